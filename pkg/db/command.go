@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// RunMigrateCommand runs "<name> migrate up | down [steps] | status", given
+// the arguments after "migrate", and returns the process exit code: 0 on
+// success, 1 when migrating fails and 2 on a usage error
+func RunMigrateCommand(args []string, migrator *Migrator, name string) int {
+	return runMigrateCommand(context.Background(), args, migrator, name, os.Stdout, os.Stderr)
+}
+
+func runMigrateCommand(ctx context.Context, args []string, migrator *Migrator, name string, stdout, stderr io.Writer) int {
+	usage := func() int {
+		fmt.Fprintf(stderr, "usage: %s migrate up | down [steps] | status\n", name)
+		return 2
+	}
+	if len(args) == 0 || len(args) > 2 || (args[0] != "down" && len(args) > 1) {
+		return usage()
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Fprintf(stdout, "applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "migration failed: %v\n", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "schema is up to date")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			var err error
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return usage()
+			}
+		}
+
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, migration := range rolledBack {
+			fmt.Fprintf(stdout, "rolled back %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "rollback failed: %v\n", err)
+			return 1
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to read migration status: %v\n", err)
+			return 1
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(stdout, "%04d_%s\t%s\n", status.Version, status.Name, state)
+		}
+
+	default:
+		return usage()
+	}
+
+	return 0
+}
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunMigrateCommand_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"no subcommand", nil},
+		{"unknown subcommand", []string{"sideways"}},
+		{"steps that are not a number", []string{"down", "two"}},
+		{"zero steps", []string{"down", "0"}},
+		{"arguments to up", []string{"up", "1"}},
+		{"extra arguments", []string{"down", "1", "2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should print usage for "+tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			// A usage error returns before the migrator is touched
+			code := runMigrateCommand(context.Background(), tt.args, nil, "order-service", &stdout, &stderr)
+
+			if code != 2 {
+				t.Errorf("Expected exit code 2, got %d", code)
+			}
+			if !strings.HasPrefix(stderr.String(), "usage: order-service migrate") {
+				t.Errorf("Expected usage on stderr, got %q", stderr.String())
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("Expected nothing on stdout, got %q", stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package db
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+var (
+	ErrInvalidMigrationName = errors.New("invalid migration file name")
+	ErrDuplicateMigration   = errors.New("duplicate migration version")
+	ErrMissingUpMigration   = errors.New("migration has no up script")
+	ErrMissingDownMigration = errors.New("migration has no down script")
+	ErrChecksumMismatch     = errors.New("applied migration checksum does not match its file")
+	ErrUnknownMigration     = errors.New("database has a migration that is not in the source")
+)
+
+// migrationFileName matches "<version>_<name>.up.sql" and "<version>_<name>.down.sql"
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change. Checksum covers both scripts,
+// so editing either after the migration is applied is detected.
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+// LoadMigrations reads migrations from dir in fsys, typically an embed.FS.
+// Files must be named "<version>_<name>.up.sql" with an optional matching
+// ".down.sql"; the result is sorted by version.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory %q: %w", dir, err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidMigrationName, entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, exists := byVersion[version]
+		if !exists {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d (%s and %s)", ErrDuplicateMigration, version, migration.Name, match[2])
+		}
+
+		switch match[3] {
+		case "up":
+			migration.UpSQL = string(content)
+		case "down":
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("%w: %d_%s", ErrMissingUpMigration, migration.Version, migration.Name)
+		}
+		migration.Checksum = checksum(migration.UpSQL, migration.DownSQL)
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// checksum hashes the up and down scripts, separated by a NUL byte so moving
+// text from one script to the other changes the sum
+func checksum(upSQL, downSQL string) string {
+	hash := sha256.New()
+	hash.Write([]byte(upSQL))
+	hash.Write([]byte{0})
+	hash.Write([]byte(downSQL))
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	t.Run("should pair scripts and sort by version", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"migrations/0002_add_index.up.sql":      {Data: []byte("CREATE INDEX i ON t (c);")},
+			"migrations/0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c INT);")},
+			"migrations/0001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+		}
+
+		migrations, err := LoadMigrations(fsys, "migrations")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(migrations) != 2 {
+			t.Fatalf("Expected 2 migrations, got %d", len(migrations))
+		}
+
+		first, second := migrations[0], migrations[1]
+		if first.Version != 1 || first.Name != "create_table" {
+			t.Errorf("Expected 1_create_table first, got %d_%s", first.Version, first.Name)
+		}
+		if first.DownSQL != "DROP TABLE t;" {
+			t.Errorf("Expected down script to be loaded, got %q", first.DownSQL)
+		}
+		if second.Version != 2 || second.DownSQL != "" {
+			t.Errorf("Expected 2_add_index without down script, got %d_%s %q", second.Version, second.Name, second.DownSQL)
+		}
+		if first.Checksum == "" || first.Checksum == second.Checksum {
+			t.Errorf("Expected distinct checksums, got %q and %q", first.Checksum, second.Checksum)
+		}
+	})
+
+	t.Run("should change the checksum when only the down script changes", func(t *testing.T) {
+		load := func(downSQL string) Migration {
+			migrations, err := LoadMigrations(fstest.MapFS{
+				"migrations/0001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (c INT);")},
+				"migrations/0001_create_table.down.sql": {Data: []byte(downSQL)},
+			}, "migrations")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			return migrations[0]
+		}
+
+		if load("DROP TABLE t;").Checksum == load("DROP TABLE IF EXISTS t;").Checksum {
+			t.Error("Expected an edited down script to change the checksum")
+		}
+		if checksum("a", "b") == checksum("ab", "") {
+			t.Error("Expected the checksum to separate the scripts")
+		}
+	})
+
+	tests := []struct {
+		name        string
+		files       fstest.MapFS
+		expectedErr error
+	}{
+		{"invalid file name", fstest.MapFS{"migrations/create_table.sql": {}}, ErrInvalidMigrationName},
+		{"duplicate version", fstest.MapFS{
+			"migrations/0001_a.up.sql": {Data: []byte("SELECT 1;")},
+			"migrations/0001_b.up.sql": {Data: []byte("SELECT 1;")},
+		}, ErrDuplicateMigration},
+		{"down without up", fstest.MapFS{"migrations/0001_a.down.sql": {Data: []byte("SELECT 1;")}}, ErrMissingUpMigration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadMigrations(tt.files, "migrations")
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
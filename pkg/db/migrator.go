@@ -0,0 +1,265 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"time"
+)
+
+const DefaultMigrationsTable = "schema_migrations"
+
+var tableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// MigratorConfig configures a Migrator
+type MigratorConfig struct {
+	// TableName records applied versions; defaults to DefaultMigrationsTable
+	TableName string
+	// LockID is the Postgres advisory lock key held while migrating, so that
+	// replicas starting together apply migrations one at a time. Services
+	// sharing a database must use different IDs.
+	LockID int64
+}
+
+// MigrationStatus reports whether a known migration has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// queryer runs statements on the pool or on a dedicated connection
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type appliedMigration struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Migrator applies and rolls back migrations against Postgres
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+	config     MigratorConfig
+}
+
+// NewMigratorFS creates a Migrator for the migrations LoadMigrations finds in
+// dir of fsys, normally an embed.FS of the service's scripts
+func NewMigratorFS(db *sql.DB, fsys fs.FS, dir string, config MigratorConfig) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(db, migrations, config)
+}
+
+// NewMigrator creates a Migrator for migrations, which must be sorted by version
+func NewMigrator(db *sql.DB, migrations []Migration, config MigratorConfig) (*Migrator, error) {
+	if config.TableName == "" {
+		config.TableName = DefaultMigrationsTable
+	}
+	if !tableName.MatchString(config.TableName) {
+		return nil, fmt.Errorf("invalid migrations table name %q", config.TableName)
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		config:     config,
+	}, nil
+}
+
+// Up applies every pending migration in version order and returns the ones applied
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.verify(ctx, conn, true)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, exists := done[migration.Version]; exists {
+				continue
+			}
+
+			err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.UpSQL); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx,
+					`INSERT INTO `+m.config.TableName+` (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)`,
+					migration.Version, migration.Name, migration.Checksum, time.Now(),
+				)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the latest steps applied migrations and returns them, newest first
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var rolledBack []Migration
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		done, err := m.verify(ctx, conn, true)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+			migration := m.migrations[i]
+			if _, exists := done[migration.Version]; !exists {
+				continue
+			}
+			if migration.DownSQL == "" {
+				return fmt.Errorf("%w: %d_%s", ErrMissingDownMigration, migration.Version, migration.Name)
+			}
+
+			err := m.inTx(ctx, conn, func(tx *sql.Tx) error {
+				if _, err := tx.ExecContext(ctx, migration.DownSQL); err != nil {
+					return err
+				}
+				_, err := tx.ExecContext(ctx, `DELETE FROM `+m.config.TableName+` WHERE version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("rollback of %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+			rolledBack = append(rolledBack, migration)
+		}
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// Status lists every known migration and whether it has been applied. It
+// only reads, without the migration lock, so it does not wait for a running
+// Up or Down and may observe one part-way.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	done, err := m.verify(ctx, m.db, false)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if applied, exists := done[migration.Version]; exists {
+			status.Applied = true
+			status.AppliedAt = applied.appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// verify checks applied migrations against the source, returning them keyed
+// by version. With write set, which requires the migration lock, it creates
+// the version table if needed; otherwise a missing table means nothing is applied.
+func (m *Migrator) verify(ctx context.Context, q queryer, write bool) (map[int64]appliedMigration, error) {
+	if write {
+		_, err := q.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+m.config.TableName+` (
+			version BIGINT PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create migrations table: %w", err)
+		}
+	} else {
+		var exists bool
+		if err := q.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, m.config.TableName).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to look up migrations table: %w", err)
+		}
+		if !exists {
+			return map[int64]appliedMigration{}, nil
+		}
+	}
+
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM `+m.config.TableName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	done := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var applied appliedMigration
+		if err := rows.Scan(&applied.version, &applied.name, &applied.checksum, &applied.appliedAt); err != nil {
+			return nil, err
+		}
+		done[applied.version] = applied
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, applied := range done {
+		migration, exists := known[version]
+		if !exists {
+			return nil, fmt.Errorf("%w: %d_%s", ErrUnknownMigration, version, applied.name)
+		}
+		if applied.checksum != migration.Checksum {
+			return nil, fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, version, migration.Name)
+		}
+	}
+
+	return done, nil
+}
+
+// withLock runs fn on a dedicated connection holding the advisory lock.
+// Session-level advisory locks belong to a connection, so every statement
+// must go through conn rather than the pool.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, m.config.LockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, m.config.LockID)
+
+	return fn(conn)
+}
+
+func (m *Migrator) inTx(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
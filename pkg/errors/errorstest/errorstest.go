@@ -0,0 +1,43 @@
+// Package errorstest provides checks for services' error catalogs in tests
+package errorstest
+
+import (
+	"sort"
+	"testing"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+// RequireTranslations fails t for every response code that a shipped locale
+// cannot translate, counting translations its fallback chain provides, and
+// for every translated code the catalog does not have. Response codes are
+// the catalog's plus the ones HTTPErrorHandler adds itself.
+func RequireTranslations(t testing.TB, catalog *pkgErrors.Registry, translations *pkgErrors.Translations) {
+	t.Helper()
+
+	codes := append(catalog.Codes(), pkgErrors.ValidationFailed)
+	known := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		known[code] = true
+	}
+
+	for _, locale := range translations.Locales() {
+		localizer := translations.Negotiate(locale)
+		for _, code := range codes {
+			if localizer.Message(code, "") == "" {
+				t.Errorf("Locale %s has no translation for %s", locale, code)
+			}
+		}
+
+		var unknown []string
+		for code := range translations.Bundle(locale) {
+			if !known[code] {
+				unknown = append(unknown, code)
+			}
+		}
+		sort.Strings(unknown)
+		for _, code := range unknown {
+			t.Errorf("Locale %s translates %s, which is not in the catalog", locale, code)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package errorstest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+// recorder collects the failures RequireTranslations reports
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestRequireTranslations(t *testing.T) {
+	catalog := pkgErrors.NewRegistry(
+		pkgErrors.Entry{Code: "SYSTEM_INTERNAL_ERROR", Message: "An unexpected error occurred", Meta: pkgErrors.Internal},
+		pkgErrors.Entry{Err: fmt.Errorf("not found"), Code: "THING_NOT_FOUND", Message: "Thing not found", Meta: pkgErrors.NotFound},
+		pkgErrors.Entry{Err: fmt.Errorf("busy"), Code: "THING_BUSY", Message: "Thing is busy", Meta: pkgErrors.Meta{
+			Status: http.StatusTooManyRequests, GRPC: pkgErrors.GRPCResourceExhausted, LogLevel: pkgErrors.LogInfo,
+		}},
+	)
+	complete := map[string]string{
+		"SYSTEM_INTERNAL_ERROR":    "Error inesperado",
+		"THING_NOT_FOUND":          "Cosa no encontrada",
+		"THING_BUSY":               "Cosa ocupada",
+		pkgErrors.ValidationFailed: "Campos no válidos",
+	}
+
+	t.Run("should pass when every locale translates every code", func(t *testing.T) {
+		translations := pkgErrors.NewTranslations("en")
+		translations.Add("es", complete)
+		// es-MX only overrides; the rest comes from es
+		translations.Add("es-MX", map[string]string{"THING_BUSY": "Cosa ocupadísima"})
+
+		rec := &recorder{TB: t}
+		RequireTranslations(rec, catalog, translations)
+		if len(rec.failures) != 0 {
+			t.Errorf("Expected no failures, got %v", rec.failures)
+		}
+	})
+
+	t.Run("should fail for missing and unknown codes", func(t *testing.T) {
+		translations := pkgErrors.NewTranslations("en")
+		translations.Add("es", complete)
+		translations.Add("fr", map[string]string{
+			"SYSTEM_INTERNAL_ERROR":    "Erreur inattendue",
+			"THING_NOT_FOUND":          "Chose introuvable",
+			pkgErrors.ValidationFailed: "Champs invalides",
+			"THING_GONE":               "Chose disparue",
+		})
+
+		rec := &recorder{TB: t}
+		RequireTranslations(rec, catalog, translations)
+		want := []string{
+			"Locale fr has no translation for THING_BUSY",
+			"Locale fr translates THING_GONE, which is not in the catalog",
+		}
+		if fmt.Sprint(rec.failures) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, rec.failures)
+		}
+	})
+}
@@ -1,52 +1,89 @@
 package errors
 
 import (
-	"encoding/json"
+	stdErrors "errors"
 	"net/http"
 
 	"github.com/sirupsen/logrus"
 )
 
+// Error codes shared by every service
+const (
+	AuthUnauthenticated = "AUTH_UNAUTHENTICATED"
+	AuthForbidden       = "AUTH_FORBIDDEN"
+)
+
 // ErrorInfo represents error information for API responses
 type ErrorInfo struct {
-	Code    string `json:"error_code"`
-	Message string `json:"error_message"`
+	Code    string           `json:"error_code"`
+	Message string           `json:"error_message"`
+	Errors  []FieldViolation `json:"errors,omitempty"`
 }
 
-// ErrorCatalog interface that each service should implement
+// ErrorCatalog resolves errors to the entries they are reported with; services use a Registry
 type ErrorCatalog interface {
-	GetErrorInfo(err error) ErrorInfo
-	IsValidationError(err error) bool
-	IsDatabaseError(err error) bool
+	Resolve(err error) Entry
 }
 
-// HTTPErrorHandler handles HTTP error responses with standardized format
+// HTTPErrorHandler handles HTTP error responses with standardized format.
+// Responses are ErrorInfo JSON unless the client's Accept header prefers
+// RFC 7807 problem details, see WriteResponse. With translations, messages
+// are answered in the language negotiated from Accept-Language and logged
+// in the catalog's.
 type HTTPErrorHandler struct {
-	logger  *logrus.Logger
-	catalog ErrorCatalog
+	logger          *logrus.Logger
+	catalog         ErrorCatalog
+	problemTypeBase string
+	translations    *Translations
 }
 
 // NewHTTPErrorHandler creates a new HTTP error handler
 func NewHTTPErrorHandler(logger *logrus.Logger, catalog ErrorCatalog) *HTTPErrorHandler {
 	return &HTTPErrorHandler{
-		logger:  logger,
-		catalog: catalog,
+		logger:          logger,
+		catalog:         catalog,
+		problemTypeBase: DefaultProblemTypeBase,
 	}
 }
 
-// HandleError processes an error and sends appropriate HTTP response
+// WithProblemTypeBase sets the URI that prefixes problem types, for example a
+// documentation page whose anchors are the kebab-cased error codes
+func (h *HTTPErrorHandler) WithProblemTypeBase(base string) *HTTPErrorHandler {
+	h.problemTypeBase = base
+	return h
+}
+
+// WithTranslations answers with messages from the bundle of the client's
+// preferred language, see Translations.Negotiate
+func (h *HTTPErrorHandler) WithTranslations(translations *Translations) *HTTPErrorHandler {
+	h.translations = translations
+	return h
+}
+
+// HandleError processes an error and sends appropriate HTTP response, with
+// the status and log level declared by the error's catalog entry
 func (h *HTTPErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, err error) {
-	errorInfo := h.catalog.GetErrorInfo(err)
-	statusCode := h.getHTTPStatusCode(err)
+	entry := h.catalog.Resolve(err)
+	errorInfo := ErrorInfo{Code: entry.Code, Message: entry.Message}
+	
+	// Field errors are reported together, each with its own catalog code
+	var validation *ValidationErrors
+	if stdErrors.As(err, &validation) {
+		entry = Entry{Code: ValidationFailed, Message: "One or more fields are invalid", Meta: BadRequest}
+		errorInfo = ErrorInfo{Code: entry.Code, Message: entry.Message, Errors: violations(h.catalog, validation)}
+	}
+	statusCode := entry.Status
 	
 	// Log the error with context
 	logEntry := h.logger.WithFields(logrus.Fields{
-		"error_code":    errorInfo.Code,
-		"error_message": errorInfo.Message,
-		"status_code":   statusCode,
-		"method":        r.Method,
-		"path":          r.URL.Path,
-		"user_agent":    r.Header.Get("User-Agent"),
+		"error_code":     errorInfo.Code,
+		"error_message":  errorInfo.Message,
+		"status_code":    statusCode,
+		"retryable":      entry.Retryable,
+		"invalid_fields": len(errorInfo.Errors),
+		"method":         r.Method,
+		"path":           r.URL.Path,
+		"user_agent":     r.Header.Get("User-Agent"),
 	})
 	
 	// Add request ID if available
@@ -54,23 +91,34 @@ func (h *HTTPErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, e
 		logEntry = logEntry.WithField("request_id", requestID)
 	}
 	
-	// Log with appropriate level based on error type
+	// The wrapped error keeps the context callers added with %w
+	if err != nil {
+		logEntry = logEntry.WithError(err)
+	}
+	
+	var message string
 	switch {
-	case h.catalog.IsValidationError(err):
-		logEntry.Warning("Validation error occurred")
-	case h.catalog.IsDatabaseError(err):
-		logEntry.Error("Database error occurred")
+	case entry.Kind == KindValidation:
+		message = "Validation error occurred"
+	case entry.Kind == KindDatabase:
+		message = "Database error occurred"
 	case statusCode >= 500:
-		logEntry.Error("Internal server error occurred")
+		message = "Internal server error occurred"
 	default:
-		logEntry.Info("Request completed with error")
+		message = "Request completed with error"
 	}
+	logEntry.Log(entry.LogLevel.logrusLevel(), message)
 	
-	// Send standardized error response
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+	// Typed errors may add extension members to problem details
+	var extensions map[string]any
+	var extender ProblemExtender
+	if stdErrors.As(err, &extender) {
+		extensions = extender.ProblemExtensions()
+	}
 	
-	if encodeErr := json.NewEncoder(w).Encode(errorInfo); encodeErr != nil {
+	// Send standardized error response in the client's language
+	response := Response{Status: statusCode, Info: errorInfo, Retryable: entry.Retryable, Extensions: extensions}
+	if encodeErr := h.write(w, r, response); encodeErr != nil {
 		h.logger.WithFields(logrus.Fields{
 			"original_error": err.Error(),
 			"encode_error":   encodeErr.Error(),
@@ -78,40 +126,6 @@ func (h *HTTPErrorHandler) HandleError(w http.ResponseWriter, r *http.Request, e
 	}
 }
 
-// getHTTPStatusCode maps domain errors to HTTP status codes
-// This uses a generic approach that services can override if needed
-func (h *HTTPErrorHandler) getHTTPStatusCode(err error) int {
-	// Check if it's a validation error
-	if h.catalog.IsValidationError(err) {
-		return http.StatusBadRequest
-	}
-	
-	// Check if it's a database error
-	if h.catalog.IsDatabaseError(err) {
-		return http.StatusInternalServerError
-	}
-	
-	// Get error info to determine status code based on error code pattern
-	errorInfo := h.catalog.GetErrorInfo(err)
-	
-	switch {
-	case contains(errorInfo.Code, "NOT_FOUND"):
-		return http.StatusNotFound
-	case contains(errorInfo.Code, "ALREADY_EXISTS"):
-		return http.StatusConflict
-	case contains(errorInfo.Code, "TIMEOUT"):
-		return http.StatusRequestTimeout
-	case contains(errorInfo.Code, "SERVICE_UNAVAILABLE"):
-		return http.StatusServiceUnavailable
-	case contains(errorInfo.Code, "VALIDATION_"):
-		return http.StatusBadRequest
-	case contains(errorInfo.Code, "DATABASE_"):
-		return http.StatusInternalServerError
-	default:
-		return http.StatusInternalServerError
-	}
-}
-
 // HandleValidationError is a convenience method for validation errors
 func (h *HTTPErrorHandler) HandleValidationError(w http.ResponseWriter, r *http.Request, message string) {
 	if message != "" {
@@ -135,9 +149,7 @@ func (h *HTTPErrorHandler) HandleValidationError(w http.ResponseWriter, r *http.
 		
 		logEntry.Warning("Validation error occurred")
 		
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(errorInfo)
+		h.write(w, r, Response{Status: http.StatusBadRequest, Info: errorInfo})
 		return
 	}
 	
@@ -147,9 +159,7 @@ func (h *HTTPErrorHandler) HandleValidationError(w http.ResponseWriter, r *http.
 		Message: "Invalid request format",
 	}
 	
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusBadRequest)
-	json.NewEncoder(w).Encode(errorInfo)
+	h.write(w, r, Response{Status: http.StatusBadRequest, Info: errorInfo})
 }
 
 // HandleInternalError is a convenience method for internal server errors
@@ -173,23 +183,13 @@ func (h *HTTPErrorHandler) HandleInternalError(w http.ResponseWriter, r *http.Re
 		Message: "An internal error occurred",
 	}
 	
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusInternalServerError)
-	json.NewEncoder(w).Encode(errorInfo)
+	h.write(w, r, Response{Status: http.StatusInternalServerError, Info: errorInfo})
 }
 
-// Helper function to check if a string contains a substring
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && s[:len(substr)] == substr || 
-		   len(s) > len(substr) && s[len(s)-len(substr):] == substr ||
-		   (len(s) > len(substr) && findSubstring(s, substr))
-}
-
-func findSubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+// write answers with resp in the client's language
+func (h *HTTPErrorHandler) write(w http.ResponseWriter, r *http.Request, resp Response) error {
+	localizer := h.translations.Negotiate(r.Header.Get("Accept-Language"))
+	resp.Info = localizer.localize(resp.Info)
+	resp.Locale = localizer.Locale()
+	return writeResponse(w, r, resp, h.problemTypeBase)
 }
@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// GRPCCode is a gRPC status code. The values match google.golang.org/grpc/codes,
+// so a gRPC transport converts one with codes.Code(c).
+type GRPCCode uint32
+
+const (
+	GRPCOK GRPCCode = iota
+	GRPCCanceled
+	GRPCUnknown
+	GRPCInvalidArgument
+	GRPCDeadlineExceeded
+	GRPCNotFound
+	GRPCAlreadyExists
+	GRPCPermissionDenied
+	GRPCResourceExhausted
+	GRPCFailedPrecondition
+	GRPCAborted
+	GRPCOutOfRange
+	GRPCUnimplemented
+	GRPCInternal
+	GRPCUnavailable
+	GRPCDataLoss
+	GRPCUnauthenticated
+)
+
+var grpcCodeNames = [...]string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded", "NotFound",
+	"AlreadyExists", "PermissionDenied", "ResourceExhausted", "FailedPrecondition", "Aborted",
+	"OutOfRange", "Unimplemented", "Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+func (c GRPCCode) String() string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "Code(" + strconv.FormatUint(uint64(c), 10) + ")"
+}
+
+// LogLevel is the level HandleError logs an error at; the zero value is unset
+type LogLevel uint8
+
+const (
+	LogDebug LogLevel = iota + 1
+	LogInfo
+	LogWarning
+	LogError
+)
+
+func (l LogLevel) logrusLevel() logrus.Level {
+	switch l {
+	case LogDebug:
+		return logrus.DebugLevel
+	case LogInfo:
+		return logrus.InfoLevel
+	case LogWarning:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+// Meta is how an error is reported, besides its code and message. Status,
+// GRPC and LogLevel are required; Registry.Validate rejects entries without them.
+type Meta struct {
+	Kind Kind
+	// Status is the HTTP status the error is answered with
+	Status int
+	// GRPC is the status code for gRPC transports
+	GRPC GRPCCode
+	// Retryable means the same request may succeed when repeated later
+	Retryable bool
+	LogLevel  LogLevel
+}
+
+// Metadata shared by most catalog entries; entries that differ declare their own Meta
+var (
+	BadRequest         = Meta{Kind: KindValidation, Status: http.StatusBadRequest, GRPC: GRPCInvalidArgument, LogLevel: LogWarning}
+	Unauthorized       = Meta{Status: http.StatusUnauthorized, GRPC: GRPCUnauthenticated, LogLevel: LogInfo}
+	Forbidden          = Meta{Status: http.StatusForbidden, GRPC: GRPCPermissionDenied, LogLevel: LogInfo}
+	NotFound           = Meta{Status: http.StatusNotFound, GRPC: GRPCNotFound, LogLevel: LogInfo}
+	Conflict           = Meta{Status: http.StatusConflict, GRPC: GRPCAlreadyExists, LogLevel: LogInfo}
+	FailedPrecondition = Meta{Status: http.StatusConflict, GRPC: GRPCFailedPrecondition, LogLevel: LogInfo}
+	Timeout            = Meta{Status: http.StatusRequestTimeout, GRPC: GRPCDeadlineExceeded, Retryable: true, LogLevel: LogError}
+	Unavailable        = Meta{Status: http.StatusServiceUnavailable, GRPC: GRPCUnavailable, Retryable: true, LogLevel: LogError}
+	DatabaseFailure    = Meta{Kind: KindDatabase, Status: http.StatusInternalServerError, GRPC: GRPCInternal, LogLevel: LogError}
+	Internal           = Meta{Status: http.StatusInternalServerError, GRPC: GRPCInternal, LogLevel: LogError}
+)
@@ -0,0 +1,144 @@
+package errors
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ProblemContentType is the RFC 7807 media type, sent to clients whose Accept header prefers it
+	ProblemContentType = "application/problem+json"
+	// DefaultProblemTypeBase prefixes the kebab-cased error code to form a problem's type URI
+	DefaultProblemTypeBase = "urn:godops:problem:"
+)
+
+// Problem is an RFC 7807 problem details object. The error code and
+// retryability are always added as the error_code and retryable extension
+// members; Extensions holds any others.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	RequestID  string
+	Code       string
+	Retryable  bool
+	Extensions map[string]any
+}
+
+func (p Problem) MarshalJSON() ([]byte, error) {
+	members := make(map[string]any, len(p.Extensions)+8)
+	for name, value := range p.Extensions {
+		members[name] = value
+	}
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+	members["error_code"] = p.Code
+	members["retryable"] = p.Retryable
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	}
+	if p.RequestID != "" {
+		members["request_id"] = p.RequestID
+	}
+	return json.Marshal(members)
+}
+
+// ProblemExtender is implemented by errors that add extension members to
+// their problem details, such as the values a typed error carries
+type ProblemExtender interface {
+	ProblemExtensions() map[string]any
+}
+
+// Response is an error response before it is encoded in the format the client asked for.
+// Locale, when set, is the language of its messages.
+type Response struct {
+	Status     int
+	Info       ErrorInfo
+	Retryable  bool
+	Extensions map[string]any
+	Locale     string
+}
+
+// WriteResponse answers with resp as RFC 7807 problem details when the
+// request's Accept header prefers application/problem+json, and as an
+// ErrorInfo JSON body otherwise
+func WriteResponse(w http.ResponseWriter, r *http.Request, resp Response) error {
+	return writeResponse(w, r, resp, DefaultProblemTypeBase)
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, resp Response, typeBase string) error {
+	w.Header().Add("Vary", "Accept")
+	if resp.Locale != "" {
+		w.Header().Add("Vary", "Accept-Language")
+		w.Header().Set("Content-Language", resp.Locale)
+	}
+
+	if !PrefersProblem(r.Header.Get("Accept")) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(resp.Status)
+		return json.NewEncoder(w).Encode(resp.Info)
+	}
+
+	problem := Problem{
+		Type:       typeBase + problemTypeName(resp.Info.Code),
+		Title:      http.StatusText(resp.Status),
+		Status:     resp.Status,
+		Detail:     resp.Info.Message,
+		Instance:   r.URL.Path,
+		RequestID:  r.Header.Get("X-Request-ID"),
+		Code:       resp.Info.Code,
+		Retryable:  resp.Retryable,
+		Extensions: resp.Extensions,
+	}
+	if len(resp.Info.Errors) > 0 {
+		problem.Extensions = make(map[string]any, len(resp.Extensions)+1)
+		for name, value := range resp.Extensions {
+			problem.Extensions[name] = value
+		}
+		problem.Extensions["errors"] = resp.Info.Errors
+	}
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(resp.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
+
+// problemTypeName turns an error code such as ORDER_NOT_FOUND into order-not-found
+func problemTypeName(code string) string {
+	return strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// PrefersProblem reports whether an Accept header asks for
+// application/problem+json at least as much as for application/json. Only
+// an explicit problem+json range counts, so clients that send */* or
+// nothing keep receiving the ErrorInfo format.
+func PrefersProblem(accept string) bool {
+	problemQ, jsonQ := 0.0, 0.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch mediaType {
+		case ProblemContentType:
+			problemQ = max(problemQ, q)
+		case "application/json", "application/*", "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
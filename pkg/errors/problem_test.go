@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPrefersProblem(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   bool
+	}{
+		{"", false},
+		{"*/*", false},
+		{"application/json", false},
+		{"application/problem+json", true},
+		{"application/problem+json, application/json", true},
+		{"application/json, application/problem+json;q=0.9", false},
+		{"application/problem+json;q=0.5, application/json;q=0.4", true},
+		{"application/problem+json;q=0", false},
+		{"text/html, application/problem+json, */*;q=0.8", true},
+		{"application/problem+json; charset=utf-8", true},
+		{"not a media type", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("should answer %v for %q", tt.want, tt.accept), func(t *testing.T) {
+			if got := PrefersProblem(tt.accept); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// extendedError adds extension members to its problem details
+type extendedError struct{}
+
+func (extendedError) Error() string        { return "thing is busy" }
+func (extendedError) Is(target error) bool { return target == errBusy }
+func (extendedError) ProblemExtensions() map[string]any {
+	return map[string]any{"busy_until": "2030-01-01T00:00:00Z", "status": 999}
+}
+
+func TestHTTPErrorHandler_Problem(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	handler := NewHTTPErrorHandler(logger, newTestRegistry())
+
+	serve := func(accept string, err error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/things/7?expand=all", nil)
+		req.Header.Set("X-Request-ID", "req-1")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler.HandleError(rec, req, err)
+		return rec
+	}
+
+	t.Run("should keep the ErrorInfo format by default", func(t *testing.T) {
+		rec := serve("", errNotFound)
+
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Expected application/json, got %s", ct)
+		}
+		var body map[string]any
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["error_code"] != "THING_NOT_FOUND" || len(body) != 2 {
+			t.Errorf("Expected an ErrorInfo body, got %v", body)
+		}
+	})
+
+	t.Run("should answer problem details when asked for", func(t *testing.T) {
+		rec := serve(ProblemContentType, fmt.Errorf("get: %w", errNotFound))
+
+		if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+			t.Errorf("Expected %s, got %s", ProblemContentType, ct)
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("Expected Vary: Accept, got %q", rec.Header().Get("Vary"))
+		}
+		var body map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		want := map[string]any{
+			"type":       "urn:godops:problem:thing-not-found",
+			"title":      "Not Found",
+			"status":     float64(http.StatusNotFound),
+			"detail":     "Thing not found",
+			"instance":   "/things/7",
+			"request_id": "req-1",
+			"error_code": "THING_NOT_FOUND",
+			"retryable":  false,
+		}
+		for name, value := range want {
+			if body[name] != value {
+				t.Errorf("Expected %s %v, got %v", name, value, body[name])
+			}
+		}
+	})
+
+	t.Run("should add extension members without overriding standard ones", func(t *testing.T) {
+		handler := NewHTTPErrorHandler(logger, newTestRegistry()).WithProblemTypeBase("https://docs.example.com/errors#")
+		req := httptest.NewRequest(http.MethodGet, "/things/7", nil)
+		req.Header.Set("Accept", ProblemContentType)
+		rec := httptest.NewRecorder()
+		handler.HandleError(rec, req, extendedError{})
+
+		var body map[string]any
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["type"] != "https://docs.example.com/errors#thing-busy" {
+			t.Errorf("Expected the configured type base, got %v", body["type"])
+		}
+		if body["busy_until"] != "2030-01-01T00:00:00Z" {
+			t.Errorf("Expected the busy_until extension, got %v", body["busy_until"])
+		}
+		if body["status"] != float64(http.StatusTooManyRequests) || body["retryable"] != true {
+			t.Errorf("Expected status 429 and retryable, got %v %v", body["status"], body["retryable"])
+		}
+	})
+
+	t.Run("should negotiate the convenience handlers too", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/things", nil)
+		req.Header.Set("Accept", ProblemContentType)
+		rec := httptest.NewRecorder()
+		handler.HandleValidationError(rec, req, "Body must be JSON")
+
+		var body map[string]any
+		json.NewDecoder(rec.Body).Decode(&body)
+		if rec.Header().Get("Content-Type") != ProblemContentType || body["detail"] != "Body must be JSON" {
+			t.Errorf("Expected a problem with the message as detail, got %v", body)
+		}
+	})
+}
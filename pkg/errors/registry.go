@@ -0,0 +1,175 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"sort"
+)
+
+// Kind classifies a catalog entry for the IsValidationError and IsDatabaseError checks
+type Kind int
+
+const (
+	KindDefault Kind = iota
+	// KindValidation entries are the client's fault, such as malformed input
+	KindValidation
+	// KindDatabase entries are storage failures
+	KindDatabase
+)
+
+// Entry declares how a domain error is reported. Err is matched with
+// errors.Is, so an error wrapped with fmt.Errorf("...: %w", Err) resolves to
+// the same entry; typed errors are declared with As instead.
+type Entry struct {
+	Err     error
+	Code    string
+	Message string
+	Meta
+
+	// match is set by As; it reports whether err holds the type and the message for it
+	match func(err error) (string, bool)
+}
+
+// As declares entry for errors of type T, matched with errors.As. The client
+// message is built by message from the matched value; entry.Err is ignored.
+func As[T error](entry Entry, message func(T) string) Entry {
+	entry.Err = nil
+	entry.match = func(err error) (string, bool) {
+		var target T
+		if !stdErrors.As(err, &target) {
+			return "", false
+		}
+		return message(target), true
+	}
+	return entry
+}
+
+func (e Entry) matches(err error) (string, bool) {
+	if e.match != nil {
+		return e.match(err)
+	}
+	return e.Message, stdErrors.Is(err, e.Err)
+}
+
+// Registry is an ErrorCatalog built from declared entries. An error resolves
+// to the first registered entry it matches, so typed errors that also match a
+// sentinel are registered before it; unmatched errors get the fallback.
+// Entries are registered at startup and the registry is read-only afterwards.
+type Registry struct {
+	fallback Entry
+	entries  []Entry
+}
+
+// NewRegistry returns a registry of entries that reports unknown errors as fallback
+func NewRegistry(fallback Entry, entries ...Entry) *Registry {
+	r := &Registry{fallback: fallback}
+	return r.Register(entries...)
+}
+
+// Register adds entries to the registry. It panics on an entry without a code
+// or error and on a sentinel registered twice, as both are programming errors.
+func (r *Registry) Register(entries ...Entry) *Registry {
+	for _, entry := range entries {
+		if entry.Code == "" || (entry.Err == nil && entry.match == nil) {
+			panic(fmt.Sprintf("errors: incomplete catalog entry %+v", entry))
+		}
+		for _, existing := range r.entries {
+			if entry.Err != nil && existing.Err == entry.Err {
+				panic(fmt.Sprintf("errors: %q is already registered as %s", entry.Err, existing.Code))
+			}
+		}
+		r.entries = append(r.entries, entry)
+	}
+	return r
+}
+
+// Validate reports every entry, fallback included, that lacks a code, an error
+// status, a gRPC code or a log level. Services call it at startup so an
+// incomplete catalog fails before it answers a request.
+func (r *Registry) Validate() error {
+	var problems []error
+	for _, entry := range append([]Entry{r.fallback}, r.entries...) {
+		if err := entry.validate(); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	return stdErrors.Join(problems...)
+}
+
+func (e Entry) validate() error {
+	var missing []string
+	if e.Code == "" {
+		missing = append(missing, "code")
+	}
+	if e.Message == "" && e.match == nil {
+		missing = append(missing, "message")
+	}
+	if e.Status < 400 || e.Status > 599 {
+		missing = append(missing, "4xx or 5xx status")
+	}
+	if e.GRPC == GRPCOK || e.GRPC > GRPCUnauthenticated {
+		missing = append(missing, "gRPC error code")
+	}
+	if e.LogLevel < LogDebug || e.LogLevel > LogError {
+		missing = append(missing, "log level")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("catalog entry %q lacks %v", e.Code, missing)
+}
+
+// Resolve returns the entry err resolves to, with the message of a typed
+// entry filled in, or the fallback
+func (r *Registry) Resolve(err error) Entry {
+	if entry, ok := r.Lookup(err); ok {
+		return entry
+	}
+	return r.fallback
+}
+
+// Lookup returns the entry err resolves to, with the message of a typed entry filled in
+func (r *Registry) Lookup(err error) (Entry, bool) {
+	if err == nil {
+		return Entry{}, false
+	}
+	for _, entry := range r.entries {
+		if message, ok := entry.matches(err); ok {
+			entry.Message = message
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Codes returns the distinct codes of the entries and the fallback, sorted
+func (r *Registry) Codes() []string {
+	seen := map[string]bool{r.fallback.Code: true}
+	codes := []string{r.fallback.Code}
+	for _, entry := range r.entries {
+		if !seen[entry.Code] {
+			seen[entry.Code] = true
+			codes = append(codes, entry.Code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// GetErrorInfo returns the code and message of the entry err resolves to
+func (r *Registry) GetErrorInfo(err error) ErrorInfo {
+	entry := r.Resolve(err)
+	return ErrorInfo{Code: entry.Code, Message: entry.Message}
+}
+
+// IsValidationError reports whether err resolves to a KindValidation entry
+func (r *Registry) IsValidationError(err error) bool {
+	entry, ok := r.Lookup(err)
+	return ok && entry.Kind == KindValidation
+}
+
+// IsDatabaseError reports whether err resolves to a KindDatabase entry
+func (r *Registry) IsDatabaseError(err error) bool {
+	entry, ok := r.Lookup(err)
+	return ok && entry.Kind == KindDatabase
+}
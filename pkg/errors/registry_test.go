@@ -0,0 +1,183 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errNotFound = stdErrors.New("thing not found")
+	errInvalid  = stdErrors.New("thing is invalid")
+	errDatabase = stdErrors.New("database failed")
+	errBusy     = stdErrors.New("thing is busy")
+)
+
+const codeTooMany = "VALIDATION_TOO_MANY_THINGS"
+
+// limitError is a typed error that also matches errInvalid
+type limitError struct {
+	Max int
+}
+
+func (e *limitError) Error() string        { return fmt.Sprintf("more than %d things", e.Max) }
+func (e *limitError) Is(target error) bool { return target == errInvalid }
+
+func newTestRegistry() *Registry {
+	return NewRegistry(Entry{Code: "SYSTEM_INTERNAL_ERROR", Message: "An unexpected error occurred", Meta: Internal},
+		As(Entry{Code: codeTooMany, Meta: BadRequest}, func(e *limitError) string {
+			return fmt.Sprintf("At most %d things are allowed", e.Max)
+		}),
+		Entry{Err: errNotFound, Code: "THING_NOT_FOUND", Message: "Thing not found", Meta: NotFound},
+		Entry{Err: errInvalid, Code: "VALIDATION_INVALID_THING", Message: "Thing is invalid", Meta: BadRequest},
+		Entry{Err: errDatabase, Code: "DATABASE_QUERY_ERROR", Message: "Database query failed", Meta: DatabaseFailure},
+		Entry{Err: errBusy, Code: "THING_BUSY", Message: "Thing is busy", Meta: Meta{
+			Status: http.StatusTooManyRequests, GRPC: GRPCResourceExhausted, Retryable: true, LogLevel: LogDebug,
+		}},
+	)
+}
+
+func TestRegistry(t *testing.T) {
+	registry := newTestRegistry()
+
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    string
+		wantMessage string
+		validation  bool
+		database    bool
+	}{
+		{"sentinel", errNotFound, "THING_NOT_FOUND", "Thing not found", false, false},
+		{"wrapped sentinel", fmt.Errorf("loading thing 7: %w", errNotFound), "THING_NOT_FOUND", "Thing not found", false, false},
+		{"doubly wrapped sentinel", fmt.Errorf("handler: %w", fmt.Errorf("repo: %w", errDatabase)), "DATABASE_QUERY_ERROR", "Database query failed", false, true},
+		{"joined errors", stdErrors.Join(stdErrors.New("other"), errInvalid), "VALIDATION_INVALID_THING", "Thing is invalid", true, false},
+		{"typed error", &limitError{Max: 3}, codeTooMany, "At most 3 things are allowed", true, false},
+		{"wrapped typed error", fmt.Errorf("create: %w", &limitError{Max: 5}), codeTooMany, "At most 5 things are allowed", true, false},
+		{"unknown error", stdErrors.New("boom"), "SYSTEM_INTERNAL_ERROR", "An unexpected error occurred", false, false},
+		{"nil", nil, "SYSTEM_INTERNAL_ERROR", "An unexpected error occurred", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run("should resolve "+tt.name, func(t *testing.T) {
+			info := registry.GetErrorInfo(tt.err)
+			if info.Code != tt.wantCode || info.Message != tt.wantMessage {
+				t.Errorf("Expected %s %q, got %s %q", tt.wantCode, tt.wantMessage, info.Code, info.Message)
+			}
+			if got := registry.IsValidationError(tt.err); got != tt.validation {
+				t.Errorf("Expected IsValidationError %v, got %v", tt.validation, got)
+			}
+			if got := registry.IsDatabaseError(tt.err); got != tt.database {
+				t.Errorf("Expected IsDatabaseError %v, got %v", tt.database, got)
+			}
+		})
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry Entry
+	}{
+		{"a duplicate sentinel", Entry{Err: errNotFound, Code: "OTHER_NOT_FOUND", Message: "Other", Meta: NotFound}},
+		{"an entry without a code", Entry{Err: stdErrors.New("new"), Message: "New", Meta: NotFound}},
+		{"an entry without an error", Entry{Code: "NEW", Message: "New", Meta: NotFound}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should panic on "+tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+			newTestRegistry().Register(tt.entry)
+		})
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	if err := newTestRegistry().Validate(); err != nil {
+		t.Fatalf("Expected a complete catalog, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		entry   Entry
+		missing string
+	}{
+		{"status", Entry{Code: "A", Message: "A", Meta: Meta{GRPC: GRPCInternal, LogLevel: LogError}}, "status"},
+		{"success status", Entry{Code: "A", Message: "A", Meta: Meta{Status: http.StatusOK, GRPC: GRPCInternal, LogLevel: LogError}}, "status"},
+		{"gRPC code", Entry{Code: "A", Message: "A", Meta: Meta{Status: http.StatusConflict, LogLevel: LogInfo}}, "gRPC"},
+		{"log level", Entry{Code: "A", Message: "A", Meta: Meta{Status: http.StatusConflict, GRPC: GRPCAborted}}, "log level"},
+		{"message", Entry{Code: "A", Meta: Conflict}, "message"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject an entry without a "+tt.name, func(t *testing.T) {
+			tt.entry.Err = stdErrors.New(tt.name)
+			err := newTestRegistry().Register(tt.entry).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.missing) {
+				t.Errorf("Expected an error naming the %s, got %v", tt.missing, err)
+			}
+		})
+	}
+
+	t.Run("should reject an incomplete fallback", func(t *testing.T) {
+		if err := NewRegistry(Entry{Code: "SYSTEM_INTERNAL_ERROR", Message: "Oops"}).Validate(); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestHTTPErrorHandler_HandleError(t *testing.T) {
+	var logs bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&logs)
+	logger.SetLevel(logrus.DebugLevel)
+	logger.SetFormatter(&logrus.JSONFormatter{})
+	handler := NewHTTPErrorHandler(logger, newTestRegistry())
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantLevel  string
+	}{
+		{"a wrapped not found error", fmt.Errorf("get: %w", errNotFound), http.StatusNotFound, "info"},
+		{"a validation error", &limitError{Max: 1}, http.StatusBadRequest, "warning"},
+		{"a database error", errDatabase, http.StatusInternalServerError, "error"},
+		{"an entry with its own metadata", errBusy, http.StatusTooManyRequests, "debug"},
+		{"an unknown error", stdErrors.New("boom"), http.StatusInternalServerError, "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should report "+tt.name, func(t *testing.T) {
+			logs.Reset()
+			rec := httptest.NewRecorder()
+			handler.HandleError(rec, httptest.NewRequest(http.MethodGet, "/things/7", nil), tt.err)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+			var info ErrorInfo
+			if err := json.NewDecoder(rec.Body).Decode(&info); err != nil || info.Code == "" {
+				t.Errorf("Expected an ErrorInfo body, got %v", err)
+			}
+			var logged map[string]any
+			if err := json.Unmarshal(logs.Bytes(), &logged); err != nil {
+				t.Fatalf("Expected one JSON log line, got %q", logs.String())
+			}
+			if logged["level"] != tt.wantLevel {
+				t.Errorf("Expected log level %s, got %v", tt.wantLevel, logged["level"])
+			}
+		})
+	}
+}
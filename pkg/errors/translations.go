@@ -0,0 +1,210 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Translations holds a message bundle per locale, mapping error codes to
+// messages. Catalog messages are in the default locale, so a bundle only
+// covers what it translates; lookups fall back from a locale to its parents
+// (pt-BR to pt) and then to the catalog message.
+type Translations struct {
+	defaultLocale string
+	bundles       map[string]map[string]string // by lower-cased locale tag
+	tags          map[string]string            // lower-cased locale tag to the tag as shipped
+}
+
+// NewTranslations creates an empty set of bundles for a catalog whose
+// messages are in defaultLocale
+func NewTranslations(defaultLocale string) *Translations {
+	return &Translations{
+		defaultLocale: defaultLocale,
+		bundles:       make(map[string]map[string]string),
+		tags:          make(map[string]string),
+	}
+}
+
+// LoadTranslations reads a bundle from every JSON file in dir of fsys, such
+// as an embed.FS. The file name is the locale tag (es.json, pt-BR.json) and
+// the file maps error codes to messages.
+func LoadTranslations(fsys fs.FS, dir, defaultLocale string) (*Translations, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	translations := NewTranslations(defaultLocale)
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("parse message bundle %s: %w", file, err)
+		}
+		translations.Add(strings.TrimSuffix(path.Base(file), ".json"), messages)
+	}
+	return translations, nil
+}
+
+// MustLoadTranslations is like LoadTranslations but panics on error, for
+// bundles embedded in the binary
+func MustLoadTranslations(fsys fs.FS, dir, defaultLocale string) *Translations {
+	translations, err := LoadTranslations(fsys, dir, defaultLocale)
+	if err != nil {
+		panic(err)
+	}
+	return translations
+}
+
+// Add registers the bundle of a locale, replacing the one it had
+func (t *Translations) Add(locale string, messages map[string]string) {
+	key := strings.ToLower(locale)
+	t.bundles[key] = messages
+	t.tags[key] = locale
+}
+
+// DefaultLocale is the locale of the catalog messages
+func (t *Translations) DefaultLocale() string {
+	return t.defaultLocale
+}
+
+// Locales returns the locales that ship a bundle, sorted
+func (t *Translations) Locales() []string {
+	locales := make([]string, 0, len(t.tags))
+	for _, tag := range t.tags {
+		locales = append(locales, tag)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Bundle returns the messages a locale ships, without those of its parents
+func (t *Translations) Bundle(locale string) map[string]string {
+	return t.bundles[strings.ToLower(locale)]
+}
+
+// Localizer translates messages into the locale negotiated for a request
+type Localizer struct {
+	locale string
+	chain  []map[string]string
+}
+
+// Locale is the tag of the language messages are in, for the
+// Content-Language header; it is empty when nothing was negotiated
+func (l Localizer) Locale() string {
+	return l.locale
+}
+
+// Message returns the translation of code, or fallback when no bundle in
+// the locale's fallback chain has one
+func (l Localizer) Message(code, fallback string) string {
+	for _, bundle := range l.chain {
+		if message, ok := bundle[code]; ok && message != "" {
+			return message
+		}
+	}
+	return fallback
+}
+
+// Negotiate picks the locale for an Accept-Language header with RFC 4647
+// lookup: language ranges are tried by descending quality, each followed by
+// its parent tags, and the default locale is used when none ships. A nil
+// Translations negotiates nothing.
+func (t *Translations) Negotiate(acceptLanguage string) Localizer {
+	if t == nil {
+		return Localizer{}
+	}
+
+	for _, languageRange := range parseAcceptLanguage(acceptLanguage) {
+		for tag := strings.ToLower(languageRange); tag != ""; tag = parentTag(tag) {
+			if tag == strings.ToLower(t.defaultLocale) {
+				return t.defaultLocalizer()
+			}
+			if _, ok := t.bundles[tag]; ok {
+				return t.localizer(tag)
+			}
+		}
+	}
+	return t.defaultLocalizer()
+}
+
+func (t *Translations) defaultLocalizer() Localizer {
+	return Localizer{locale: t.defaultLocale}
+}
+
+// localizer falls back from the shipped locale tag through its shipped parents
+func (t *Translations) localizer(tag string) Localizer {
+	localizer := Localizer{locale: t.tags[tag]}
+	for ; tag != ""; tag = parentTag(tag) {
+		if bundle, ok := t.bundles[tag]; ok {
+			localizer.chain = append(localizer.chain, bundle)
+		}
+	}
+	return localizer
+}
+
+// parentTag drops the last subtag: pt-br becomes pt, and pt becomes ""
+func parentTag(tag string) string {
+	if i := strings.LastIndex(tag, "-"); i > 0 {
+		return tag[:i]
+	}
+	return ""
+}
+
+// parseAcceptLanguage returns the language ranges of an Accept-Language
+// header by descending quality, without the ones refused with q=0 and the
+// "*" wildcard, which the default locale answers
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			ranges = append(ranges, weighted{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	tags := make([]string, len(ranges))
+	for i, r := range ranges {
+		tags[i] = r.tag
+	}
+	return tags
+}
+
+// localize translates an error response and its field errors
+func (l Localizer) localize(info ErrorInfo) ErrorInfo {
+	info.Message = l.Message(info.Code, info.Message)
+	if len(info.Errors) > 0 {
+		fields := make([]FieldViolation, len(info.Errors))
+		for i, field := range info.Errors {
+			field.Message = l.Message(field.Code, field.Message)
+			fields[i] = field
+		}
+		info.Errors = fields
+	}
+	return info
+}
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newTestTranslations() *Translations {
+	translations := NewTranslations("en")
+	translations.Add("es", map[string]string{
+		"THING_NOT_FOUND":          "Cosa no encontrada",
+		"VALIDATION_INVALID_THING": "La cosa no es válida",
+		ValidationFailed:           "Uno o más campos no son válidos",
+	})
+	translations.Add("pt", map[string]string{"THING_NOT_FOUND": "Coisa não encontrada", "THING_BUSY": "Coisa ocupada"})
+	translations.Add("pt-BR", map[string]string{"THING_NOT_FOUND": "Coisa não achada"})
+	return translations
+}
+
+func TestTranslations_Negotiate(t *testing.T) {
+	translations := newTestTranslations()
+
+	tests := []struct {
+		acceptLanguage string
+		wantLocale     string
+		wantMessage    string
+	}{
+		{"", "en", "fallback"},
+		{"*", "en", "fallback"},
+		{"es", "es", "Cosa no encontrada"},
+		{"ES-mx", "es", "Cosa no encontrada"},
+		{"fr, es;q=0.5", "es", "Cosa no encontrada"},
+		{"es;q=0.5, pt-BR", "pt-BR", "Coisa não achada"},
+		{"en-GB, es;q=0.9", "en", "fallback"},
+		{"es;q=0, de", "en", "fallback"},
+		{"es;q=bogus, pt", "pt", "Coisa não encontrada"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should negotiate "+tt.wantLocale+" for "+tt.acceptLanguage, func(t *testing.T) {
+			localizer := translations.Negotiate(tt.acceptLanguage)
+			if localizer.Locale() != tt.wantLocale {
+				t.Errorf("Expected locale %s, got %s", tt.wantLocale, localizer.Locale())
+			}
+			if got := localizer.Message("THING_NOT_FOUND", "fallback"); got != tt.wantMessage {
+				t.Errorf("Expected %q, got %q", tt.wantMessage, got)
+			}
+		})
+	}
+
+	t.Run("should fall back through parent locales to the catalog message", func(t *testing.T) {
+		localizer := translations.Negotiate("pt-BR")
+		if got := localizer.Message("THING_BUSY", "Thing is busy"); got != "Coisa ocupada" {
+			t.Errorf("Expected the pt message, got %q", got)
+		}
+		if got := localizer.Message("DATABASE_QUERY_ERROR", "Database query failed"); got != "Database query failed" {
+			t.Errorf("Expected the catalog message, got %q", got)
+		}
+	})
+
+	t.Run("should negotiate nothing without translations", func(t *testing.T) {
+		var none *Translations
+		localizer := none.Negotiate("es")
+		if localizer.Locale() != "" || localizer.Message("THING_NOT_FOUND", "fallback") != "fallback" {
+			t.Errorf("Expected no locale and the catalog message, got %q", localizer.Locale())
+		}
+	})
+}
+
+func TestLoadTranslations(t *testing.T) {
+	t.Run("should load a bundle per file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/es.json":    {Data: []byte(`{"THING_NOT_FOUND": "Cosa no encontrada"}`)},
+			"locales/pt-BR.json": {Data: []byte(`{"THING_NOT_FOUND": "Coisa não achada"}`)},
+			"locales/README.md":  {Data: []byte("not a bundle")},
+		}
+
+		translations, err := LoadTranslations(fsys, "locales", "en")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if locales := translations.Locales(); len(locales) != 2 || locales[0] != "es" || locales[1] != "pt-BR" {
+			t.Errorf("Expected [es pt-BR], got %v", locales)
+		}
+		if got := translations.Negotiate("pt-br").Locale(); got != "pt-BR" {
+			t.Errorf("Expected the locale tag as shipped, got %s", got)
+		}
+	})
+
+	t.Run("should reject a malformed bundle", func(t *testing.T) {
+		fsys := fstest.MapFS{"locales/es.json": {Data: []byte(`{"THING_NOT_FOUND": 1}`)}}
+
+		if _, err := LoadTranslations(fsys, "locales", "en"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestHTTPErrorHandler_Translations(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	handler := NewHTTPErrorHandler(logger, newTestRegistry()).WithTranslations(newTestTranslations())
+
+	serve := func(acceptLanguage string, err error) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/things/7", nil)
+		req.Header.Set("Accept-Language", acceptLanguage)
+		rec := httptest.NewRecorder()
+		handler.HandleError(rec, req, err)
+		return rec
+	}
+
+	t.Run("should answer in the negotiated language", func(t *testing.T) {
+		rec := serve("es-ES, en;q=0.5", errNotFound)
+
+		var info ErrorInfo
+		json.NewDecoder(rec.Body).Decode(&info)
+		if info.Code != "THING_NOT_FOUND" || info.Message != "Cosa no encontrada" {
+			t.Errorf("Expected the Spanish message, got %+v", info)
+		}
+		if rec.Header().Get("Content-Language") != "es" {
+			t.Errorf("Expected Content-Language es, got %q", rec.Header().Get("Content-Language"))
+		}
+		if vary := rec.Header().Values("Vary"); len(vary) != 2 || vary[1] != "Accept-Language" {
+			t.Errorf("Expected Vary: Accept, Accept-Language, got %v", vary)
+		}
+	})
+
+	t.Run("should translate field errors", func(t *testing.T) {
+		var v ValidationErrors
+		v.Add("/thing", errInvalid)
+		v.Add("/other", errDatabase)
+		rec := serve("es", v.Err())
+
+		var info ErrorInfo
+		json.NewDecoder(rec.Body).Decode(&info)
+		if info.Message != "Uno o más campos no son válidos" || len(info.Errors) != 2 {
+			t.Fatalf("Expected the Spanish summary and 2 fields, got %+v", info)
+		}
+		if info.Errors[0].Message != "La cosa no es válida" {
+			t.Errorf("Expected the Spanish field message, got %q", info.Errors[0].Message)
+		}
+		if info.Errors[1].Message != "Database query failed" {
+			t.Errorf("Expected the catalog message for an untranslated code, got %q", info.Errors[1].Message)
+		}
+	})
+
+	t.Run("should answer in the default language otherwise", func(t *testing.T) {
+		rec := serve("de", errNotFound)
+
+		var info ErrorInfo
+		json.NewDecoder(rec.Body).Decode(&info)
+		if info.Message != "Thing not found" || rec.Header().Get("Content-Language") != "en" {
+			t.Errorf("Expected the English message, got %q in %q", info.Message, rec.Header().Get("Content-Language"))
+		}
+	})
+}
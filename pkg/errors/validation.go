@@ -0,0 +1,100 @@
+package errors
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"strings"
+)
+
+// ValidationFailed is the error code of a response listing every invalid field of a request
+const ValidationFailed = "VALIDATION_FAILED"
+
+// FieldError is a catalog error reported for one field of a request.
+// Pointer is an RFC 6901 JSON pointer into the request body, such as
+// /items/2/price.
+type FieldError struct {
+	Pointer string
+	Err     error
+}
+
+// ValidationErrors collects the field errors of a request, so clients can
+// fix every invalid field in one round-trip. It matches each of the errors
+// it holds with errors.Is.
+type ValidationErrors struct {
+	Fields []FieldError
+}
+
+// Add records err for the field at pointer; a nil err is ignored
+func (v *ValidationErrors) Add(pointer string, err error) {
+	if err == nil {
+		return
+	}
+	v.Fields = append(v.Fields, FieldError{Pointer: pointer, Err: err})
+}
+
+// Merge records err below prefix. The field errors of a *ValidationErrors
+// keep their own pointers under prefix; any other error is recorded for
+// prefix itself.
+func (v *ValidationErrors) Merge(prefix string, err error) {
+	var nested *ValidationErrors
+	if stdErrors.As(err, &nested) {
+		for _, field := range nested.Fields {
+			v.Add(prefix+field.Pointer, field.Err)
+		}
+		return
+	}
+	v.Add(prefix, err)
+}
+
+// Err returns v when it holds field errors and nil otherwise
+func (v *ValidationErrors) Err() error {
+	if v == nil || len(v.Fields) == 0 {
+		return nil
+	}
+	return v
+}
+
+func (v *ValidationErrors) Error() string {
+	parts := make([]string, len(v.Fields))
+	for i, field := range v.Fields {
+		parts[i] = field.Pointer + ": " + field.Err.Error()
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (v *ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(v.Fields))
+	for i, field := range v.Fields {
+		errs[i] = field.Err
+	}
+	return errs
+}
+
+// Pointer builds a JSON pointer from reference tokens, escaping "~" and "/":
+// Pointer("items", 2, "price") is "/items/2/price"
+func Pointer(tokens ...any) string {
+	var b strings.Builder
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(escaper.Replace(fmt.Sprint(token)))
+	}
+	return b.String()
+}
+
+// FieldViolation reports one invalid field in the errors array of an error response
+type FieldViolation struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"error_code"`
+	Message string `json:"error_message"`
+}
+
+// violations resolves every field error against catalog
+func violations(catalog ErrorCatalog, v *ValidationErrors) []FieldViolation {
+	fields := make([]FieldViolation, len(v.Fields))
+	for i, field := range v.Fields {
+		entry := catalog.Resolve(field.Err)
+		fields[i] = FieldViolation{Pointer: field.Pointer, Code: entry.Code, Message: entry.Message}
+	}
+	return fields
+}
@@ -0,0 +1,138 @@
+package errors
+
+import (
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestPointer(t *testing.T) {
+	tests := []struct {
+		tokens []any
+		want   string
+	}{
+		{nil, ""},
+		{[]any{"items", 2, "price"}, "/items/2/price"},
+		{[]any{"a/b", "m~n"}, "/a~1b/m~0n"},
+	}
+
+	for _, tt := range tests {
+		t.Run("should build "+tt.want, func(t *testing.T) {
+			if got := Pointer(tt.tokens...); got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidationErrors(t *testing.T) {
+	t.Run("should be nil without field errors", func(t *testing.T) {
+		var v ValidationErrors
+		v.Add("/name", nil)
+		if err := v.Err(); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+	})
+
+	t.Run("should nest merged field errors under the prefix", func(t *testing.T) {
+		var address ValidationErrors
+		address.Add("/city", errInvalid)
+		address.Add("/country", errNotFound)
+
+		var v ValidationErrors
+		v.Add("/user_id", errInvalid)
+		v.Merge("/address", fmt.Errorf("address: %w", address.Err()))
+		v.Merge("/coupon", errBusy)
+
+		want := []string{"/user_id", "/address/city", "/address/country", "/coupon"}
+		if len(v.Fields) != len(want) {
+			t.Fatalf("Expected %d field errors, got %v", len(want), v.Fields)
+		}
+		for i, pointer := range want {
+			if v.Fields[i].Pointer != pointer {
+				t.Errorf("Expected pointer %s at %d, got %s", pointer, i, v.Fields[i].Pointer)
+			}
+		}
+	})
+
+	t.Run("should match every field error", func(t *testing.T) {
+		var v ValidationErrors
+		v.Add("/a", errInvalid)
+		v.Add("/b", &limitError{Max: 3})
+		err := fmt.Errorf("create: %w", v.Err())
+
+		if !stdErrors.Is(err, errInvalid) {
+			t.Error("Expected the errors to match errInvalid")
+		}
+		var limit *limitError
+		if !stdErrors.As(err, &limit) || limit.Max != 3 {
+			t.Errorf("Expected the errors to hold the limit error, got %v", limit)
+		}
+		if stdErrors.Is(err, errNotFound) {
+			t.Error("Expected the errors not to match errNotFound")
+		}
+	})
+}
+
+func TestHTTPErrorHandler_ValidationErrors(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	handler := NewHTTPErrorHandler(logger, newTestRegistry())
+
+	var v ValidationErrors
+	v.Add(Pointer("items", 2, "price"), errInvalid)
+	v.Add(Pointer("items", 3, "quantity"), &limitError{Max: 5})
+	err := fmt.Errorf("create thing: %w", v.Err())
+
+	want := []FieldViolation{
+		{Pointer: "/items/2/price", Code: "VALIDATION_INVALID_THING", Message: "Thing is invalid"},
+		{Pointer: "/items/3/quantity", Code: codeTooMany, Message: "At most 5 things are allowed"},
+	}
+
+	t.Run("should list every field in the errors array", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/things", nil)
+		rec := httptest.NewRecorder()
+		handler.HandleError(rec, req, err)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+		var info ErrorInfo
+		if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if info.Code != ValidationFailed {
+			t.Errorf("Expected %s, got %s", ValidationFailed, info.Code)
+		}
+		if fmt.Sprint(info.Errors) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, info.Errors)
+		}
+	})
+
+	t.Run("should add the errors array to problem details", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/things", nil)
+		req.Header.Set("Accept", ProblemContentType)
+		rec := httptest.NewRecorder()
+		handler.HandleError(rec, req, err)
+
+		var body struct {
+			Status int              `json:"status"`
+			Code   string           `json:"error_code"`
+			Errors []FieldViolation `json:"errors"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("Failed to decode problem: %v", err)
+		}
+		if body.Status != http.StatusBadRequest || body.Code != ValidationFailed {
+			t.Errorf("Expected 400 %s, got %d %s", ValidationFailed, body.Status, body.Code)
+		}
+		if fmt.Sprint(body.Errors) != fmt.Sprint(want) {
+			t.Errorf("Expected %v, got %v", want, body.Errors)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrTransportClosed = errors.New("event transport is closed")
+
+// Handler processes one event. Returning an error asks the transport to
+// redeliver it; wrap the error with Permanent to skip further attempts.
+type Handler func(ctx context.Context, envelope Envelope) error
+
+// Subscription is a registered consumer
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Transport carries events from publishers to consumer groups. Every group
+// subscribed to a topic receives each event published to it, and within a group
+// each event goes to a single member. Delivery is at least once.
+// Implementations adapt a broker; MemoryTransport serves tests and single-binary setups.
+type Transport interface {
+	Publish(ctx context.Context, topic string, envelope Envelope) error
+	Subscribe(topic, group string, handler Handler) (Subscription, error)
+	Close() error
+}
+
+// Bus publishes events to topics named after their type and subscribes handlers to them
+type Bus struct {
+	transport Transport
+}
+
+func NewBus(transport Transport) *Bus {
+	return &Bus{transport: transport}
+}
+
+// Publish sends envelope to the topic named by its type. Bus implements
+// Publisher, so a Relay can feed it from an outbox.
+func (b *Bus) Publish(ctx context.Context, envelope Envelope) error {
+	if envelope.Type == "" {
+		return fmt.Errorf("cannot publish event %s without a type", envelope.ID)
+	}
+	return b.transport.Publish(ctx, envelope.Type, envelope)
+}
+
+// Subscribe registers handler as a member of group on topic
+func (b *Bus) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	if topic == "" || group == "" {
+		return nil, errors.New("topic and consumer group are required")
+	}
+	return b.transport.Subscribe(topic, group, handler)
+}
+
+// Close stops the underlying transport
+func (b *Bus) Close() error {
+	return b.transport.Close()
+}
+
+// Subscribe registers a handler that receives the payload decoded as T.
+// Payloads that cannot be decoded are rejected as permanent failures.
+func Subscribe[T any](bus *Bus, topic, group string, handler func(ctx context.Context, envelope Envelope, payload T) error) (Subscription, error) {
+	return bus.Subscribe(topic, group, TypedHandler(handler))
+}
+
+// TypedHandler adapts a handler taking a decoded payload to Handler
+func TypedHandler[T any](handler func(ctx context.Context, envelope Envelope, payload T) error) Handler {
+	return func(ctx context.Context, envelope Envelope) error {
+		var payload T
+		if err := envelope.Decode(&payload); err != nil {
+			return Permanent(err)
+		}
+		return handler(ctx, envelope, payload)
+	}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks a handler error as not worth retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
@@ -0,0 +1,311 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTimeout = 2 * time.Second
+
+func newTestBus(t *testing.T, config MemoryTransportConfig) *Bus {
+	t.Helper()
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Millisecond
+	}
+	bus := NewBus(NewMemoryTransport(config))
+	t.Cleanup(func() { bus.Close() })
+	return bus
+}
+
+func waitFor(t *testing.T, received <-chan string) string {
+	t.Helper()
+	select {
+	case id := <-received:
+		return id
+	case <-time.After(testTimeout):
+		t.Fatal("Timed out waiting for an event")
+		return ""
+	}
+}
+
+func expectNothing(t *testing.T, received <-chan string) {
+	t.Helper()
+	select {
+	case id := <-received:
+		t.Errorf("Expected no further delivery, got %s", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBus_Delivery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should deliver each event to every group once", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+
+		billing := make(chan string, 10)
+		notifications := make(chan string, 10)
+		for i := 0; i < 2; i++ {
+			if _, err := bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+				billing <- envelope.AggregateID
+				return nil
+			}); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if _, err := bus.Subscribe(OrderCreatedEvent, "notifications", func(ctx context.Context, envelope Envelope) error {
+			notifications <- envelope.AggregateID
+			return nil
+		}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := bus.Publish(ctx, newTestEnvelope(t, "order-1")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if id := waitFor(t, billing); id != "order-1" {
+			t.Errorf("Expected order-1 in billing, got %s", id)
+		}
+		if id := waitFor(t, notifications); id != "order-1" {
+			t.Errorf("Expected order-1 in notifications, got %s", id)
+		}
+		expectNothing(t, billing)
+	})
+
+	t.Run("should ignore other topics", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+
+		received := make(chan string, 10)
+		bus.Subscribe("order.cancelled", "billing", func(ctx context.Context, envelope Envelope) error {
+			received <- envelope.AggregateID
+			return nil
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		expectNothing(t, received)
+	})
+
+	t.Run("should redeliver until the handler succeeds", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{MaxAttempts: 3})
+
+		var mutex sync.Mutex
+		attempts := 0
+		received := make(chan string, 10)
+		bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			attempts++
+			if attempts < 3 {
+				return errors.New("downstream unavailable")
+			}
+			received <- envelope.AggregateID
+			return nil
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		waitFor(t, received)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("should dead-letter after the last attempt", func(t *testing.T) {
+		deadLetters := make(chan string, 10)
+		bus := newTestBus(t, MemoryTransportConfig{
+			MaxAttempts: 2,
+			OnDeadLetter: func(topic, group string, envelope Envelope, err error) {
+				deadLetters <- group + ":" + envelope.AggregateID
+			},
+		})
+
+		bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			return errors.New("downstream unavailable")
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		if got := waitFor(t, deadLetters); got != "billing:order-1" {
+			t.Errorf("Expected billing:order-1, got %s", got)
+		}
+	})
+
+	t.Run("should not retry permanent failures", func(t *testing.T) {
+		deadLetters := make(chan string, 10)
+		bus := newTestBus(t, MemoryTransportConfig{
+			OnDeadLetter: func(topic, group string, envelope Envelope, err error) {
+				deadLetters <- envelope.AggregateID
+			},
+		})
+
+		var mutex sync.Mutex
+		attempts := 0
+		bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			attempts++
+			return Permanent(errors.New("unknown customer"))
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		waitFor(t, deadLetters)
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if attempts != 1 {
+			t.Errorf("Expected 1 attempt, got %d", attempts)
+		}
+	})
+
+	t.Run("should recover from panicking handlers", func(t *testing.T) {
+		deadLetters := make(chan string, 10)
+		bus := newTestBus(t, MemoryTransportConfig{
+			MaxAttempts: 1,
+			OnDeadLetter: func(topic, group string, envelope Envelope, err error) {
+				deadLetters <- envelope.AggregateID
+			},
+		})
+
+		bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			panic("boom")
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		waitFor(t, deadLetters)
+	})
+}
+
+func TestBus_TypedSubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should decode the payload", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+
+		received := make(chan string, 10)
+		Subscribe(bus, OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope, payload OrderCreated) error {
+			received <- payload.OrderID
+			return nil
+		})
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+		if id := waitFor(t, received); id != "order-1" {
+			t.Errorf("Expected order-1, got %s", id)
+		}
+	})
+
+	t.Run("should dead-letter payloads that cannot be decoded", func(t *testing.T) {
+		deadLetters := make(chan string, 10)
+		bus := newTestBus(t, MemoryTransportConfig{
+			OnDeadLetter: func(topic, group string, envelope Envelope, err error) {
+				if IsPermanent(err) {
+					deadLetters <- envelope.ID
+				}
+			},
+		})
+
+		Subscribe(bus, OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope, payload OrderCreated) error {
+			return nil
+		})
+
+		envelope := newTestEnvelope(t, "order-1")
+		envelope.Payload = []byte(`"not an object"`)
+		bus.Publish(ctx, envelope)
+		if id := waitFor(t, deadLetters); id != envelope.ID {
+			t.Errorf("Expected %s, got %s", envelope.ID, id)
+		}
+	})
+}
+
+func TestBus_Close(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should reject publishing and subscribing once closed", func(t *testing.T) {
+		bus := NewBus(NewMemoryTransport(MemoryTransportConfig{}))
+		if err := bus.Close(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if err := bus.Publish(ctx, newTestEnvelope(t, "order-1")); !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+		if _, err := bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			return nil
+		}); !errors.Is(err, ErrTransportClosed) {
+			t.Errorf("Expected ErrTransportClosed, got %v", err)
+		}
+	})
+
+	t.Run("should release a publish blocked on a full queue", func(t *testing.T) {
+		bus := NewBus(NewMemoryTransport(MemoryTransportConfig{QueueSize: 1}))
+		subscription, _ := bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			return nil
+		})
+		subscription.Unsubscribe()
+		// Let the consumer goroutine observe the stop signal
+		time.Sleep(20 * time.Millisecond)
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+
+		published := make(chan error, 1)
+		go func() { published <- bus.Publish(ctx, newTestEnvelope(t, "order-2")) }()
+		time.Sleep(20 * time.Millisecond)
+
+		closed := make(chan error, 1)
+		go func() { closed <- bus.Close() }()
+		select {
+		case err := <-closed:
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		case <-time.After(testTimeout):
+			t.Fatal("Timed out waiting for Close")
+		}
+		select {
+		case err := <-published:
+			if !errors.Is(err, ErrTransportClosed) {
+				t.Errorf("Expected ErrTransportClosed, got %v", err)
+			}
+		case <-time.After(testTimeout):
+			t.Fatal("Timed out waiting for Publish")
+		}
+	})
+
+	t.Run("should require a topic and group", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+		if _, err := bus.Subscribe(OrderCreatedEvent, "", func(ctx context.Context, envelope Envelope) error {
+			return nil
+		}); err == nil {
+			t.Error("Expected an error for a missing group")
+		}
+	})
+}
+
+func TestBus_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should keep queueing for a group with no members", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+
+		first, _ := bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			return nil
+		})
+		first.Unsubscribe()
+		// Let the consumer goroutine observe the stop signal
+		time.Sleep(20 * time.Millisecond)
+
+		bus.Publish(ctx, newTestEnvelope(t, "order-1"))
+
+		received := make(chan string, 10)
+		bus.Subscribe(OrderCreatedEvent, "billing", func(ctx context.Context, envelope Envelope) error {
+			received <- envelope.AggregateID
+			return nil
+		})
+		if id := waitFor(t, received); id != "order-1" {
+			t.Errorf("Expected order-1, got %s", id)
+		}
+	})
+}
@@ -0,0 +1,155 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the
+	// timestamp, a dot and the body, keyed with the secret shared by
+	// HTTPForwarder and HTTPReceiver
+	SignatureHeader = "X-Event-Signature"
+	// TimestampHeader carries the Unix time the event was signed at
+	TimestampHeader = "X-Event-Timestamp"
+	// MaxSignatureAge bounds how far a signature's timestamp may be from the
+	// receiver's clock, so a captured request cannot be replayed later
+	MaxSignatureAge = 5 * time.Minute
+)
+
+// maxEventSize bounds the body HTTPReceiver accepts
+const maxEventSize = 1 << 20
+
+// HTTPForwarder returns a handler that POSTs each event as JSON to url, letting
+// another service's bus consume it through HTTPReceiver, and signs it with
+// secret. A 2xx response acknowledges the event; other 4xx responses except
+// 408 and 429 are permanent failures, everything else is retried. Wrapped in
+// PublisherFunc and fed by a Relay, an event leaves the outbox only once the
+// other service acknowledged it.
+func HTTPForwarder(client *http.Client, url string, secret []byte) Handler {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context, envelope Envelope) error {
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			return Permanent(err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return Permanent(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(sign(secret, timestamp, body)))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		io.Copy(io.Discard, resp.Body)
+
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return nil
+		case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests:
+			return fmt.Errorf("forwarding event %s to %s: %s", envelope.ID, url, resp.Status)
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			return Permanent(fmt.Errorf("forwarding event %s to %s: %s", envelope.ID, url, resp.Status))
+		default:
+			return fmt.Errorf("forwarding event %s to %s: %s", envelope.ID, url, resp.Status)
+		}
+	}
+}
+
+// HTTPReceiver accepts events POSTed by HTTPForwarder and publishes them to
+// publisher, answering 202 only once Publish returned. A Bus returns as soon as
+// the event is queued in memory; to acknowledge an event only once it has been
+// processed, pass the processing Handler wrapped in PublisherFunc. A Permanent
+// failure is answered 422, so the forwarder gives up on the event, and any
+// other 503, so it is sent again. Requests without a signature made with
+// secret within MaxSignatureAge are answered 401; with an empty secret every
+// request is.
+func HTTPReceiver(publisher Publisher, secret []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxEventSize))
+		if err != nil {
+			http.Error(w, "invalid event envelope", http.StatusBadRequest)
+			return
+		}
+		if !verify(secret, r.Header, body, time.Now()) {
+			http.Error(w, "invalid event signature", http.StatusUnauthorized)
+			return
+		}
+
+		var envelope Envelope
+		if err := json.Unmarshal(body, &envelope); err != nil {
+			http.Error(w, "invalid event envelope", http.StatusBadRequest)
+			return
+		}
+		if envelope.ID == "" || envelope.Type == "" {
+			http.Error(w, "event id and type are required", http.StatusBadRequest)
+			return
+		}
+
+		if err := publisher.Publish(r.Context(), envelope); err != nil {
+			if IsPermanent(err) {
+				http.Error(w, "event rejected", http.StatusUnprocessableEntity)
+				return
+			}
+			http.Error(w, "event could not be handled", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func sign(secret []byte, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// verify checks the signature headers of a request against its body
+func verify(secret []byte, header http.Header, body []byte, now time.Time) bool {
+	if len(secret) == 0 {
+		return false
+	}
+
+	timestamp := header.Get(TimestampHeader)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(signedAt, 0)); age > MaxSignatureAge || age < -MaxSignatureAge {
+		return false
+	}
+
+	signature, err := hex.DecodeString(strings.TrimPrefix(header.Get(SignatureHeader), "sha256="))
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signature, sign(secret, timestamp, body))
+}
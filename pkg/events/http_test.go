@@ -0,0 +1,143 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("shared-secret")
+
+func TestHTTPForwarder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should publish forwarded events on the receiving bus", func(t *testing.T) {
+		bus := newTestBus(t, MemoryTransportConfig{})
+		received := make(chan string, 10)
+		bus.Subscribe(OrderCreatedEvent, "notifications", func(ctx context.Context, envelope Envelope) error {
+			received <- envelope.AggregateID
+			return nil
+		})
+
+		server := httptest.NewServer(HTTPReceiver(bus, testSecret))
+		defer server.Close()
+
+		if err := HTTPForwarder(server.Client(), server.URL, testSecret)(ctx, newTestEnvelope(t, "order-1")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if id := waitFor(t, received); id != "order-1" {
+			t.Errorf("Expected order-1, got %s", id)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		status        int
+		wantPermanent bool
+	}{
+		{"client error is permanent", http.StatusBadRequest, true},
+		{"rate limiting is retried", http.StatusTooManyRequests, false},
+		{"server error is retried", http.StatusServiceUnavailable, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := HTTPForwarder(server.Client(), server.URL, testSecret)(ctx, newTestEnvelope(t, "order-1"))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("Expected permanent %v, got %v", tt.wantPermanent, IsPermanent(err))
+			}
+		})
+	}
+}
+
+func TestHTTPReceiver(t *testing.T) {
+	bus := newTestBus(t, MemoryTransportConfig{})
+	body, err := json.Marshal(newTestEnvelope(t, "order-1"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-MaxSignatureAge-time.Minute).Unix(), 10)
+	signature := func(secret []byte, timestamp string, body []byte) string {
+		return "sha256=" + hex.EncodeToString(sign(secret, timestamp, body))
+	}
+
+	tests := []struct {
+		name       string
+		secret     []byte
+		body       []byte
+		timestamp  string
+		signature  string
+		wantStatus int
+	}{
+		{"signed event", testSecret, body, now, signature(testSecret, now, body), http.StatusAccepted},
+		{"missing signature", testSecret, body, now, "", http.StatusUnauthorized},
+		{"signature with another secret", testSecret, body, now, signature([]byte("other"), now, body), http.StatusUnauthorized},
+		{"tampered body", testSecret, bytes.Replace(body, []byte("order-1"), []byte("order-2"), 1), now, signature(testSecret, now, body), http.StatusUnauthorized},
+		{"stale timestamp", testSecret, body, stale, signature(testSecret, stale, body), http.StatusUnauthorized},
+		{"receiver without a secret", nil, body, now, signature(nil, now, body), http.StatusUnauthorized},
+		{"signed empty body", testSecret, nil, now, signature(testSecret, now, nil), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run("should answer "+strconv.Itoa(tt.wantStatus)+" for "+tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(tt.body))
+			req.Header.Set(TimestampHeader, tt.timestamp)
+			if tt.signature != "" {
+				req.Header.Set(SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			HTTPReceiver(bus, tt.secret).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("Expected %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+		})
+	}
+
+	handled := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"handled event", nil, http.StatusAccepted},
+		{"rejected event", Permanent(errors.New("no template")), http.StatusUnprocessableEntity},
+		{"failed event", errors.New("channel down"), http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range handled {
+		t.Run("should answer "+strconv.Itoa(tt.wantStatus)+" for a "+tt.name, func(t *testing.T) {
+			var calls int
+			receiver := HTTPReceiver(PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+				calls++
+				return tt.err
+			}), testSecret)
+
+			req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(body))
+			req.Header.Set(TimestampHeader, now)
+			req.Header.Set(SignatureHeader, signature(testSecret, now, body))
+			rec := httptest.NewRecorder()
+			receiver.ServeHTTP(rec, req)
+
+			// The answer waits for the handler, so a 202 means the event was handled
+			if calls != 1 || rec.Code != tt.wantStatus {
+				t.Errorf("Expected %d after one call, got %d after %d: %s", tt.wantStatus, rec.Code, calls, rec.Body.String())
+			}
+		})
+	}
+}
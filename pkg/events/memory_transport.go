@@ -0,0 +1,203 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxAttempts  = 5
+	DefaultRetryBackoff = 100 * time.Millisecond
+	DefaultQueueSize    = 1024
+)
+
+// MemoryTransportConfig configures a MemoryTransport
+type MemoryTransportConfig struct {
+	// MaxAttempts is how many times an event is handed to a group before it is dead-lettered
+	MaxAttempts int
+	// RetryBackoff is the wait before the first redelivery; it doubles on each attempt
+	RetryBackoff time.Duration
+	// QueueSize bounds each group's backlog; Publish blocks while a queue is full
+	QueueSize int
+	// OnDeadLetter, when set, receives events a group gave up on
+	OnDeadLetter func(topic, group string, envelope Envelope, err error)
+}
+
+// MemoryTransport delivers events between goroutines of one process. Each
+// consumer group has its own queue shared by its members, so a group keeps
+// receiving events while at least one member is subscribed and buffers them
+// while it has none. Queued events are lost when the process exits.
+type MemoryTransport struct {
+	config MemoryTransportConfig
+	topics map[string]map[string]*memoryGroup
+	closed bool
+	done   chan struct{}
+	ctx    context.Context // passed to handlers, cancelled by Close
+	cancel context.CancelFunc
+	mutex  sync.RWMutex
+	wg     sync.WaitGroup
+}
+
+type memoryGroup struct {
+	queue chan Envelope
+}
+
+type memorySubscription struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+func NewMemoryTransport(config MemoryTransportConfig) *MemoryTransport {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DefaultMaxAttempts
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &MemoryTransport{
+		config: config,
+		topics: make(map[string]map[string]*memoryGroup),
+		done:   make(chan struct{}),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Publish queues envelope for every group subscribed to topic. Events published
+// to a topic before any group subscribes are dropped. The groups are read
+// under the lock but queued to after releasing it, so a Publish waiting on a
+// full queue does not hold up Subscribe or Close.
+func (t *MemoryTransport) Publish(ctx context.Context, topic string, envelope Envelope) error {
+	t.mutex.RLock()
+	if t.closed {
+		t.mutex.RUnlock()
+		return ErrTransportClosed
+	}
+	queues := make([]chan Envelope, 0, len(t.topics[topic]))
+	for _, group := range t.topics[topic] {
+		queues = append(queues, group.queue)
+	}
+	t.mutex.RUnlock()
+
+	for _, queue := range queues {
+		select {
+		case queue <- envelope:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.done:
+			return ErrTransportClosed
+		}
+	}
+	return nil
+}
+
+func (t *MemoryTransport) Subscribe(topic, group string, handler Handler) (Subscription, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.closed {
+		return nil, ErrTransportClosed
+	}
+
+	groups, exists := t.topics[topic]
+	if !exists {
+		groups = make(map[string]*memoryGroup)
+		t.topics[topic] = groups
+	}
+	g, exists := groups[group]
+	if !exists {
+		g = &memoryGroup{queue: make(chan Envelope, t.config.QueueSize)}
+		groups[group] = g
+	}
+
+	subscription := &memorySubscription{stop: make(chan struct{})}
+	t.wg.Add(1)
+	go t.consume(topic, group, g, handler, subscription.stop)
+
+	return subscription, nil
+}
+
+// Close stops all consumers, waiting for in-flight handlers to return
+func (t *MemoryTransport) Close() error {
+	t.mutex.Lock()
+	if t.closed {
+		t.mutex.Unlock()
+		return nil
+	}
+	t.closed = true
+	close(t.done)
+	t.cancel()
+	t.mutex.Unlock()
+
+	t.wg.Wait()
+	return nil
+}
+
+func (s *memorySubscription) Unsubscribe() error {
+	s.once.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (t *MemoryTransport) consume(topic, group string, g *memoryGroup, handler Handler, stop chan struct{}) {
+	defer t.wg.Done()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-t.done:
+			return
+		case envelope := <-g.queue:
+			t.deliver(topic, group, g, envelope, handler, stop)
+		}
+	}
+}
+
+// deliver hands envelope to handler until it succeeds, fails permanently or runs out of attempts
+func (t *MemoryTransport) deliver(topic, group string, g *memoryGroup, envelope Envelope, handler Handler, stop chan struct{}) {
+	backoff := t.config.RetryBackoff
+	var err error
+
+	for attempt := 1; attempt <= t.config.MaxAttempts; attempt++ {
+		if err = t.handle(handler, envelope); err == nil {
+			return
+		}
+		if IsPermanent(err) || attempt == t.config.MaxAttempts {
+			break
+		}
+
+		select {
+		case <-t.done:
+			return
+		case <-stop:
+			// Hand the event back so another member of the group retries it
+			select {
+			case g.queue <- envelope:
+			case <-t.done:
+			}
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	if t.config.OnDeadLetter != nil {
+		t.config.OnDeadLetter(topic, group, envelope, err)
+	}
+}
+
+func (t *MemoryTransport) handle(handler Handler, envelope Envelope) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("event handler panicked: %v", recovered)
+		}
+	}()
+	return handler(t.ctx, envelope)
+}
@@ -0,0 +1,44 @@
+package events
+
+import "time"
+
+// Order event types, shared by the order service and its consumers
+const (
+	OrderAggregate = "order"
+
+	OrderCreatedEvent       = "order.created"
+	OrderStatusChangedEvent = "order.status_changed"
+)
+
+// OrderCreated is the payload of OrderCreatedEvent. Amounts are in minor units of Currency.
+type OrderCreated struct {
+	OrderID         string          `json:"order_id"`
+	UserID          string          `json:"user_id"`
+	Items           []OrderLineItem `json:"items"`
+	CouponCode      string          `json:"coupon_code,omitempty"`
+	SubtotalAmount  int64           `json:"subtotal_amount"`
+	DiscountAmount  int64           `json:"discount_amount"`
+	TotalAmount     int64           `json:"total_amount"`
+	Currency        string          `json:"currency"`
+	ShippingCountry string          `json:"shipping_country"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// OrderLineItem is one item of an order event
+type OrderLineItem struct {
+	ProductID       string `json:"product_id"`
+	Quantity        int    `json:"quantity"`
+	UnitPriceAmount int64  `json:"unit_price_amount"`
+}
+
+// OrderStatusChanged is the payload of OrderStatusChangedEvent. Statuses use the
+// order service's names, e.g. CONFIRMED or SHIPPED.
+type OrderStatusChanged struct {
+	OrderID        string    `json:"order_id"`
+	UserID         string    `json:"user_id"`
+	PreviousStatus string    `json:"previous_status"`
+	Status         string    `json:"status"`
+	TotalAmount    int64     `json:"total_amount"`
+	Currency       string    `json:"currency"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Outbox holds events that were committed together with the state change that
+// produced them and have not yet been handed to a Publisher.
+// Writing to the outbox is store specific, since it must share the caller's
+// transaction; see MemoryOutbox.Append and PostgresOutbox.Insert.
+type Outbox interface {
+	// Claim passes up to limit undispatched events, oldest first, to dispatch
+	// and marks the IDs it returns as dispatched. While one claim is running a
+	// concurrent one, such as another replica's relay, receives no events, so
+	// each event is handed out once and in order.
+	Claim(ctx context.Context, limit int, dispatch func(pending []Envelope) []string) error
+}
+
+// Publisher delivers events to subscribers
+type Publisher interface {
+	Publish(ctx context.Context, envelope Envelope) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(ctx context.Context, envelope Envelope) error
+
+func (f PublisherFunc) Publish(ctx context.Context, envelope Envelope) error {
+	return f(ctx, envelope)
+}
+
+type outboxEntry struct {
+	envelope     Envelope
+	dispatchedAt time.Time
+}
+
+// MemoryOutbox is an in-process Outbox for memory storage and tests.
+// Callers that need atomicity append under the same lock as their own state change.
+type MemoryOutbox struct {
+	entries  []*outboxEntry
+	mutex    sync.RWMutex
+	claiming sync.Mutex
+}
+
+func NewMemoryOutbox() *MemoryOutbox {
+	return &MemoryOutbox{}
+}
+
+// Append adds events to the outbox
+func (o *MemoryOutbox) Append(envelopes ...Envelope) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, envelope := range envelopes {
+		o.entries = append(o.entries, &outboxEntry{envelope: envelope})
+	}
+}
+
+// Claim holds the outbox for dispatch; a concurrent Claim returns without
+// dispatching anything
+func (o *MemoryOutbox) Claim(ctx context.Context, limit int, dispatch func(pending []Envelope) []string) error {
+	if !o.claiming.TryLock() {
+		return nil
+	}
+	defer o.claiming.Unlock()
+
+	pending, err := o.Pending(ctx, limit)
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	return o.MarkDispatched(ctx, dispatch(pending), time.Now())
+}
+
+// Pending returns up to limit undispatched events, oldest first
+func (o *MemoryOutbox) Pending(ctx context.Context, limit int) ([]Envelope, error) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	pending := make([]Envelope, 0)
+	for _, entry := range o.entries {
+		if limit > 0 && len(pending) == limit {
+			break
+		}
+		if entry.dispatchedAt.IsZero() {
+			pending = append(pending, entry.envelope)
+		}
+	}
+	return pending, nil
+}
+
+// MarkDispatched records that the events with the given IDs were published
+func (o *MemoryOutbox) MarkDispatched(ctx context.Context, ids []string, at time.Time) error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	marked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		marked[id] = true
+	}
+	for _, entry := range o.entries {
+		if marked[entry.envelope.ID] && entry.dispatchedAt.IsZero() {
+			entry.dispatchedAt = at
+		}
+	}
+	return nil
+}
+
+// All returns every event in the outbox, dispatched or not, oldest first
+func (o *MemoryOutbox) All() []Envelope {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	all := make([]Envelope, 0, len(o.entries))
+	for _, entry := range o.entries {
+		all = append(all, entry.envelope)
+	}
+	return all
+}
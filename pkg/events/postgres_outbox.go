@@ -0,0 +1,159 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultOutboxTable is the table PostgresOutbox uses unless told otherwise
+const DefaultOutboxTable = "outbox"
+
+var outboxTableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Execer is satisfied by *sql.DB, *sql.Tx and *sql.Conn
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// PostgresOutbox stores events in a table created by the owning service's migrations:
+//
+//	CREATE TABLE outbox (
+//	    position BIGSERIAL PRIMARY KEY,
+//	    id TEXT NOT NULL UNIQUE,
+//	    event_type TEXT NOT NULL,
+//	    aggregate_type TEXT NOT NULL,
+//	    aggregate_id TEXT NOT NULL,
+//	    payload JSONB NOT NULL,
+//	    occurred_at TIMESTAMPTZ NOT NULL,
+//	    dispatched_at TIMESTAMPTZ
+//	);
+type PostgresOutbox struct {
+	db    *sql.DB
+	table string
+}
+
+func NewPostgresOutbox(db *sql.DB, table string) (*PostgresOutbox, error) {
+	if table == "" {
+		table = DefaultOutboxTable
+	}
+	if !outboxTableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid outbox table name %q", table)
+	}
+	return &PostgresOutbox{db: db, table: table}, nil
+}
+
+// Insert writes events through exec, normally the transaction that stores the
+// state change they describe, so both commit or roll back together
+func (o *PostgresOutbox) Insert(ctx context.Context, exec Execer, envelopes ...Envelope) error {
+	for _, envelope := range envelopes {
+		_, err := exec.ExecContext(ctx,
+			`INSERT INTO `+o.table+` (id, event_type, aggregate_type, aggregate_id, payload, occurred_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			envelope.ID,
+			envelope.Type,
+			envelope.AggregateType,
+			envelope.AggregateID,
+			[]byte(envelope.Payload),
+			envelope.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert %s event into outbox: %w", envelope.Type, err)
+		}
+	}
+	return nil
+}
+
+// Claim locks the batch in a transaction that stays open while dispatch
+// publishes it, so replicas running a relay never publish the same events.
+// Rows are taken FOR UPDATE SKIP LOCKED, and a transaction-level advisory lock
+// on the table lets one claim run at a time: skipping the locked batch alone
+// would let another replica publish newer events before it.
+func (o *PostgresOutbox) Claim(ctx context.Context, limit int, dispatch func(pending []Envelope) []string) error {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var claimed bool
+	if err := tx.QueryRowContext(ctx, `SELECT pg_try_advisory_xact_lock(hashtext($1))`, "outbox:"+o.table).Scan(&claimed); err != nil {
+		return err
+	}
+	if !claimed {
+		return nil
+	}
+
+	pending, err := o.pending(ctx, tx, limit, " FOR UPDATE SKIP LOCKED")
+	if err != nil || len(pending) == 0 {
+		return err
+	}
+	if err := o.markDispatched(ctx, tx, dispatch(pending), time.Now()); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Pending returns up to limit undispatched events, oldest first, without claiming them
+func (o *PostgresOutbox) Pending(ctx context.Context, limit int) ([]Envelope, error) {
+	return o.pending(ctx, o.db, limit, "")
+}
+
+// MarkDispatched records that the events with the given IDs were published
+func (o *PostgresOutbox) MarkDispatched(ctx context.Context, ids []string, at time.Time) error {
+	return o.markDispatched(ctx, o.db, ids, at)
+}
+
+type outboxQueryer interface {
+	Execer
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func (o *PostgresOutbox) pending(ctx context.Context, q outboxQueryer, limit int, lock string) ([]Envelope, error) {
+	rows, err := q.QueryContext(ctx,
+		`SELECT id, event_type, aggregate_type, aggregate_id, payload, occurred_at
+		FROM `+o.table+`
+		WHERE dispatched_at IS NULL
+		ORDER BY position
+		LIMIT $1`+lock, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := make([]Envelope, 0)
+	for rows.Next() {
+		var envelope Envelope
+		var payload []byte
+		if err := rows.Scan(&envelope.ID, &envelope.Type, &envelope.AggregateType, &envelope.AggregateID, &payload, &envelope.OccurredAt); err != nil {
+			return nil, err
+		}
+		envelope.Payload = payload
+		pending = append(pending, envelope)
+	}
+
+	return pending, rows.Err()
+}
+
+func (o *PostgresOutbox) markDispatched(ctx context.Context, exec Execer, ids []string, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := []interface{}{at}
+	placeholders := make([]string, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+	}
+
+	_, err := exec.ExecContext(ctx,
+		`UPDATE `+o.table+` SET dispatched_at = $1
+		WHERE dispatched_at IS NULL AND id IN (`+strings.Join(placeholders, ", ")+`)`,
+		args...,
+	)
+	return err
+}
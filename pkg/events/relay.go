@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultRelayInterval  = time.Second
+	DefaultRelayBatchSize = 100
+)
+
+// RelayConfig configures a Relay
+type RelayConfig struct {
+	// Interval between polls when the outbox is drained
+	Interval time.Duration
+	// BatchSize is the most events read from the outbox per poll
+	BatchSize int
+	// OnError, when set, receives failures from Run, which otherwise retries silently
+	OnError func(err error)
+	// OnDeadLetter, when set, receives events the publisher rejected permanently
+	OnDeadLetter func(envelope Envelope, err error)
+}
+
+// Relay moves events from an Outbox to a Publisher. An event is marked
+// dispatched only once Publish returned, so the publisher decides what
+// acknowledges it; an event that was published but not marked dispatched
+// (e.g. after a crash) is published again, so delivery is at least once.
+type Relay struct {
+	outbox    Outbox
+	publisher Publisher
+	config    RelayConfig
+}
+
+func NewRelay(outbox Outbox, publisher Publisher, config RelayConfig) *Relay {
+	if config.Interval <= 0 {
+		config.Interval = DefaultRelayInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultRelayBatchSize
+	}
+
+	return &Relay{
+		outbox:    outbox,
+		publisher: publisher,
+		config:    config,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		// Keep draining while full batches come back
+		for {
+			dispatched, err := r.RelayOnce(ctx)
+			if err != nil && ctx.Err() == nil && r.config.OnError != nil {
+				r.config.OnError(err)
+			}
+			if err != nil || dispatched < r.config.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending events in order and returns how many
+// were dispatched. It stops at the first publish failure so that events are
+// never published out of order; the failed event is retried on the next call.
+// An event rejected with a Permanent error would never succeed, so it is
+// handed to OnDeadLetter and dispatched instead of holding back the outbox.
+// A batch claimed by another relay is left to it, and nothing is dispatched.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	var published []string
+	var publishErr error
+
+	err := r.outbox.Claim(ctx, r.config.BatchSize, func(pending []Envelope) []string {
+		published = make([]string, 0, len(pending))
+		for _, envelope := range pending {
+			if err := r.publisher.Publish(ctx, envelope); err != nil {
+				if !IsPermanent(err) {
+					publishErr = err
+					break
+				}
+				if r.config.OnDeadLetter != nil {
+					r.config.OnDeadLetter(envelope, err)
+				}
+			}
+			published = append(published, envelope.ID)
+		}
+		return published
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(published), publishErr
+}
+
+// Fanout returns a Publisher that hands each event to every publisher in turn,
+// so a Relay marks an event dispatched only once all of them accepted it. It
+// stops at the first failure that can be retried, and the event is then
+// published to all of them again; permanent failures do not stop the others
+// and are reported once every publisher was tried.
+func Fanout(publishers ...Publisher) Publisher {
+	return PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+		var rejected []error
+		for _, publisher := range publishers {
+			err := publisher.Publish(ctx, envelope)
+			if err == nil {
+				continue
+			}
+			if !IsPermanent(err) {
+				return err
+			}
+			rejected = append(rejected, err)
+		}
+		return Permanent(errors.Join(rejected...))
+	})
+}
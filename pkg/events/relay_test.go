@@ -0,0 +1,224 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newTestEnvelope(t *testing.T, aggregateID string) Envelope {
+	t.Helper()
+	envelope, err := NewEnvelope(OrderCreatedEvent, OrderAggregate, aggregateID, OrderCreated{OrderID: aggregateID}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return envelope
+}
+
+func TestEnvelope_Decode(t *testing.T) {
+	envelope := newTestEnvelope(t, "order-1")
+
+	var payload OrderCreated
+	if err := envelope.Decode(&payload); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if payload.OrderID != "order-1" {
+		t.Errorf("Expected order-1, got %s", payload.OrderID)
+	}
+	if envelope.ID == "" || envelope.ID == newTestEnvelope(t, "order-1").ID {
+		t.Errorf("Expected a unique envelope ID, got %q", envelope.ID)
+	}
+}
+
+func TestRelay_RelayOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should publish pending events in order and mark them dispatched", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		outbox.Append(newTestEnvelope(t, "order-1"), newTestEnvelope(t, "order-2"))
+
+		var published []string
+		relay := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			published = append(published, envelope.AggregateID)
+			return nil
+		}), RelayConfig{})
+
+		dispatched, err := relay.RelayOnce(ctx)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if dispatched != 2 || len(published) != 2 || published[0] != "order-1" {
+			t.Errorf("Expected order-1 then order-2, got %v", published)
+		}
+
+		pending, _ := outbox.Pending(ctx, 10)
+		if len(pending) != 0 {
+			t.Errorf("Expected nothing pending, got %d events", len(pending))
+		}
+	})
+
+	t.Run("should stop at a failed publish and retry it next time", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		outbox.Append(newTestEnvelope(t, "order-1"), newTestEnvelope(t, "order-2"), newTestEnvelope(t, "order-3"))
+
+		failOn := "order-2"
+		var published []string
+		relay := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			if envelope.AggregateID == failOn {
+				return errors.New("broker unavailable")
+			}
+			published = append(published, envelope.AggregateID)
+			return nil
+		}), RelayConfig{})
+
+		dispatched, err := relay.RelayOnce(ctx)
+		if err == nil || dispatched != 1 {
+			t.Fatalf("Expected 1 dispatched and an error, got %d and %v", dispatched, err)
+		}
+
+		failOn = ""
+		dispatched, err = relay.RelayOnce(ctx)
+		if err != nil || dispatched != 2 {
+			t.Fatalf("Expected the remaining 2 dispatched, got %d and %v", dispatched, err)
+		}
+
+		expected := []string{"order-1", "order-2", "order-3"}
+		for i, id := range expected {
+			if published[i] != id {
+				t.Errorf("Expected %v, got %v", expected, published)
+				break
+			}
+		}
+	})
+
+	t.Run("should dead-letter a permanently rejected event and move on", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		outbox.Append(newTestEnvelope(t, "order-1"), newTestEnvelope(t, "order-2"))
+
+		var deadLettered []string
+		relay := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			if envelope.AggregateID == "order-1" {
+				return Permanent(errors.New("rejected"))
+			}
+			return nil
+		}), RelayConfig{OnDeadLetter: func(envelope Envelope, err error) {
+			deadLettered = append(deadLettered, envelope.AggregateID)
+		}})
+
+		if dispatched, err := relay.RelayOnce(ctx); dispatched != 2 || err != nil {
+			t.Fatalf("Expected 2 dispatched, got %d and %v", dispatched, err)
+		}
+		if len(deadLettered) != 1 || deadLettered[0] != "order-1" {
+			t.Errorf("Expected order-1 to be dead-lettered, got %v", deadLettered)
+		}
+	})
+
+	t.Run("should honour the batch size", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		outbox.Append(newTestEnvelope(t, "order-1"), newTestEnvelope(t, "order-2"), newTestEnvelope(t, "order-3"))
+
+		relay := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			return nil
+		}), RelayConfig{BatchSize: 2})
+
+		if dispatched, _ := relay.RelayOnce(ctx); dispatched != 2 {
+			t.Errorf("Expected 2 dispatched, got %d", dispatched)
+		}
+		if dispatched, _ := relay.RelayOnce(ctx); dispatched != 1 {
+			t.Errorf("Expected 1 dispatched, got %d", dispatched)
+		}
+	})
+
+	t.Run("should leave a batch claimed by another relay to it", func(t *testing.T) {
+		outbox := NewMemoryOutbox()
+		outbox.Append(newTestEnvelope(t, "order-1"), newTestEnvelope(t, "order-2"))
+
+		var published []string
+		replica := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			published = append(published, "replica:"+envelope.AggregateID)
+			return nil
+		}), RelayConfig{})
+		relay := NewRelay(outbox, PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			if len(published) == 0 {
+				if dispatched, err := replica.RelayOnce(ctx); dispatched != 0 || err != nil {
+					t.Errorf("Expected the replica to dispatch nothing, got %d and %v", dispatched, err)
+				}
+			}
+			published = append(published, envelope.AggregateID)
+			return nil
+		}), RelayConfig{})
+
+		if dispatched, err := relay.RelayOnce(ctx); dispatched != 2 || err != nil {
+			t.Fatalf("Expected 2 dispatched, got %d and %v", dispatched, err)
+		}
+		if len(published) != 2 || published[0] != "order-1" || published[1] != "order-2" {
+			t.Errorf("Expected only the claiming relay to publish, got %v", published)
+		}
+	})
+}
+
+func TestFanout(t *testing.T) {
+	ctx := context.Background()
+	envelope := newTestEnvelope(t, "order-1")
+
+	recorder := func(name string, err error, calls *[]string) Publisher {
+		return PublisherFunc(func(ctx context.Context, envelope Envelope) error {
+			*calls = append(*calls, name)
+			return err
+		})
+	}
+
+	t.Run("should acknowledge once every publisher accepted the event", func(t *testing.T) {
+		var calls []string
+		err := Fanout(recorder("a", nil, &calls), recorder("b", nil, &calls)).Publish(ctx, envelope)
+
+		if err != nil || len(calls) != 2 {
+			t.Errorf("Expected both publishers to accept, got %v and %v", calls, err)
+		}
+	})
+
+	t.Run("should stop at a failure that can be retried", func(t *testing.T) {
+		var calls []string
+		err := Fanout(recorder("a", errors.New("unavailable"), &calls), recorder("b", nil, &calls)).Publish(ctx, envelope)
+
+		if err == nil || IsPermanent(err) || len(calls) != 1 {
+			t.Errorf("Expected a retryable failure from a alone, got %v and %v", calls, err)
+		}
+	})
+
+	t.Run("should try the others after a permanent failure", func(t *testing.T) {
+		var calls []string
+		err := Fanout(recorder("a", Permanent(errors.New("rejected")), &calls), recorder("b", nil, &calls)).Publish(ctx, envelope)
+
+		if !IsPermanent(err) || len(calls) != 2 {
+			t.Errorf("Expected a permanent failure after both were tried, got %v and %v", calls, err)
+		}
+	})
+
+	t.Run("should keep the event in the outbox until a forwarding destination acknowledges it", func(t *testing.T) {
+		var status atomic.Int32
+		status.Store(http.StatusServiceUnavailable)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(int(status.Load()))
+		}))
+		defer server.Close()
+
+		outbox := NewMemoryOutbox()
+		outbox.Append(envelope)
+		forwarder := PublisherFunc(HTTPForwarder(server.Client(), server.URL, []byte("secret")))
+		relay := NewRelay(outbox, Fanout(forwarder), RelayConfig{})
+
+		if dispatched, err := relay.RelayOnce(ctx); dispatched != 0 || err == nil {
+			t.Fatalf("Expected nothing dispatched while the destination is down, got %d and %v", dispatched, err)
+		}
+
+		status.Store(http.StatusAccepted)
+		if dispatched, err := relay.RelayOnce(ctx); dispatched != 1 || err != nil {
+			t.Fatalf("Expected the event dispatched once acknowledged, got %d and %v", dispatched, err)
+		}
+	})
+}
@@ -3,6 +3,7 @@ module github.com/robrt95x/godops/pkg
 go 1.24.0
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/google/uuid v1.6.0
 	github.com/sirupsen/logrus v1.9.3
 )
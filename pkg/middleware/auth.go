@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Context keys of the authenticated caller; being unexported, they cannot
+// collide with other packages' keys, which read the caller through the accessors
+type subjectContextKey struct{}
+type rolesContextKey struct{}
+type bearerTokenContextKey struct{}
+
+// AuthConfig configures Authenticate
+type AuthConfig struct {
+	// Keys supplies the keys tokens are verified with, see StaticKey and NewJWKSKeySource
+	Keys KeySource
+	// Issuer and Audience, when set, must match the token's iss and aud claims
+	Issuer   string
+	Audience string
+	// Leeway tolerates clock skew between issuer and verifier for exp and nbf
+	Leeway time.Duration
+}
+
+// accessClaims are the claims Authenticate reads; roles may be a list or a single string
+type accessClaims struct {
+	Roles roleList `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+type roleList []string
+
+func (l *roleList) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*l = roleList{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// Authenticate requires a valid "Authorization: Bearer <jwt>" header. The
+// token must be signed by a key from config.Keys with a matching algorithm,
+// be within its validity period and name a subject. The subject, roles and
+// raw token are added to the request context; any failure is answered with
+// 401 AUTH_UNAUTHENTICATED.
+func Authenticate(config AuthConfig, logger *logrus.Logger) func(next http.Handler) http.Handler {
+	options := []jwt.ParserOption{
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "HS256", "HS384", "HS512"}),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	parser := jwt.NewParser(options...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, found := bearerToken(r)
+			if !found {
+				writeUnauthenticated(w, r, logger, "", errors.New("missing bearer token"))
+				return
+			}
+
+			var claims accessClaims
+			_, err := parser.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+				kid, _ := t.Header["kid"].(string)
+				key, err := config.Keys.Key(r.Context(), kid)
+				if err != nil {
+					return nil, err
+				}
+				return key, checkKeyType(t.Method, key)
+			})
+			if err == nil && claims.Subject == "" {
+				err = errors.New("token has no subject")
+			}
+			if err != nil {
+				writeUnauthenticated(w, r, logger, "invalid_token", err)
+				return
+			}
+
+			ctx := WithIdentity(r.Context(), claims.Subject, []string(claims.Roles))
+			ctx = context.WithValue(ctx, bearerTokenContextKey{}, token)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireRole lets a request through when the authenticated caller has at
+// least one of roles, and answers 403 AUTH_FORBIDDEN otherwise. It must run
+// after Authenticate.
+func RequireRole(logger *logrus.Logger, roles ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, role := range roles {
+				if HasRole(r.Context(), role) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			logger.WithFields(logrus.Fields{
+				"request_id":     GetRequestID(r),
+				"method":         r.Method,
+				"path":           r.URL.Path,
+				"subject":        GetSubject(r),
+				"required_roles": roles,
+			}).Warning("Request rejected: missing role")
+
+			writeAuthError(w, r, http.StatusForbidden, pkgErrors.ErrorInfo{
+				Code:    pkgErrors.AuthForbidden,
+				Message: "You are not allowed to perform this action",
+			})
+		})
+	}
+}
+
+// WithIdentity returns a copy of ctx carrying subject and roles as the
+// authenticated caller, as Authenticate stores them; services and tests use it
+// to act as a caller without a token
+func WithIdentity(ctx context.Context, subject string, roles []string) context.Context {
+	ctx = context.WithValue(ctx, subjectContextKey{}, subject)
+	return context.WithValue(ctx, rolesContextKey{}, roles)
+}
+
+// GetSubject extracts the authenticated subject from the request's context
+func GetSubject(r *http.Request) string {
+	return SubjectFromContext(r.Context())
+}
+
+// SubjectFromContext extracts the authenticated subject from context
+func SubjectFromContext(ctx context.Context) string {
+	if subject, ok := ctx.Value(subjectContextKey{}).(string); ok {
+		return subject
+	}
+	return ""
+}
+
+// GetRoles extracts the authenticated caller's roles from the request's context
+func GetRoles(r *http.Request) []string {
+	return RolesFromContext(r.Context())
+}
+
+// RolesFromContext extracts the authenticated caller's roles from context
+func RolesFromContext(ctx context.Context) []string {
+	if roles, ok := ctx.Value(rolesContextKey{}).([]string); ok {
+		return roles
+	}
+	return nil
+}
+
+// HasRole reports whether the authenticated caller has role
+func HasRole(ctx context.Context, role string) bool {
+	for _, r := range RolesFromContext(ctx) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// BearerTokenFromContext returns the caller's token, for forwarding to downstream services
+func BearerTokenFromContext(ctx context.Context) string {
+	if token, ok := ctx.Value(bearerTokenContextKey{}).(string); ok {
+		return token
+	}
+	return ""
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// checkKeyType rejects a token whose algorithm does not belong to key, so an
+// RSA public key can never be used as an HMAC secret
+func checkKeyType(method jwt.SigningMethod, key any) error {
+	var ok bool
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		_, ok = key.(*rsa.PublicKey)
+	case *jwt.SigningMethodECDSA:
+		_, ok = key.(*ecdsa.PublicKey)
+	case *jwt.SigningMethodHMAC:
+		_, ok = key.([]byte)
+	}
+	if !ok {
+		return fmt.Errorf("algorithm %s does not match the signing key", method.Alg())
+	}
+	return nil
+}
+
+// writeUnauthenticated answers 401 with an RFC 6750 challenge; errorCode is
+// empty when the request carried no token at all
+func writeUnauthenticated(w http.ResponseWriter, r *http.Request, logger *logrus.Logger, errorCode string, err error) {
+	logger.WithFields(logrus.Fields{
+		"request_id": GetRequestID(r),
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"reason":     err.Error(),
+	}).Warning("Request rejected: not authenticated")
+
+	challenge := "Bearer"
+	if errorCode != "" {
+		challenge += ` error="` + errorCode + `"`
+	}
+	w.Header().Set("WWW-Authenticate", challenge)
+
+	writeAuthError(w, r, http.StatusUnauthorized, pkgErrors.ErrorInfo{
+		Code:    pkgErrors.AuthUnauthenticated,
+		Message: "A valid bearer token is required",
+	})
+}
+
+// writeAuthError answers in the same formats as the services' error handlers
+func writeAuthError(w http.ResponseWriter, r *http.Request, statusCode int, info pkgErrors.ErrorInfo) {
+	pkgErrors.WriteResponse(w, r, pkgErrors.Response{Status: statusCode, Info: info})
+}
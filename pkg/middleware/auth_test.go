@@ -0,0 +1,407 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAuthenticate(t *testing.T) {
+	key := newTestKey(t)
+	config := AuthConfig{Keys: StaticKey(&key.PublicKey), Issuer: "issuer", Audience: "godops"}
+
+	t.Run("should put subject and roles into the context", func(t *testing.T) {
+		var subject, bearer string
+		var roles []string
+		handler := Authenticate(config, testLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, roles = GetSubject(r), GetRoles(r)
+			bearer = BearerTokenFromContext(r.Context())
+		}))
+
+		token := signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"roles": []string{"admin", "support"}}))
+		rec := serve(handler, token)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if subject != "user-1" {
+			t.Errorf("Expected subject user-1, got %q", subject)
+		}
+		if len(roles) != 2 || roles[0] != "admin" || roles[1] != "support" {
+			t.Errorf("Expected roles [admin support], got %v", roles)
+		}
+		if bearer != token {
+			t.Errorf("Expected the bearer token to be kept for forwarding, got %q", bearer)
+		}
+	})
+
+	hmacToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, validClaims(nil)).SignedString(key.PublicKey.N.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"missing token", ""},
+		{"malformed token", "not-a-jwt"},
+		{"expired token", signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()}))},
+		{"token without expiry", signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"exp": nil}))},
+		{"wrong audience", signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"aud": "elsewhere"}))},
+		{"wrong issuer", signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"iss": "elsewhere"}))},
+		{"missing subject", signToken(t, key, "kid-1", validClaims(jwt.MapClaims{"sub": nil}))},
+		{"foreign signing key", signToken(t, newTestKey(t), "kid-1", validClaims(nil))},
+		{"HMAC token keyed with the public key", hmacToken},
+	}
+
+	for _, tt := range tests {
+		t.Run("should reject "+tt.name, func(t *testing.T) {
+			handler := Authenticate(config, testLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("Expected the handler not to run")
+			}))
+
+			rec := serve(handler, tt.token)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Fatalf("Expected 401, got %d", rec.Code)
+			}
+			if rec.Header().Get("WWW-Authenticate") == "" {
+				t.Error("Expected a WWW-Authenticate challenge")
+			}
+			if code := decodeErrorCode(t, rec); code != pkgErrors.AuthUnauthenticated {
+				t.Errorf("Expected %s, got %s", pkgErrors.AuthUnauthenticated, code)
+			}
+		})
+	}
+
+	t.Run("should answer problem details when asked for", func(t *testing.T) {
+		handler := Authenticate(config, testLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set("Accept", pkgErrors.ProblemContentType)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if ct := rec.Header().Get("Content-Type"); ct != pkgErrors.ProblemContentType {
+			t.Fatalf("Expected %s, got %s", pkgErrors.ProblemContentType, ct)
+		}
+		if code := decodeErrorCode(t, rec); code != pkgErrors.AuthUnauthenticated {
+			t.Errorf("Expected %s, got %s", pkgErrors.AuthUnauthenticated, code)
+		}
+	})
+}
+
+func TestWithIdentity(t *testing.T) {
+	t.Run("should carry the caller like Authenticate does", func(t *testing.T) {
+		ctx := WithIdentity(context.Background(), "user-1", []string{"support"})
+
+		if subject := SubjectFromContext(ctx); subject != "user-1" {
+			t.Errorf("Expected subject user-1, got %q", subject)
+		}
+		if !HasRole(ctx, "support") || HasRole(ctx, "admin") {
+			t.Errorf("Expected only the support role, got %v", RolesFromContext(ctx))
+		}
+	})
+
+	t.Run("should not be forged with a plain string key", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), "subject", "user-1")
+
+		if subject := SubjectFromContext(ctx); subject != "" {
+			t.Errorf("Expected no subject, got %q", subject)
+		}
+	})
+}
+
+func TestRequireRole(t *testing.T) {
+	key := newTestKey(t)
+	authenticate := Authenticate(AuthConfig{Keys: StaticKey(&key.PublicKey)}, testLogger())
+	handler := authenticate(RequireRole(testLogger(), "support", "admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+
+	tests := []struct {
+		name     string
+		roles    any
+		wantCode int
+	}{
+		{"one of the roles", []string{"customer", "admin"}, http.StatusOK},
+		{"a single role string", "support", http.StatusOK},
+		{"no matching role", []string{"customer"}, http.StatusForbidden},
+		{"no roles", nil, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run("should answer "+http.StatusText(tt.wantCode)+" for "+tt.name, func(t *testing.T) {
+			rec := serve(handler, signToken(t, key, "", validClaims(jwt.MapClaims{"roles": tt.roles})))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("Expected %d, got %d", tt.wantCode, rec.Code)
+			}
+			if tt.wantCode == http.StatusForbidden {
+				if code := decodeErrorCode(t, rec); code != pkgErrors.AuthForbidden {
+					t.Errorf("Expected %s, got %s", pkgErrors.AuthForbidden, code)
+				}
+			}
+		})
+	}
+}
+
+func TestJWKSKeySource(t *testing.T) {
+	ctx := context.Background()
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+
+	var fetches atomic.Int32
+	var published atomic.Value
+	published.Store([]*rsa.PrivateKey{oldKey})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		json.NewEncoder(w).Encode(jwksFor(published.Load().([]*rsa.PrivateKey)...))
+	}))
+	defer server.Close()
+
+	t.Run("should cache the key set", func(t *testing.T) {
+		source := NewJWKSKeySource(server.URL, JWKSConfig{})
+		fetches.Store(0)
+
+		for i := 0; i < 3; i++ {
+			if _, err := source.Key(ctx, "kid-0"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if n := fetches.Load(); n != 1 {
+			t.Errorf("Expected 1 fetch, got %d", n)
+		}
+	})
+
+	t.Run("should refetch for a rotated-in key", func(t *testing.T) {
+		source := NewJWKSKeySource(server.URL, JWKSConfig{MinRefreshInterval: time.Nanosecond})
+		published.Store([]*rsa.PrivateKey{oldKey})
+		if _, err := source.Key(ctx, "kid-0"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		published.Store([]*rsa.PrivateKey{oldKey, newKey})
+		key, err := source.Key(ctx, "kid-1")
+		if err != nil {
+			t.Fatalf("Expected the rotated key, got %v", err)
+		}
+		if key.(*rsa.PublicKey).N.Cmp(newKey.N) != 0 {
+			t.Error("Expected the new public key")
+		}
+	})
+
+	t.Run("should not refetch unknown keys within the refresh interval", func(t *testing.T) {
+		source := NewJWKSKeySource(server.URL, JWKSConfig{MinRefreshInterval: time.Hour})
+		published.Store([]*rsa.PrivateKey{oldKey})
+		fetches.Store(0)
+
+		for i := 0; i < 3; i++ {
+			if _, err := source.Key(ctx, "made-up"); err != ErrUnknownKey {
+				t.Fatalf("Expected ErrUnknownKey, got %v", err)
+			}
+		}
+		if n := fetches.Load(); n != 1 {
+			t.Errorf("Expected 1 fetch, got %d", n)
+		}
+	})
+
+	// gatedServer publishes oldKey once release is closed, counting requests on arrival
+	gatedServer := func(t *testing.T) (*httptest.Server, *atomic.Int32, chan struct{}) {
+		var requests atomic.Int32
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			<-release
+			json.NewEncoder(w).Encode(jwksFor(oldKey))
+		}))
+		t.Cleanup(server.Close)
+		return server, &requests, release
+	}
+	waitFor := func(t *testing.T, condition func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(time.Second); !condition(); time.Sleep(time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatal("Timed out waiting")
+			}
+		}
+	}
+
+	t.Run("should share one fetch between concurrent requests", func(t *testing.T) {
+		server, requests, release := gatedServer(t)
+		source := NewJWKSKeySource(server.URL, JWKSConfig{MinRefreshInterval: time.Nanosecond})
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = source.Key(ctx, "kid-0")
+			}(i)
+		}
+		waitFor(t, func() bool { return requests.Load() == 1 })
+		close(release)
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("Expected 1 fetch, got %d", n)
+		}
+	})
+
+	t.Run("should not let a cancelled request cut the fetch short", func(t *testing.T) {
+		server, requests, release := gatedServer(t)
+		source := NewJWKSKeySource(server.URL, JWKSConfig{MinRefreshInterval: time.Hour})
+
+		cancelled, cancel := context.WithCancel(ctx)
+		result := make(chan error, 1)
+		go func() {
+			_, err := source.Key(cancelled, "kid-0")
+			result <- err
+		}()
+		waitFor(t, func() bool { return requests.Load() == 1 })
+		cancel()
+
+		// The waiting request leaves while the fetch is still blocked
+		select {
+		case err := <-result:
+			if err != context.Canceled {
+				t.Errorf("Expected %v, got %v", context.Canceled, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the cancelled request to return without waiting for the fetch")
+		}
+
+		close(release)
+		if _, err := source.Key(ctx, "kid-0"); err != nil {
+			t.Errorf("Expected the fetch to complete for later requests, got %v", err)
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("Expected 1 fetch, got %d", n)
+		}
+	})
+
+	t.Run("should bound a fetch by the fetch timeout", func(t *testing.T) {
+		server, _, release := gatedServer(t)
+		defer close(release)
+		source := NewJWKSKeySource(server.URL, JWKSConfig{FetchTimeout: 10 * time.Millisecond})
+
+		start := time.Now()
+		if _, err := source.Key(ctx, "kid-0"); err == nil {
+			t.Error("Expected the fetch to time out")
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Expected the fetch to give up after its timeout, took %v", elapsed)
+		}
+	})
+
+	t.Run("should verify tokens end to end", func(t *testing.T) {
+		published.Store([]*rsa.PrivateKey{oldKey})
+		source := NewJWKSKeySource(server.URL, JWKSConfig{})
+		handler := Authenticate(AuthConfig{Keys: source}, testLogger())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		if rec := serve(handler, signToken(t, oldKey, "kid-0", validClaims(nil))); rec.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", rec.Code)
+		}
+		if rec := serve(handler, signToken(t, newKey, "kid-0", validClaims(nil))); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for a key outside the set, got %d", rec.Code)
+		}
+	})
+}
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+// validClaims returns claims Authenticate accepts, with overrides applied; a nil override removes the claim
+func validClaims(overrides jwt.MapClaims) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"sub": "user-1",
+		"iss": "issuer",
+		"aud": "godops",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+	return claims
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return signed
+}
+
+// jwksFor publishes keys with IDs kid-0, kid-1, ...
+func jwksFor(keys ...*rsa.PrivateKey) map[string]any {
+	set := make([]map[string]string, 0, len(keys))
+	for i, key := range keys {
+		set = append(set, map[string]string{
+			"kty": "RSA",
+			"use": "sig",
+			"kid": "kid-" + strconv.Itoa(i),
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	return map[string]any{"keys": set}
+}
+
+func serve(handler http.Handler, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var info pkgErrors.ErrorInfo
+	if err := json.NewDecoder(rec.Body).Decode(&info); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	return info.Code
+}
+
+func testLogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return logger
+}
@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey is returned by a KeySource that has no key with the requested ID
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySource supplies the public keys that access tokens are verified with
+type KeySource interface {
+	// Key returns the key with ID kid; kid is empty when the token header has none
+	Key(ctx context.Context, kid string) (any, error)
+}
+
+// StaticKey verifies every token with key, whatever its key ID: an
+// *rsa.PublicKey, an *ecdsa.PublicKey or a []byte HMAC secret
+func StaticKey(key any) KeySource {
+	return staticKey{key: key}
+}
+
+type staticKey struct {
+	key any
+}
+
+func (s staticKey) Key(ctx context.Context, kid string) (any, error) {
+	return s.key, nil
+}
+
+// JWKSConfig configures a JWKSKeySource
+type JWKSConfig struct {
+	// CacheTTL is how long a fetched key set is used before it is fetched again
+	CacheTTL time.Duration
+	// MinRefreshInterval is the least time between fetches, so tokens with
+	// made-up key IDs or an unreachable issuer do not turn every request into a fetch
+	MinRefreshInterval time.Duration
+	// FetchTimeout bounds a fetch, which is not tied to any one request
+	FetchTimeout time.Duration
+	HTTPClient   *http.Client
+}
+
+// JWKSKeySource fetches keys from a JSON Web Key Set URL and caches them.
+// A token signed with a key ID that is not cached triggers a refetch, so
+// keys the issuer rotates in are picked up before the cache expires. When a
+// fetch fails the previously fetched keys stay in use.
+//
+// Concurrent requests share a single fetch, which runs without holding the
+// lock and outlives the requests waiting on it, so a cancelled request
+// neither blocks the others nor cuts the fetch short.
+type JWKSKeySource struct {
+	url    string
+	config JWKSConfig
+
+	mutex       sync.Mutex
+	keys        map[string]any
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	// inflight is the fetch in progress, nil when there is none
+	inflight *jwksFetch
+}
+
+// jwksFetch is a fetch that requests wait on; err is set before done is closed
+type jwksFetch struct {
+	done chan struct{}
+	err  error
+}
+
+// NewJWKSKeySource returns a key source for url; the first fetch happens on first use
+func NewJWKSKeySource(url string, config JWKSConfig) *JWKSKeySource {
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = 5 * time.Minute
+	}
+	if config.MinRefreshInterval <= 0 {
+		config.MinRefreshInterval = 10 * time.Second
+	}
+	if config.FetchTimeout <= 0 {
+		config.FetchTimeout = 5 * time.Second
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.FetchTimeout}
+	}
+	return &JWKSKeySource{url: url, config: config}
+}
+
+func (s *JWKSKeySource) Key(ctx context.Context, kid string) (any, error) {
+	s.mutex.Lock()
+	now := time.Now()
+	key, cached := s.lookup(kid)
+	stale := s.keys == nil || now.Sub(s.fetchedAt) >= s.config.CacheTTL
+
+	fetch := s.inflight
+	if fetch == nil && (stale || !cached) && now.Sub(s.attemptedAt) >= s.config.MinRefreshInterval {
+		s.attemptedAt = now
+		fetch = &jwksFetch{done: make(chan struct{})}
+		s.inflight = fetch
+		go s.fetch(fetch, now)
+	}
+	s.mutex.Unlock()
+
+	// A fresh cached key is used as is, even while another key is being fetched
+	if fetch == nil || (cached && !stale) {
+		if cached {
+			return key, nil
+		}
+		return nil, ErrUnknownKey
+	}
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	s.mutex.Lock()
+	key, cached = s.lookup(kid)
+	s.mutex.Unlock()
+
+	if cached {
+		return key, nil
+	}
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	return nil, ErrUnknownKey
+}
+
+// lookup finds kid among the cached keys; a token without a key ID is only
+// accepted when the set holds exactly one key. It must be called with the lock held.
+func (s *JWKSKeySource) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// fetch loads the key set into the cache and completes fetch. It runs on its
+// own context, bounded by FetchTimeout, rather than on a request's.
+func (s *JWKSKeySource) fetch(fetch *jwksFetch, now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.FetchTimeout)
+	defer cancel()
+
+	keys, err := s.fetchKeys(ctx)
+
+	s.mutex.Lock()
+	if err == nil {
+		s.keys = keys
+		s.fetchedAt = now
+	}
+	s.inflight = nil
+	s.mutex.Unlock()
+
+	fetch.err = err
+	close(fetch.done)
+}
+
+func (s *JWKSKeySource) fetchKeys(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: %s returned %d", s.url, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		// Keys of unsupported types are skipped rather than failing the whole set
+		if key, err := jwk.publicKey(); err == nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+// jsonWebKey holds the RFC 7517 members needed for RSA and EC public keys
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(raw) == 0 {
+		return nil, fmt.Errorf("invalid key parameter %q", value)
+	}
+	return new(big.Int).SetBytes(raw), nil
+}
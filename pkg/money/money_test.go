@@ -0,0 +1,93 @@
+package money_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/robrt95x/godops/pkg/money"
+)
+
+func TestMoney(t *testing.T) {
+	t.Run("should reject unknown currencies", func(t *testing.T) {
+		if _, err := money.New(100, "XXX"); !errors.Is(err, money.ErrUnknownCurrency) {
+			t.Errorf("Expected ErrUnknownCurrency, got %v", err)
+		}
+	})
+
+	t.Run("should normalise currency case", func(t *testing.T) {
+		m, err := money.New(100, "usd")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if m.Currency != "USD" {
+			t.Errorf("Expected USD, got %s", m.Currency)
+		}
+	})
+
+	t.Run("should add without rounding drift", func(t *testing.T) {
+		total := money.Zero("USD")
+		price := money.Money{Amount: 10, Currency: "USD"}
+		for i := 0; i < 10; i++ {
+			total, _ = total.Add(price)
+		}
+		if total.Amount != 100 {
+			t.Errorf("Expected 100, got %d", total.Amount)
+		}
+	})
+
+	t.Run("should refuse to mix currencies", func(t *testing.T) {
+		_, err := money.Money{Amount: 1, Currency: "USD"}.Add(money.Money{Amount: 1, Currency: "EUR"})
+		if !errors.Is(err, money.ErrCurrencyMismatch) {
+			t.Errorf("Expected ErrCurrencyMismatch, got %v", err)
+		}
+	})
+
+	t.Run("should report overflow instead of wrapping", func(t *testing.T) {
+		large := money.Money{Amount: math.MaxInt64 / 2, Currency: "USD"}
+		tests := []struct {
+			name string
+			op   func() (money.Money, error)
+		}{
+			{"multiply", func() (money.Money, error) { return large.Multiply(3) }},
+			{"multiply by a negative factor", func() (money.Money, error) { return large.Multiply(-3) }},
+			{"multiply the minimum by -1", func() (money.Money, error) {
+				return money.Money{Amount: math.MinInt64, Currency: "USD"}.Multiply(-1)
+			}},
+			{"add", func() (money.Money, error) {
+				return large.Add(money.Money{Amount: math.MaxInt64/2 + 2, Currency: "USD"})
+			}},
+			{"subtract", func() (money.Money, error) {
+				return money.Money{Amount: math.MinInt64 + 1, Currency: "USD"}.Subtract(money.Money{Amount: 2, Currency: "USD"})
+			}},
+		}
+		for _, tt := range tests {
+			if _, err := tt.op(); !errors.Is(err, money.ErrOverflow) {
+				t.Errorf("Expected ErrOverflow to %s, got %v", tt.name, err)
+			}
+		}
+
+		product, err := money.Money{Amount: 2999, Currency: "USD"}.Multiply(3)
+		if err != nil || product.Amount != 8997 {
+			t.Errorf("Expected 8997, got %d (%v)", product.Amount, err)
+		}
+	})
+
+	t.Run("should format using minor units", func(t *testing.T) {
+		tests := []struct {
+			money    money.Money
+			expected string
+		}{
+			{money.Money{Amount: 2999, Currency: "USD"}, "29.99 USD"},
+			{money.Money{Amount: 5, Currency: "EUR"}, "0.05 EUR"},
+			{money.Money{Amount: -150, Currency: "GBP"}, "-1.50 GBP"},
+			{money.Money{Amount: 1500, Currency: "JPY"}, "1500 JPY"},
+			{money.Money{Amount: 1234, Currency: "KWD"}, "1.234 KWD"},
+		}
+		for _, tt := range tests {
+			if got := tt.money.String(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/pkg/events"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/notification/internal/config"
+	"github.com/robrt95x/godops/services/notification/internal/delivery/consumer"
+	httpDelivery "github.com/robrt95x/godops/services/notification/internal/delivery/http"
+	serviceErrors "github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/infra"
+	"github.com/robrt95x/godops/services/notification/internal/infra/static"
+	"github.com/robrt95x/godops/services/notification/internal/templates"
+	"github.com/robrt95x/godops/services/notification/internal/usecase"
+)
+
+func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Setup logger
+	loggerConfig := pkgLogger.Config{
+		Level:       cfg.LogLevel,
+		Format:      cfg.LogFormat,
+		Output:      cfg.LogOutput,
+		FilePath:    cfg.LogFilePath,
+		MaxSize:     cfg.LogMaxSize,
+		MaxBackups:  cfg.LogMaxBackups,
+		MaxAge:      cfg.LogMaxAge,
+		Compress:    cfg.LogCompress,
+		ServiceName: "notification-service",
+	}
+	appLogger := pkgLogger.Setup(loggerConfig)
+
+	// Fail fast on an error catalog entry without a status, gRPC code or log level
+	if err := serviceErrors.Catalog.Validate(); err != nil {
+		appLogger.WithError(err).Fatal("Invalid error catalog")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrator, err := infra.NewRepositoryFactory(cfg).CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		os.Exit(db.RunMigrateCommand(os.Args[2:], migrator, "notification-service"))
+	}
+
+	appLogger.WithField("storage_type", cfg.StorageType).Info("Starting notification service")
+
+	// Create repository and channels using factory
+	factory := infra.NewRepositoryFactory(cfg)
+	if cfg.IsPostgresStorage() && cfg.DBAutoMigrate {
+		migrator, err := factory.CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to apply database migrations")
+		}
+		appLogger.WithField("applied", len(applied)).Info("Database schema is up to date")
+	}
+
+	repo, err := factory.CreateDeliveryRepository()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create repository")
+	}
+	channels, err := factory.CreateChannels()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create notification channels")
+	}
+	renderer, err := templates.NewEmbeddedRenderer(cfg.DefaultLocale)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to load notification templates")
+	}
+	directory := static.NewDirectory(cfg.RecipientEmailPattern, cfg.DefaultLocale)
+
+	// Create use cases
+	notifyUC := usecase.NewNotifyCase(repo, directory, renderer, channels, cfg.DeliveryMaxAttempts, appLogger)
+	listDeliveriesUC := usecase.NewListDeliveriesCase(repo, appLogger)
+	handler := httpDelivery.NewDeliveryHandler(listDeliveriesUC, appLogger)
+
+	// Setup router with middleware
+	r := chi.NewRouter()
+
+	// Add custom middleware
+	r.Use(pkgMiddleware.RequestID)
+	r.Use(pkgMiddleware.Logging(appLogger))
+	r.Use(pkgMiddleware.ErrorLogging(appLogger))
+	r.Use(pkgMiddleware.Timeout(cfg.RequestTimeout))
+	r.Use(middleware.Recoverer)
+
+	// Only the order service, which signs events with the shared secret, may post them.
+	// Events are handled before they are acknowledged, so the order service keeps
+	// an event in its outbox until its delivery attempts are recorded here.
+	if cfg.EventSecret == "" {
+		appLogger.Fatal("EVENT_SECRET is required to receive events")
+	}
+	receiver := events.PublisherFunc(consumer.Handler(notifyUC, cfg.DeliveryTimeout))
+	r.Method(http.MethodPost, "/events", events.HTTPReceiver(receiver, []byte(cfg.EventSecret)))
+
+	// Delivery attempts name recipients and carry error details, so only
+	// support and admin users may list them
+	if cfg.AuthEnabled {
+		authenticate := pkgMiddleware.Authenticate(pkgMiddleware.AuthConfig{
+			Keys:     pkgMiddleware.NewJWKSKeySource(cfg.AuthJWKSURL, pkgMiddleware.JWKSConfig{CacheTTL: cfg.AuthJWKSCacheTTL}),
+			Issuer:   cfg.AuthIssuer,
+			Audience: cfg.AuthAudience,
+			Leeway:   cfg.AuthLeeway,
+		}, appLogger)
+		r.With(authenticate, pkgMiddleware.RequireRole(appLogger, "support", "admin")).Get("/deliveries", handler.ListDeliveries)
+	} else {
+		appLogger.Warn("AUTH_ENABLED=false; /deliveries is served without authentication")
+		r.Get("/deliveries", handler.ListDeliveries)
+	}
+
+	appLogger.WithField("port", cfg.ServerPort).Info("Starting HTTP server")
+	if err := http.ListenAndServe(":"+cfg.ServerPort, r); err != nil {
+		appLogger.WithError(err).Fatal("HTTP server failed")
+	}
+}
@@ -0,0 +1,16 @@
+package channel
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+// Channel delivers rendered messages, e.g. over SMTP or a webhook. Send may be
+// called again for a message after an error, so implementations should tolerate
+// duplicates.
+type Channel interface {
+	// Name identifies the channel in delivery records
+	Name() string
+	Send(ctx context.Context, message entity.Message) error
+}
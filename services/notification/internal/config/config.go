@@ -0,0 +1,173 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+type Config struct {
+	// Storage Configuration
+	StorageType string `env:"STORAGE_TYPE" default:"postgres"`
+
+	// Database Configuration
+	DBHost        string `env:"DB_HOST" default:"localhost"`
+	DBPort        string `env:"DB_PORT" default:"5432"`
+	DBUser        string `env:"DB_USER" default:"user"`
+	DBPassword    string `env:"DB_PASSWORD" default:"pass"`
+	DBName        string `env:"DB_NAME" default:"godops"`
+	DBSSLMode     string `env:"DB_SSLMODE" default:"disable"`
+	DBAutoMigrate bool   `env:"DB_AUTO_MIGRATE" default:"true"`
+
+	// Server Configuration
+	ServerPort     string        `env:"SERVER_PORT" default:"8083"`
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" default:"10s"`
+	// EventSecret verifies the signature of events POSTed to /events; the
+	// order service signs them with the same EVENT_FORWARD_SECRET
+	EventSecret string `env:"EVENT_SECRET" default:""`
+
+	// Authentication Configuration; /deliveries takes tokens issued by the user
+	// service and verified against its JWKS
+	AuthEnabled      bool          `env:"AUTH_ENABLED" default:"true"`
+	AuthJWKSURL      string        `env:"AUTH_JWKS_URL" default:"http://localhost:8081/.well-known/jwks.json"`
+	AuthJWKSCacheTTL time.Duration `env:"AUTH_JWKS_CACHE_TTL" default:"5m"`
+	AuthIssuer       string        `env:"AUTH_ISSUER" default:"godops-user-service"`
+	AuthAudience     string        `env:"AUTH_AUDIENCE" default:"godops"`
+	AuthLeeway       time.Duration `env:"AUTH_LEEWAY" default:"30s"`
+
+	// Notification Configuration
+	Channels              []string      `env:"NOTIFICATION_CHANNELS" default:"file"`
+	DeliveryTimeout       time.Duration `env:"DELIVERY_TIMEOUT" default:"10s"`
+	DeliveryMaxAttempts   int           `env:"DELIVERY_MAX_ATTEMPTS" default:"5"`
+	DefaultLocale         string        `env:"DEFAULT_LOCALE" default:"en"`
+	RecipientEmailPattern string        `env:"RECIPIENT_EMAIL_PATTERN" default:"{user_id}@example.com"`
+
+	// Channel Configuration
+	SMTPHost     string `env:"SMTP_HOST" default:"localhost"`
+	SMTPPort     string `env:"SMTP_PORT" default:"1025"`
+	SMTPFrom     string `env:"SMTP_FROM" default:"notifications@godops.local"`
+	WebhookURL   string `env:"WEBHOOK_URL" default:""`
+	FileSinkPath string `env:"FILE_SINK_PATH" default:"logs/notifications.jsonl"`
+
+	// Logging Configuration
+	LogLevel      string `env:"LOG_LEVEL" default:"info"`
+	LogFormat     string `env:"LOG_FORMAT" default:"json"`
+	LogOutput     string `env:"LOG_OUTPUT" default:"console"`
+	LogFilePath   string `env:"LOG_FILE_PATH" default:"logs/notification-service.log"`
+	LogMaxSize    int    `env:"LOG_MAX_SIZE" default:"100"`
+	LogMaxBackups int    `env:"LOG_MAX_BACKUPS" default:"5"`
+	LogMaxAge     int    `env:"LOG_MAX_AGE" default:"30"`
+	LogCompress   bool   `env:"LOG_COMPRESS" default:"true"`
+
+	// Environment
+	AppEnv string `env:"APP_ENV" default:"development"`
+}
+
+func Load() *Config {
+	// Try to load .env file (ignore error if file doesn't exist)
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found, using environment variables and defaults")
+	}
+
+	config := &Config{
+		StorageType:           getEnv("STORAGE_TYPE", "postgres"),
+		DBHost:                getEnv("DB_HOST", "localhost"),
+		DBPort:                getEnv("DB_PORT", "5432"),
+		DBUser:                getEnv("DB_USER", "user"),
+		DBPassword:            getEnv("DB_PASSWORD", "pass"),
+		DBName:                getEnv("DB_NAME", "godops"),
+		DBSSLMode:             getEnv("DB_SSLMODE", "disable"),
+		DBAutoMigrate:         getEnvBool("DB_AUTO_MIGRATE", true),
+		ServerPort:            getEnv("SERVER_PORT", "8083"),
+		RequestTimeout:        getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		EventSecret:           getEnv("EVENT_SECRET", ""),
+		AuthEnabled:           getEnvBool("AUTH_ENABLED", true),
+		AuthJWKSURL:           getEnv("AUTH_JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+		AuthJWKSCacheTTL:      getEnvDuration("AUTH_JWKS_CACHE_TTL", 5*time.Minute),
+		AuthIssuer:            getEnv("AUTH_ISSUER", "godops-user-service"),
+		AuthAudience:          getEnv("AUTH_AUDIENCE", "godops"),
+		AuthLeeway:            getEnvDuration("AUTH_LEEWAY", 30*time.Second),
+		Channels:              getEnvList("NOTIFICATION_CHANNELS", "file"),
+		DeliveryTimeout:       getEnvDuration("DELIVERY_TIMEOUT", 10*time.Second),
+		DeliveryMaxAttempts:   getEnvInt("DELIVERY_MAX_ATTEMPTS", 5),
+		DefaultLocale:         getEnv("DEFAULT_LOCALE", "en"),
+		RecipientEmailPattern: getEnv("RECIPIENT_EMAIL_PATTERN", "{user_id}@example.com"),
+		SMTPHost:              getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:              getEnv("SMTP_PORT", "1025"),
+		SMTPFrom:              getEnv("SMTP_FROM", "notifications@godops.local"),
+		WebhookURL:            getEnv("WEBHOOK_URL", ""),
+		FileSinkPath:          getEnv("FILE_SINK_PATH", "logs/notifications.jsonl"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
+		LogFormat:             getEnv("LOG_FORMAT", "json"),
+		LogOutput:             getEnv("LOG_OUTPUT", "console"),
+		LogFilePath:           getEnv("LOG_FILE_PATH", "logs/notification-service.log"),
+		LogMaxSize:            getEnvInt("LOG_MAX_SIZE", 100),
+		LogMaxBackups:         getEnvInt("LOG_MAX_BACKUPS", 5),
+		LogMaxAge:             getEnvInt("LOG_MAX_AGE", 30),
+		LogCompress:           getEnvBool("LOG_COMPRESS", true),
+		AppEnv:                getEnv("APP_ENV", "development"),
+	}
+
+	return config
+}
+
+func (c *Config) GetDatabaseURL() string {
+	return "postgres://" + c.DBUser + ":" + c.DBPassword + "@" + c.DBHost + ":" + c.DBPort + "/" + c.DBName + "?sslmode=" + c.DBSSLMode
+}
+
+func (c *Config) IsMemoryStorage() bool {
+	return c.StorageType == "memory"
+}
+
+func (c *Config) IsPostgresStorage() bool {
+	return c.StorageType == "postgres"
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries
+func getEnvList(key, defaultValue string) []string {
+	var values []string
+	for _, value := range strings.Split(getEnv(key, defaultValue), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,52 @@
+package consumer
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/usecase"
+)
+
+// Topics are the events the service has templates for
+var Topics = []string{events.OrderCreatedEvent, events.OrderStatusChangedEvent}
+
+// Handler returns a handler that notifies for events of Topics and
+// acknowledges any other event without doing anything. Each event gets timeout
+// to be delivered before the attempt is abandoned and left to a redelivery.
+// Served through events.HTTPReceiver, an event is acknowledged only once its
+// delivery attempts are recorded.
+func Handler(notifyUC *usecase.NotifyCase, timeout time.Duration) events.Handler {
+	return func(ctx context.Context, envelope events.Envelope) error {
+		if !isTopic(envelope.Type) {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		err := notifyUC.Execute(ctx, envelope)
+		if isPermanent(err) {
+			return events.Permanent(err)
+		}
+		return err
+	}
+}
+
+func isTopic(eventType string) bool {
+	for _, topic := range Topics {
+		if topic == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// isPermanent reports errors that redelivering the same event cannot fix
+func isPermanent(err error) bool {
+	return errors.IsValidationError(err) ||
+		stdErrors.Is(err, errors.ErrNotificationTemplateNotFound) ||
+		stdErrors.Is(err, errors.ErrNotificationRecipientUnknown)
+}
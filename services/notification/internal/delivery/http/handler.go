@@ -0,0 +1,71 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+	"github.com/robrt95x/godops/services/notification/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+type DeliveryHandler struct {
+	ListUC       *usecase.ListDeliveriesCase
+	ErrorHandler *pkgErrors.HTTPErrorHandler
+	Logger       *logrus.Logger
+}
+
+func NewDeliveryHandler(listUC *usecase.ListDeliveriesCase, logger *logrus.Logger) *DeliveryHandler {
+	errorCatalog := errors.NewNotificationErrorCatalog()
+	return &DeliveryHandler{
+		ListUC:       listUC,
+		ErrorHandler: pkgErrors.NewHTTPErrorHandler(logger, errorCatalog),
+		Logger:       logger,
+	}
+}
+
+type ListDeliveriesResponse struct {
+	Deliveries []*entity.DeliveryAttempt `json:"deliveries"`
+}
+
+func (h *DeliveryHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Request-ID")
+	query := r.URL.Query()
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "ListDeliveries",
+		"request_id": requestID,
+	})
+
+	logEntry.Debug("Processing list deliveries request")
+
+	filter := repository.DeliveryFilter{
+		EventID: query.Get("event_id"),
+		Status:  entity.DeliveryStatus(query.Get("status")),
+	}
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil {
+			logEntry.WithError(err).Warning("Invalid limit parameter")
+			h.ErrorHandler.HandleError(w, r, errors.ErrValidationInvalidRequest)
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	attempts, err := h.ListUC.Execute(r.Context(), filter)
+	if err != nil {
+		logEntry.WithError(err).Warning("List deliveries use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.WithField("count", len(attempts)).Info("Delivery attempts listed successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ListDeliveriesResponse{Deliveries: attempts})
+}
@@ -0,0 +1,42 @@
+package entity
+
+import "time"
+
+type DeliveryStatus string
+
+const (
+	Sent   DeliveryStatus = "SENT"
+	Failed DeliveryStatus = "FAILED"
+)
+
+// Recipient is who a notification is addressed to
+type Recipient struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+	Locale string `json:"locale"`
+}
+
+// Message is a rendered notification ready to hand to a channel. HTMLBody may be empty.
+type Message struct {
+	EventID   string
+	EventType string
+	Recipient Recipient
+	Locale    string
+	Subject   string
+	TextBody  string
+	HTMLBody  string
+}
+
+// DeliveryAttempt records one try at sending a message over a channel
+type DeliveryAttempt struct {
+	ID          string         `json:"id"`
+	EventID     string         `json:"event_id"`
+	EventType   string         `json:"event_type"`
+	Channel     string         `json:"channel"`
+	Recipient   string         `json:"recipient"`
+	Locale      string         `json:"locale"`
+	Subject     string         `json:"subject"`
+	Status      DeliveryStatus `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	AttemptedAt time.Time      `json:"attempted_at"`
+}
@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+// Error codes for standardized API responses
+const (
+	// Notification related errors
+	NotificationTemplateNotFound = "NOTIFICATION_TEMPLATE_NOT_FOUND"
+	NotificationRecipientUnknown = "NOTIFICATION_RECIPIENT_UNKNOWN"
+	NotificationDeliveryFailed   = "NOTIFICATION_DELIVERY_FAILED"
+
+	// Validation errors
+	ValidationMissingEventID = "VALIDATION_MISSING_EVENT_ID"
+	ValidationInvalidPayload = "VALIDATION_INVALID_PAYLOAD"
+	ValidationInvalidRequest = "VALIDATION_INVALID_REQUEST"
+
+	// Database errors
+	DatabaseConnectionError  = "DATABASE_CONNECTION_ERROR"
+	DatabaseQueryError       = "DATABASE_QUERY_ERROR"
+	DatabaseTransactionError = "DATABASE_TRANSACTION_ERROR"
+
+	// System errors
+	SystemInternalError      = "SYSTEM_INTERNAL_ERROR"
+	SystemServiceUnavailable = "SYSTEM_SERVICE_UNAVAILABLE"
+	SystemTimeout            = "SYSTEM_TIMEOUT"
+)
+
+// Domain errors that map to error codes
+var (
+	ErrNotificationTemplateNotFound = errors.New("no notification template for event type")
+	ErrNotificationRecipientUnknown = errors.New("notification recipient could not be resolved")
+	ErrNotificationDeliveryFailed   = errors.New("notification delivery failed on at least one channel")
+
+	ErrValidationMissingEventID = errors.New("event ID is required")
+	ErrValidationInvalidPayload = errors.New("event payload is not a JSON object with a user ID")
+	ErrValidationInvalidRequest = errors.New("invalid request format")
+
+	ErrDatabaseConnection  = errors.New("database connection failed")
+	ErrDatabaseQuery       = errors.New("database query failed")
+	ErrDatabaseTransaction = errors.New("database transaction failed")
+
+	ErrSystemInternal           = errors.New("internal system error")
+	ErrSystemServiceUnavailable = errors.New("service temporarily unavailable")
+	ErrSystemTimeout            = errors.New("request timeout")
+)
+
+// Catalog resolves domain errors to API error responses; errors wrapped with
+// %w resolve like the error they wrap
+var Catalog = pkgErrors.NewRegistry(
+	pkgErrors.Entry{Code: SystemInternalError, Message: "An unexpected error occurred", Meta: pkgErrors.Internal},
+
+	pkgErrors.Entry{Err: ErrNotificationTemplateNotFound, Code: NotificationTemplateNotFound, Message: "No notification template exists for this event type", Meta: pkgErrors.NotFound},
+	pkgErrors.Entry{Err: ErrNotificationRecipientUnknown, Code: NotificationRecipientUnknown, Message: "The notification recipient could not be resolved", Meta: pkgErrors.Meta{
+		Status: http.StatusUnprocessableEntity, GRPC: pkgErrors.GRPCFailedPrecondition, LogLevel: pkgErrors.LogWarning,
+	}},
+	pkgErrors.Entry{Err: ErrNotificationDeliveryFailed, Code: NotificationDeliveryFailed, Message: "The notification could not be delivered on every channel", Meta: pkgErrors.Meta{
+		Status: http.StatusBadGateway, GRPC: pkgErrors.GRPCUnavailable, Retryable: true, LogLevel: pkgErrors.LogError,
+	}},
+
+	pkgErrors.Entry{Err: ErrValidationMissingEventID, Code: ValidationMissingEventID, Message: "Event ID is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidPayload, Code: ValidationInvalidPayload, Message: "Event payload must be a JSON object with a user_id", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRequest, Code: ValidationInvalidRequest, Message: "Invalid request format", Meta: pkgErrors.BadRequest},
+
+	pkgErrors.Entry{Err: ErrDatabaseConnection, Code: DatabaseConnectionError, Message: "Database connection failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseQuery, Code: DatabaseQueryError, Message: "Database query failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseTransaction, Code: DatabaseTransactionError, Message: "Database transaction failed", Meta: pkgErrors.DatabaseFailure},
+
+	pkgErrors.Entry{Err: ErrSystemInternal, Code: SystemInternalError, Message: "An internal error occurred", Meta: pkgErrors.Internal},
+	pkgErrors.Entry{Err: ErrSystemServiceUnavailable, Code: SystemServiceUnavailable, Message: "Service is temporarily unavailable", Meta: pkgErrors.Unavailable},
+	pkgErrors.Entry{Err: ErrSystemTimeout, Code: SystemTimeout, Message: "Request timeout", Meta: pkgErrors.Timeout},
+)
+
+// GetErrorInfo returns the ErrorInfo for a given error
+func GetErrorInfo(err error) pkgErrors.ErrorInfo {
+	return Catalog.GetErrorInfo(err)
+}
+
+// IsValidationError checks if the error is a validation error
+func IsValidationError(err error) bool {
+	return Catalog.IsValidationError(err)
+}
+
+// IsDatabaseError checks if the error is a database error
+func IsDatabaseError(err error) bool {
+	return Catalog.IsDatabaseError(err)
+}
+
+// NewNotificationErrorCatalog returns the catalog the HTTP error handler reports errors with
+func NewNotificationErrorCatalog() pkgErrors.ErrorCatalog {
+	return Catalog
+}
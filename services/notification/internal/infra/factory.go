@@ -0,0 +1,137 @@
+package infra
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	_ "github.com/lib/pq"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/services/notification/internal/channel"
+	"github.com/robrt95x/godops/services/notification/internal/config"
+	"github.com/robrt95x/godops/services/notification/internal/infra/memory"
+	"github.com/robrt95x/godops/services/notification/internal/infra/postgres"
+	"github.com/robrt95x/godops/services/notification/internal/infra/sender"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+)
+
+type RepositoryFactory struct {
+	config *config.Config
+	db     *sql.DB
+}
+
+func NewRepositoryFactory(config *config.Config) *RepositoryFactory {
+	return &RepositoryFactory{config: config}
+}
+
+func (f *RepositoryFactory) CreateDeliveryRepository() (repository.DeliveryRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for delivery attempts")
+		return memory.NewDeliveryMemoryRepository(), nil
+
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for delivery attempts")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewDeliveryPostgresRepository(db), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+// CreateChannels returns the channels named in NOTIFICATION_CHANNELS, in order
+func (f *RepositoryFactory) CreateChannels() ([]channel.Channel, error) {
+	channels := make([]channel.Channel, 0, len(f.config.Channels))
+	for _, name := range f.config.Channels {
+		switch name {
+		case "smtp":
+			log.Printf("Delivering notifications over SMTP via %s:%s", f.config.SMTPHost, f.config.SMTPPort)
+			channels = append(channels, sender.NewSMTPSender(f.config.SMTPHost, f.config.SMTPPort, f.config.SMTPFrom))
+
+		case "webhook":
+			if f.config.WebhookURL == "" {
+				return nil, fmt.Errorf("webhook channel requires WEBHOOK_URL")
+			}
+			log.Printf("Delivering notifications to webhook %s", f.config.WebhookURL)
+			channels = append(channels, sender.NewWebhookSender(f.config.WebhookURL, &http.Client{Timeout: f.config.DeliveryTimeout}))
+
+		case "file":
+			writer, err := f.fileSink()
+			if err != nil {
+				return nil, err
+			}
+			log.Printf("Writing notifications to %s", f.config.FileSinkPath)
+			channels = append(channels, sender.NewFileSender(writer))
+
+		default:
+			return nil, fmt.Errorf("unsupported notification channel: %s", name)
+		}
+	}
+
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("no notification channels configured")
+	}
+	return channels, nil
+}
+
+// fileSink opens FILE_SINK_PATH for appending; "-" writes to stdout
+func (f *RepositoryFactory) fileSink() (io.Writer, error) {
+	if f.config.FileSinkPath == "-" {
+		return os.Stdout, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(f.config.FileSinkPath), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create notification sink directory: %w", err)
+	}
+	return os.OpenFile(f.config.FileSinkPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
+
+// CreateMigrator returns the schema migrator; only postgres storage has a schema
+func (f *RepositoryFactory) CreateMigrator() (*db.Migrator, error) {
+	if !f.config.IsPostgresStorage() {
+		return nil, fmt.Errorf("migrations require postgres storage, got: %s", f.config.StorageType)
+	}
+
+	conn, err := f.postgresConnection()
+	if err != nil {
+		return nil, err
+	}
+	return postgres.NewMigrator(conn)
+}
+
+// postgresConnection opens the shared connection pool on first use
+func (f *RepositoryFactory) postgresConnection() (*sql.DB, error) {
+	if f.db != nil {
+		return f.db, nil
+	}
+
+	db, err := f.createPostgresConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection: %w", err)
+	}
+	f.db = db
+	return db, nil
+}
+
+func (f *RepositoryFactory) createPostgresConnection() (*sql.DB, error) {
+	db, err := sql.Open("postgres", f.config.GetDatabaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	log.Printf("Connected to PostgreSQL at %s:%s", f.config.DBHost, f.config.DBPort)
+	return db, nil
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+)
+
+type DeliveryMemoryRepository struct {
+	attempts []entity.DeliveryAttempt
+	mutex    sync.RWMutex
+}
+
+func NewDeliveryMemoryRepository() *DeliveryMemoryRepository {
+	return &DeliveryMemoryRepository{}
+}
+
+func (r *DeliveryMemoryRepository) Save(ctx context.Context, attempt *entity.DeliveryAttempt) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func (r *DeliveryMemoryRepository) Attempts(ctx context.Context, eventID, channel string) (bool, int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	sent, failed := false, 0
+	for _, attempt := range r.attempts {
+		if attempt.EventID != eventID || attempt.Channel != channel {
+			continue
+		}
+		switch attempt.Status {
+		case entity.Sent:
+			sent = true
+		case entity.Failed:
+			failed++
+		}
+	}
+	return sent, failed, nil
+}
+
+func (r *DeliveryMemoryRepository) List(ctx context.Context, filter repository.DeliveryFilter) ([]*entity.DeliveryAttempt, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	result := make([]*entity.DeliveryAttempt, 0)
+	for i := len(r.attempts) - 1; i >= 0; i-- {
+		attempt := r.attempts[i]
+		if filter.EventID != "" && attempt.EventID != filter.EventID {
+			continue
+		}
+		if filter.Status != "" && attempt.Status != filter.Status {
+			continue
+		}
+		result = append(result, &attempt)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].AttemptedAt.After(result[j].AttemptedAt)
+	})
+	if filter.Limit > 0 && len(result) > filter.Limit {
+		result = result[:filter.Limit]
+	}
+	return result, nil
+}
@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+)
+
+const deliveryColumns = `id, event_id, event_type, channel, recipient, locale, subject, status, error, attempted_at`
+
+type DeliveryPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewDeliveryPostgresRepository(db *sql.DB) *DeliveryPostgresRepository {
+	return &DeliveryPostgresRepository{db: db}
+}
+
+func (r *DeliveryPostgresRepository) Save(ctx context.Context, attempt *entity.DeliveryAttempt) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO delivery_attempts (`+deliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		attempt.ID,
+		attempt.EventID,
+		attempt.EventType,
+		attempt.Channel,
+		attempt.Recipient,
+		attempt.Locale,
+		attempt.Subject,
+		attempt.Status,
+		attempt.Error,
+		attempt.AttemptedAt,
+	)
+
+	return err
+}
+
+func (r *DeliveryPostgresRepository) Attempts(ctx context.Context, eventID, channel string) (bool, int, error) {
+	var sent bool
+	var failed int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COALESCE(bool_or(status = $3), false), COUNT(*) FILTER (WHERE status = $4)
+		FROM delivery_attempts WHERE event_id = $1 AND channel = $2`,
+		eventID, channel, entity.Sent, entity.Failed,
+	).Scan(&sent, &failed)
+	return sent, failed, err
+}
+
+func (r *DeliveryPostgresRepository) List(ctx context.Context, filter repository.DeliveryFilter) ([]*entity.DeliveryAttempt, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]interface{}, 0, 3)
+	if filter.EventID != "" {
+		args = append(args, filter.EventID)
+		conditions = append(conditions, fmt.Sprintf("event_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT ` + deliveryColumns + ` FROM delivery_attempts`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY attempted_at DESC, id`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attempts := make([]*entity.DeliveryAttempt, 0)
+	for rows.Next() {
+		var attempt entity.DeliveryAttempt
+		if err := rows.Scan(
+			&attempt.ID,
+			&attempt.EventID,
+			&attempt.EventType,
+			&attempt.Channel,
+			&attempt.Recipient,
+			&attempt.Locale,
+			&attempt.Subject,
+			&attempt.Status,
+			&attempt.Error,
+			&attempt.AttemptedAt,
+		); err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, rows.Err()
+}
@@ -0,0 +1,15 @@
+CREATE TABLE delivery_attempts (
+    id TEXT PRIMARY KEY,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    status TEXT NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    attempted_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX delivery_attempts_event_idx ON delivery_attempts (event_id, channel);
+CREATE INDEX delivery_attempts_attempted_at_idx ON delivery_attempts (attempted_at DESC);
@@ -0,0 +1,59 @@
+package sender
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+// FileSender appends each message as a JSON line to a writer, for development
+type FileSender struct {
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func NewFileSender(writer io.Writer) *FileSender {
+	return &FileSender{writer: writer}
+}
+
+type fileRecord struct {
+	SentAt    time.Time `json:"sent_at"`
+	EventID   string    `json:"event_id"`
+	EventType string    `json:"event_type"`
+	UserID    string    `json:"user_id"`
+	Email     string    `json:"email,omitempty"`
+	Locale    string    `json:"locale"`
+	Subject   string    `json:"subject"`
+	Text      string    `json:"text"`
+	HTML      string    `json:"html,omitempty"`
+}
+
+func (s *FileSender) Name() string {
+	return "file"
+}
+
+func (s *FileSender) Send(ctx context.Context, message entity.Message) error {
+	line, err := json.Marshal(fileRecord{
+		SentAt:    time.Now().UTC(),
+		EventID:   message.EventID,
+		EventType: message.EventType,
+		UserID:    message.Recipient.UserID,
+		Email:     message.Recipient.Email,
+		Locale:    message.Locale,
+		Subject:   message.Subject,
+		Text:      message.TextBody,
+		HTML:      message.HTMLBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, err = s.writer.Write(append(line, '\n'))
+	return err
+}
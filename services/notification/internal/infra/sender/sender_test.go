@@ -0,0 +1,132 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+func newTestMessage(eventID string) entity.Message {
+	return entity.Message{
+		EventID:   eventID,
+		EventType: "order.created",
+		Recipient: entity.Recipient{UserID: "user-1", Email: "user-1@example.com", Locale: "es"},
+		Locale:    "es",
+		Subject:   "Pedido order-1 recibido",
+		TextBody:  "Gracias",
+		HTMLBody:  "<p>Gracias</p>",
+	}
+}
+
+func TestWebhookSender_Send(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should post the message with the event as idempotency key", func(t *testing.T) {
+		var received webhookPayload
+		var idempotencyKey, contentType string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey, contentType = r.Header.Get("Idempotency-Key"), r.Header.Get("Content-Type")
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				t.Errorf("Failed to decode webhook body: %v", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		if err := NewWebhookSender(server.URL, server.Client()).Send(ctx, newTestMessage("event-1")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if idempotencyKey != "event-1" || contentType != "application/json" {
+			t.Errorf("Expected idempotency key event-1 and JSON, got %q and %q", idempotencyKey, contentType)
+		}
+		expected := webhookPayload{
+			EventID:   "event-1",
+			EventType: "order.created",
+			UserID:    "user-1",
+			Email:     "user-1@example.com",
+			Locale:    "es",
+			Subject:   "Pedido order-1 recibido",
+			Text:      "Gracias",
+			HTML:      "<p>Gracias</p>",
+		}
+		if received != expected {
+			t.Errorf("Expected %+v, got %+v", expected, received)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		status int
+	}{
+		{"client error", http.StatusBadRequest},
+		{"server error", http.StatusBadGateway},
+	}
+
+	for _, tt := range tests {
+		t.Run("should fail on a "+tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookSender(server.URL, server.Client()).Send(ctx, newTestMessage("event-1"))
+			if err == nil || !strings.Contains(err.Error(), http.StatusText(tt.status)) {
+				t.Errorf("Expected an error naming %d, got %v", tt.status, err)
+			}
+		})
+	}
+
+	t.Run("should fail when the webhook is unreachable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		if err := NewWebhookSender(server.URL, nil).Send(ctx, newTestMessage("event-1")); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestFileSender_Send(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	sender := NewFileSender(&buf)
+
+	var wg sync.WaitGroup
+	for _, id := range []string{"event-1", "event-2", "event-3"} {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if err := sender.Send(ctx, newTestMessage(id)); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %q", len(lines), buf.String())
+	}
+	seen := make(map[string]bool)
+	for _, line := range lines {
+		var record fileRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Expected each line to be a JSON record, got %q: %v", line, err)
+		}
+		if record.SentAt.IsZero() || record.UserID != "user-1" || record.Subject != "Pedido order-1 recibido" || record.HTML != "<p>Gracias</p>" {
+			t.Errorf("Expected the message fields to be recorded, got %+v", record)
+		}
+		seen[record.EventID] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected one record per event, got %v", seen)
+	}
+}
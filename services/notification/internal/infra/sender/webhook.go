@@ -0,0 +1,76 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+// WebhookSender POSTs messages as JSON to a fixed URL
+type WebhookSender struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSender(url string, client *http.Client) *WebhookSender {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &WebhookSender{url: url, client: client}
+}
+
+type webhookPayload struct {
+	EventID   string `json:"event_id"`
+	EventType string `json:"event_type"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email,omitempty"`
+	Locale    string `json:"locale"`
+	Subject   string `json:"subject"`
+	Text      string `json:"text"`
+	HTML      string `json:"html,omitempty"`
+}
+
+func (s *WebhookSender) Name() string {
+	return "webhook"
+}
+
+func (s *WebhookSender) Send(ctx context.Context, message entity.Message) error {
+	body, err := json.Marshal(webhookPayload{
+		EventID:   message.EventID,
+		EventType: message.EventType,
+		UserID:    message.Recipient.UserID,
+		Email:     message.Recipient.Email,
+		Locale:    message.Locale,
+		Subject:   message.Subject,
+		Text:      message.TextBody,
+		HTML:      message.HTMLBody,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Lets the receiver drop the duplicates at-least-once delivery can produce
+	req.Header.Set("Idempotency-Key", message.EventID)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %s", resp.Status)
+	}
+	return nil
+}
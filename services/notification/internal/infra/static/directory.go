@@ -0,0 +1,28 @@
+package static
+
+import (
+	"context"
+	"strings"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+// Directory addresses every user with the same locale and an email built from a
+// pattern, until notification preferences are stored somewhere
+type Directory struct {
+	emailPattern string
+	locale       string
+}
+
+// NewDirectory returns a directory replacing {user_id} in emailPattern
+func NewDirectory(emailPattern, locale string) *Directory {
+	return &Directory{emailPattern: emailPattern, locale: locale}
+}
+
+func (d *Directory) Resolve(ctx context.Context, userID string) (entity.Recipient, error) {
+	return entity.Recipient{
+		UserID: userID,
+		Email:  strings.ReplaceAll(d.emailPattern, "{user_id}", userID),
+		Locale: d.locale,
+	}, nil
+}
@@ -0,0 +1,12 @@
+package recipient
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+// Directory resolves the user named in an event to a notification recipient
+type Directory interface {
+	Resolve(ctx context.Context, userID string) (entity.Recipient, error)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+)
+
+type DeliveryRepository interface {
+	Save(ctx context.Context, attempt *entity.DeliveryAttempt) error
+	// Attempts reports whether the event was already delivered successfully over
+	// channel and how many attempts to deliver it there failed
+	Attempts(ctx context.Context, eventID, channel string) (sent bool, failed int, err error)
+	List(ctx context.Context, filter DeliveryFilter) ([]*entity.DeliveryAttempt, error)
+}
+
+// DeliveryFilter selects delivery attempts. Zero values disable a filter.
+// Results are ordered by AttemptedAt, newest first.
+type DeliveryFilter struct {
+	EventID string
+	Status  entity.DeliveryStatus
+	Limit   int
+}
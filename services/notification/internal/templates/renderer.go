@@ -0,0 +1,205 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmlTemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	textTemplate "text/template"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+)
+
+// Template files live at files/<event type>/<locale>/ as subject.txt and
+// body.txt, plus an optional body.html
+//
+//go:embed files
+var embedded embed.FS
+
+const (
+	subjectFile  = "subject.txt"
+	textBodyFile = "body.txt"
+	htmlBodyFile = "body.html"
+)
+
+// Data is what templates are executed with. Payload is the event payload
+// decoded as a JSON object, with numbers kept as json.Number.
+type Data struct {
+	EventID    string
+	EventType  string
+	OccurredAt time.Time
+	Recipient  entity.Recipient
+	Payload    map[string]interface{}
+}
+
+type templateSet struct {
+	subject *textTemplate.Template
+	text    *textTemplate.Template
+	html    *htmlTemplate.Template
+}
+
+// Renderer renders messages per event type and locale, falling back from a
+// regional locale to its language and then to the default locale
+type Renderer struct {
+	sets          map[string]map[string]*templateSet
+	defaultLocale string
+}
+
+// NewEmbeddedRenderer returns a renderer for the templates shipped with the service
+func NewEmbeddedRenderer(defaultLocale string) (*Renderer, error) {
+	files, err := fs.Sub(embedded, "files")
+	if err != nil {
+		return nil, err
+	}
+	return NewRenderer(files, defaultLocale)
+}
+
+// NewRenderer parses every <event type>/<locale> directory of files. Each event
+// type must have templates for defaultLocale.
+func NewRenderer(files fs.FS, defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		sets:          make(map[string]map[string]*templateSet),
+		defaultLocale: normalizeLocale(defaultLocale),
+	}
+
+	eventTypes, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, err
+	}
+	for _, eventType := range eventTypes {
+		if !eventType.IsDir() {
+			continue
+		}
+		locales, err := fs.ReadDir(files, eventType.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		r.sets[eventType.Name()] = make(map[string]*templateSet)
+		for _, locale := range locales {
+			if !locale.IsDir() {
+				continue
+			}
+			set, err := parseSet(files, path.Join(eventType.Name(), locale.Name()))
+			if err != nil {
+				return nil, err
+			}
+			r.sets[eventType.Name()][normalizeLocale(locale.Name())] = set
+		}
+
+		if _, exists := r.sets[eventType.Name()][r.defaultLocale]; !exists {
+			return nil, fmt.Errorf("templates for %s have no default locale %q", eventType.Name(), r.defaultLocale)
+		}
+	}
+
+	return r, nil
+}
+
+// Render renders the templates for eventType in the closest available locale
+func (r *Renderer) Render(eventType, locale string, data Data) (entity.Message, error) {
+	locales, exists := r.sets[eventType]
+	if !exists {
+		return entity.Message{}, errors.ErrNotificationTemplateNotFound
+	}
+
+	resolved := r.resolveLocale(locales, locale)
+	set := locales[resolved]
+
+	message := entity.Message{
+		EventID:   data.EventID,
+		EventType: eventType,
+		Recipient: data.Recipient,
+		Locale:    resolved,
+	}
+
+	var buf bytes.Buffer
+	if err := set.subject.Execute(&buf, data); err != nil {
+		return entity.Message{}, fmt.Errorf("rendering %s subject: %w", eventType, err)
+	}
+	// Subjects are a single header line
+	message.Subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := set.text.Execute(&buf, data); err != nil {
+		return entity.Message{}, fmt.Errorf("rendering %s text body: %w", eventType, err)
+	}
+	message.TextBody = buf.String()
+
+	if set.html != nil {
+		buf.Reset()
+		if err := set.html.Execute(&buf, data); err != nil {
+			return entity.Message{}, fmt.Errorf("rendering %s html body: %w", eventType, err)
+		}
+		message.HTMLBody = buf.String()
+	}
+
+	return message, nil
+}
+
+// resolveLocale tries "es-mx", then "es", then the default locale
+func (r *Renderer) resolveLocale(available map[string]*templateSet, locale string) string {
+	locale = normalizeLocale(locale)
+	candidates := []string{locale}
+	if language, _, found := strings.Cut(locale, "-"); found {
+		candidates = append(candidates, language)
+	}
+
+	for _, candidate := range candidates {
+		if _, exists := available[candidate]; exists {
+			return candidate
+		}
+	}
+	return r.defaultLocale
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func parseSet(files fs.FS, dir string) (*templateSet, error) {
+	subject, err := textTemplate.New(subjectFile).Funcs(textTemplate.FuncMap(funcs)).ParseFS(files, path.Join(dir, subjectFile))
+	if err != nil {
+		return nil, err
+	}
+	text, err := textTemplate.New(textBodyFile).Funcs(textTemplate.FuncMap(funcs)).ParseFS(files, path.Join(dir, textBodyFile))
+	if err != nil {
+		return nil, err
+	}
+
+	set := &templateSet{subject: subject, text: text}
+	if _, err := fs.Stat(files, path.Join(dir, htmlBodyFile)); err == nil {
+		set.html, err = htmlTemplate.New(htmlBodyFile).Funcs(htmlTemplate.FuncMap(funcs)).ParseFS(files, path.Join(dir, htmlBodyFile))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// funcs are available to every template
+var funcs = map[string]interface{}{
+	// money formats a minor-unit amount, e.g. {{money .Payload.total_amount .Payload.currency}}
+	"money": func(amount interface{}, currency interface{}) string {
+		code, _ := currency.(string)
+		var minor int64
+		switch value := amount.(type) {
+		case json.Number:
+			minor, _ = value.Int64()
+		case int64:
+			minor = value
+		case int:
+			minor = int64(value)
+		case float64:
+			minor = int64(value)
+		}
+		return money.Money{Amount: minor, Currency: code}.String()
+	},
+}
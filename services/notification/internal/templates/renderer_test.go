@@ -0,0 +1,126 @@
+package templates_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/templates"
+)
+
+func newData(t *testing.T, payload string) templates.Data {
+	t.Helper()
+	data := templates.Data{EventID: "event-1", Recipient: entity.Recipient{UserID: "user-1"}}
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&data.Payload); err != nil {
+		t.Fatalf("Failed to decode payload: %v", err)
+	}
+	return data
+}
+
+func TestRenderer_Render(t *testing.T) {
+	renderer, err := templates.NewEmbeddedRenderer("en")
+	if err != nil {
+		t.Fatalf("Expected the embedded templates to load, got %v", err)
+	}
+	data := newData(t, `{"order_id": "order-1", "items": [], "total_amount": 5998, "currency": "USD"}`)
+
+	tests := []struct {
+		name            string
+		locale          string
+		expectedLocale  string
+		expectedSubject string
+	}{
+		{"exact locale", "es", "es", "Pedido order-1 recibido"},
+		{"regional locale falls back to its language", "es-MX", "es", "Pedido order-1 recibido"},
+		{"underscore locale is normalized", "es_AR", "es", "Pedido order-1 recibido"},
+		{"unknown locale falls back to the default", "fr-FR", "en", "Order order-1 received"},
+		{"empty locale uses the default", "", "en", "Order order-1 received"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := renderer.Render("order.created", tt.locale, data)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if message.Locale != tt.expectedLocale {
+				t.Errorf("Expected locale %s, got %s", tt.expectedLocale, message.Locale)
+			}
+			if message.Subject != tt.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", tt.expectedSubject, message.Subject)
+			}
+			if message.EventID != "event-1" || message.Recipient.UserID != "user-1" {
+				t.Errorf("Expected the event and recipient to be kept, got %+v", message)
+			}
+		})
+	}
+
+	t.Run("missing template", func(t *testing.T) {
+		if _, err := renderer.Render("order.shipped", "en", data); err != errors.ErrNotificationTemplateNotFound {
+			t.Errorf("Expected %v, got %v", errors.ErrNotificationTemplateNotFound, err)
+		}
+	})
+
+	t.Run("formats money and escapes html", func(t *testing.T) {
+		message, err := renderer.Render("order.created", "en", newData(t, `{"order_id": "<b>order-1</b>", "items": [], "total_amount": 5998, "currency": "USD"}`))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(message.TextBody, "Total: 59.98 USD") {
+			t.Errorf("Expected the formatted total in the text body, got %q", message.TextBody)
+		}
+		if strings.Contains(message.HTMLBody, "<b>order-1</b>") || !strings.Contains(message.HTMLBody, "&lt;b&gt;order-1&lt;/b&gt;") {
+			t.Errorf("Expected the payload to be escaped in the html body, got %q", message.HTMLBody)
+		}
+	})
+}
+
+func TestNewRenderer(t *testing.T) {
+	t.Run("subject is a single line and html is optional", func(t *testing.T) {
+		renderer, err := templates.NewRenderer(fstest.MapFS{
+			"order.created/en/subject.txt": {Data: []byte("Order\n  {{.Payload.order_id}}\n")},
+			"order.created/en/body.txt":    {Data: []byte("Hello {{.Recipient.UserID}}")},
+		}, "en")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		message, err := renderer.Render("order.created", "en", newData(t, `{"order_id": "order-1"}`))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if message.Subject != "Order order-1" || message.TextBody != "Hello user-1" || message.HTMLBody != "" {
+			t.Errorf("Expected a plain message, got %+v", message)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"no default locale", fstest.MapFS{
+			"order.created/es/subject.txt": {Data: []byte("Pedido")},
+			"order.created/es/body.txt":    {Data: []byte("Hola")},
+		}},
+		{"missing body", fstest.MapFS{
+			"order.created/en/subject.txt": {Data: []byte("Order")},
+		}},
+		{"invalid template", fstest.MapFS{
+			"order.created/en/subject.txt": {Data: []byte("Order {{.Payload.order_id")},
+			"order.created/en/body.txt":    {Data: []byte("Hello")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			if _, err := templates.NewRenderer(tt.files, "en"); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
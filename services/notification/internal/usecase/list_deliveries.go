@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultDeliveryLimit = 50
+	MaxDeliveryLimit     = 200
+)
+
+type ListDeliveriesCase struct {
+	repository repository.DeliveryRepository
+	logger     *logrus.Logger
+}
+
+func NewListDeliveriesCase(repository repository.DeliveryRepository, logger *logrus.Logger) *ListDeliveriesCase {
+	return &ListDeliveriesCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *ListDeliveriesCase) Execute(ctx context.Context, filter repository.DeliveryFilter) ([]*entity.DeliveryAttempt, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case": "ListDeliveries",
+		"event_id": filter.EventID,
+		"status":   filter.Status,
+	})
+
+	logEntry.Debug("Starting list deliveries use case")
+
+	if filter.Status != "" && filter.Status != entity.Sent && filter.Status != entity.Failed {
+		logEntry.Warning("List deliveries failed: unknown status")
+		return nil, errors.ErrValidationInvalidRequest
+	}
+	if filter.Limit < 0 || filter.Limit > MaxDeliveryLimit {
+		logEntry.WithField("limit", filter.Limit).Warning("List deliveries failed: invalid limit")
+		return nil, errors.ErrValidationInvalidRequest
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultDeliveryLimit
+	}
+
+	attempts, err := uc.repository.List(ctx, filter)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to list delivery attempts from repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	logEntry.WithField("count", len(attempts)).Info("Delivery attempts listed successfully")
+	return attempts, nil
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/services/notification/internal/channel"
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/recipient"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+	"github.com/robrt95x/godops/services/notification/internal/templates"
+	"github.com/sirupsen/logrus"
+)
+
+type NotifyCase struct {
+	repository  repository.DeliveryRepository
+	directory   recipient.Directory
+	renderer    *templates.Renderer
+	channels    []channel.Channel
+	maxAttempts int
+	logger      *logrus.Logger
+}
+
+func NewNotifyCase(repository repository.DeliveryRepository, directory recipient.Directory, renderer *templates.Renderer, channels []channel.Channel, maxAttempts int, logger *logrus.Logger) *NotifyCase {
+	return &NotifyCase{
+		repository:  repository,
+		directory:   directory,
+		renderer:    renderer,
+		channels:    channels,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// Execute renders the notification for an event and sends it over every
+// channel, recording each attempt. Channels that already delivered the event
+// are skipped, so a redelivered event only retries the channels that failed;
+// a channel that failed maxAttempts times is given up on.
+func (uc *NotifyCase) Execute(ctx context.Context, envelope events.Envelope) error {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "Notify",
+		"event_id":   envelope.ID,
+		"event_type": envelope.Type,
+	})
+
+	logEntry.Debug("Starting notify use case")
+
+	if envelope.ID == "" {
+		logEntry.Warning("Notify failed: missing event ID")
+		return errors.ErrValidationMissingEventID
+	}
+
+	payload, userID, err := decodePayload(envelope)
+	if err != nil {
+		logEntry.WithError(err).Warning("Notify failed: invalid event payload")
+		return errors.ErrValidationInvalidPayload
+	}
+
+	to, err := uc.directory.Resolve(ctx, userID)
+	if err != nil {
+		logEntry.WithError(err).WithField("user_id", userID).Warning("Notify failed: recipient not resolved")
+		return errors.ErrNotificationRecipientUnknown
+	}
+
+	message, err := uc.renderer.Render(envelope.Type, to.Locale, templates.Data{
+		EventID:    envelope.ID,
+		EventType:  envelope.Type,
+		OccurredAt: envelope.OccurredAt,
+		Recipient:  to,
+		Payload:    payload,
+	})
+	if err == errors.ErrNotificationTemplateNotFound {
+		logEntry.Warning("Notify failed: no template for event type")
+		return err
+	}
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to render notification")
+		return errors.ErrSystemInternal
+	}
+
+	logEntry = logEntry.WithFields(logrus.Fields{
+		"user_id": userID,
+		"locale":  message.Locale,
+	})
+
+	failed := 0
+	for _, ch := range uc.channels {
+		channelEntry := logEntry.WithField("channel", ch.Name())
+
+		sent, failures, err := uc.repository.Attempts(ctx, envelope.ID, ch.Name())
+		if err != nil {
+			channelEntry.WithError(err).Error("Failed to check delivery history")
+			return repositoryError(ctx, err)
+		}
+		if sent {
+			channelEntry.Debug("Notification already delivered, skipping channel")
+			continue
+		}
+		if failures >= uc.maxAttempts {
+			channelEntry.WithField("attempts", failures).Error("Gave up delivering notification on channel")
+			continue
+		}
+
+		attempt := &entity.DeliveryAttempt{
+			ID:        uuid.New().String(),
+			EventID:   envelope.ID,
+			EventType: envelope.Type,
+			Channel:   ch.Name(),
+			Recipient: recipientAddress(to),
+			Locale:    message.Locale,
+			Subject:   message.Subject,
+			Status:    entity.Sent,
+		}
+
+		if sendErr := ch.Send(ctx, message); sendErr != nil {
+			attempt.Status = entity.Failed
+			attempt.Error = sendErr.Error()
+			failed++
+			channelEntry.WithError(sendErr).Warning("Notification delivery failed")
+		} else {
+			channelEntry.Info("Notification delivered")
+		}
+		attempt.AttemptedAt = time.Now()
+
+		if err := uc.repository.Save(ctx, attempt); err != nil {
+			channelEntry.WithError(err).Error("Failed to record delivery attempt")
+			return repositoryError(ctx, err)
+		}
+	}
+
+	if failed > 0 {
+		return errors.ErrNotificationDeliveryFailed
+	}
+	return nil
+}
+
+// decodePayload returns the payload as a JSON object and the user it concerns
+func decodePayload(envelope events.Envelope) (map[string]interface{}, string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(envelope.Payload))
+	decoder.UseNumber()
+
+	var payload map[string]interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, "", err
+	}
+
+	userID, _ := payload["user_id"].(string)
+	if userID == "" {
+		return nil, "", errors.ErrValidationInvalidPayload
+	}
+	return payload, userID, nil
+}
+
+func recipientAddress(to entity.Recipient) string {
+	if to.Email != "" {
+		return to.Email
+	}
+	return to.UserID
+}
@@ -0,0 +1,243 @@
+package usecase_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/events"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/services/notification/internal/channel"
+	"github.com/robrt95x/godops/services/notification/internal/entity"
+	"github.com/robrt95x/godops/services/notification/internal/errors"
+	"github.com/robrt95x/godops/services/notification/internal/infra/memory"
+	"github.com/robrt95x/godops/services/notification/internal/infra/static"
+	"github.com/robrt95x/godops/services/notification/internal/repository"
+	"github.com/robrt95x/godops/services/notification/internal/templates"
+	"github.com/robrt95x/godops/services/notification/internal/usecase"
+)
+
+// recordingChannel keeps the messages it is sent and fails while err is set
+type recordingChannel struct {
+	name     string
+	err      error
+	messages []entity.Message
+	mutex    sync.Mutex
+}
+
+func (c *recordingChannel) Name() string { return c.name }
+
+func (c *recordingChannel) Send(ctx context.Context, message entity.Message) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	c.messages = append(c.messages, message)
+	return nil
+}
+
+func newOrderCreated(t *testing.T, couponCode string) events.Envelope {
+	t.Helper()
+	envelope, err := events.NewEnvelope(events.OrderCreatedEvent, events.OrderAggregate, "order-1", events.OrderCreated{
+		OrderID:        "order-1",
+		UserID:         "user-1",
+		Items:          []events.OrderLineItem{{ProductID: "product-1", Quantity: 2, UnitPriceAmount: 2999}},
+		CouponCode:     couponCode,
+		SubtotalAmount: 5998,
+		TotalAmount:    5998,
+		Currency:       "USD",
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return envelope
+}
+
+func newNotifyCase(t *testing.T, locale string, channels ...channel.Channel) (*usecase.NotifyCase, *memory.DeliveryMemoryRepository) {
+	t.Helper()
+	renderer, err := templates.NewEmbeddedRenderer("en")
+	if err != nil {
+		t.Fatalf("Expected templates to load, got %v", err)
+	}
+	repo := memory.NewDeliveryMemoryRepository()
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	return usecase.NewNotifyCase(repo, static.NewDirectory("{user_id}@example.com", locale), renderer, channels, 3, testLogger), repo
+}
+
+func TestNotifyCase_Execute(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should deliver on every channel and record each attempt", func(t *testing.T) {
+		email := &recordingChannel{name: "smtp"}
+		file := &recordingChannel{name: "file"}
+		uc, repo := newNotifyCase(t, "en", email, file)
+
+		envelope := newOrderCreated(t, "")
+		if err := uc.Execute(ctx, envelope); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if len(email.messages) != 1 || len(file.messages) != 1 {
+			t.Fatalf("Expected one message per channel, got %d and %d", len(email.messages), len(file.messages))
+		}
+		message := email.messages[0]
+		if message.Subject != "Order order-1 received" {
+			t.Errorf("Expected subject %q, got %q", "Order order-1 received", message.Subject)
+		}
+		if !strings.Contains(message.TextBody, "2 x product-1 at 29.99 USD") || !strings.Contains(message.TextBody, "Total: 59.98 USD") {
+			t.Errorf("Expected line items and total in text body, got %q", message.TextBody)
+		}
+		if message.HTMLBody == "" || message.Recipient.Email != "user-1@example.com" {
+			t.Errorf("Expected an HTML body for user-1@example.com, got %+v", message)
+		}
+
+		attempts, _ := repo.List(ctx, repository.DeliveryFilter{EventID: envelope.ID})
+		if len(attempts) != 2 {
+			t.Fatalf("Expected 2 attempts, got %d", len(attempts))
+		}
+		for _, attempt := range attempts {
+			if attempt.Status != entity.Sent || attempt.Recipient != "user-1@example.com" {
+				t.Errorf("Expected a sent attempt to user-1@example.com, got %+v", attempt)
+			}
+		}
+	})
+
+	t.Run("should record failures and only retry failed channels", func(t *testing.T) {
+		email := &recordingChannel{name: "smtp", err: stdErrors.New("connection refused")}
+		file := &recordingChannel{name: "file"}
+		uc, repo := newNotifyCase(t, "en", email, file)
+
+		envelope := newOrderCreated(t, "")
+		if err := uc.Execute(ctx, envelope); err != errors.ErrNotificationDeliveryFailed {
+			t.Fatalf("Expected %v, got %v", errors.ErrNotificationDeliveryFailed, err)
+		}
+
+		failed, _ := repo.List(ctx, repository.DeliveryFilter{EventID: envelope.ID, Status: entity.Failed})
+		if len(failed) != 1 || failed[0].Channel != "smtp" || failed[0].Error != "connection refused" {
+			t.Fatalf("Expected one failed smtp attempt, got %+v", failed)
+		}
+
+		email.err = nil
+		if err := uc.Execute(ctx, envelope); err != nil {
+			t.Fatalf("Expected no error on redelivery, got %v", err)
+		}
+		if len(email.messages) != 1 || len(file.messages) != 1 {
+			t.Errorf("Expected each channel to deliver once, got %d and %d", len(email.messages), len(file.messages))
+		}
+
+		attempts, _ := repo.List(ctx, repository.DeliveryFilter{EventID: envelope.ID})
+		if len(attempts) != 3 {
+			t.Errorf("Expected 3 attempts, got %d", len(attempts))
+		}
+	})
+
+	t.Run("should give up on a channel after the maximum attempts", func(t *testing.T) {
+		email := &recordingChannel{name: "smtp", err: stdErrors.New("connection refused")}
+		uc, repo := newNotifyCase(t, "en", email)
+
+		envelope := newOrderCreated(t, "")
+		for i := 0; i < 3; i++ {
+			if err := uc.Execute(ctx, envelope); err != errors.ErrNotificationDeliveryFailed {
+				t.Fatalf("Expected %v on attempt %d, got %v", errors.ErrNotificationDeliveryFailed, i+1, err)
+			}
+		}
+
+		// The event is settled without trying the channel again
+		if err := uc.Execute(ctx, envelope); err != nil {
+			t.Fatalf("Expected no error once the attempts are used up, got %v", err)
+		}
+		if attempts, _ := repo.List(ctx, repository.DeliveryFilter{EventID: envelope.ID}); len(attempts) != 3 {
+			t.Errorf("Expected 3 attempts, got %d", len(attempts))
+		}
+	})
+
+	t.Run("should escape payload values in HTML only", func(t *testing.T) {
+		email := &recordingChannel{name: "smtp"}
+		uc, _ := newNotifyCase(t, "en", email)
+
+		if err := uc.Execute(ctx, newOrderCreated(t, "<b>SAVE</b>")); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		message := email.messages[0]
+		if !strings.Contains(message.HTMLBody, "&lt;b&gt;SAVE&lt;/b&gt;") {
+			t.Errorf("Expected the coupon code escaped in HTML, got %q", message.HTMLBody)
+		}
+		if !strings.Contains(message.TextBody, "Coupon <b>SAVE</b>") {
+			t.Errorf("Expected the coupon code verbatim in text, got %q", message.TextBody)
+		}
+	})
+
+	t.Run("should reject events without a template", func(t *testing.T) {
+		email := &recordingChannel{name: "smtp"}
+		uc, repo := newNotifyCase(t, "en", email)
+
+		envelope := newOrderCreated(t, "")
+		envelope.Type = "order.archived"
+		if err := uc.Execute(ctx, envelope); err != errors.ErrNotificationTemplateNotFound {
+			t.Fatalf("Expected %v, got %v", errors.ErrNotificationTemplateNotFound, err)
+		}
+		if attempts, _ := repo.List(ctx, repository.DeliveryFilter{}); len(attempts) != 0 {
+			t.Errorf("Expected no attempts, got %d", len(attempts))
+		}
+	})
+
+	t.Run("should reject payloads without a user", func(t *testing.T) {
+		uc, _ := newNotifyCase(t, "en", &recordingChannel{name: "smtp"})
+
+		envelope := newOrderCreated(t, "")
+		envelope.Payload = []byte(`{"order_id": "order-1"}`)
+		if err := uc.Execute(ctx, envelope); err != errors.ErrValidationInvalidPayload {
+			t.Fatalf("Expected %v, got %v", errors.ErrValidationInvalidPayload, err)
+		}
+	})
+}
+
+func TestNotifyCase_Locales(t *testing.T) {
+	ctx := context.Background()
+
+	changed, err := events.NewEnvelope(events.OrderStatusChangedEvent, events.OrderAggregate, "order-1", events.OrderStatusChanged{
+		OrderID:        "order-1",
+		UserID:         "user-1",
+		PreviousStatus: "PAID",
+		Status:         "SHIPPED",
+		TotalAmount:    5998,
+		Currency:       "USD",
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		locale          string
+		expectedLocale  string
+		expectedSubject string
+	}{
+		{"default locale", "en", "en", "Order order-1 shipped"},
+		{"exact locale", "es", "es", "Pedido order-1 enviado"},
+		{"regional locale falls back to language", "es-MX", "es", "Pedido order-1 enviado"},
+		{"unknown locale falls back to default", "fr", "en", "Order order-1 shipped"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := &recordingChannel{name: "smtp"}
+			uc, _ := newNotifyCase(t, tt.locale, email)
+
+			if err := uc.Execute(ctx, changed); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			message := email.messages[0]
+			if message.Locale != tt.expectedLocale {
+				t.Errorf("Expected locale %s, got %s", tt.expectedLocale, message.Locale)
+			}
+			if message.Subject != tt.expectedSubject {
+				t.Errorf("Expected subject %q, got %q", tt.expectedSubject, message.Subject)
+			}
+		})
+	}
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/pkg/events"
 	pkgLogger "github.com/robrt95x/godops/pkg/logger"
 	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
 	"github.com/robrt95x/godops/services/order/internal/config"
 	httpDelivery "github.com/robrt95x/godops/services/order/internal/delivery/http"
+	serviceErrors "github.com/robrt95x/godops/services/order/internal/errors"
 	"github.com/robrt95x/godops/services/order/internal/infra"
 	"github.com/robrt95x/godops/services/order/internal/usecase"
 )
@@ -31,19 +37,139 @@ func main() {
 	}
 	appLogger := pkgLogger.Setup(loggerConfig)
 	
+	// Fail fast on an error catalog entry without a status, gRPC code or log level
+	if err := serviceErrors.Catalog.Validate(); err != nil {
+		appLogger.WithError(err).Fatal("Invalid error catalog")
+	}
+	
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrator, err := infra.NewRepositoryFactory(cfg).CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		os.Exit(db.RunMigrateCommand(os.Args[2:], migrator, "order-service"))
+	}
+	
 	appLogger.WithField("storage_type", cfg.StorageType).Info("Starting order service")
 
 	// Create repository using factory
 	factory := infra.NewRepositoryFactory(cfg)
+	if cfg.IsPostgresStorage() && cfg.DBAutoMigrate {
+		migrator, err := factory.CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to apply database migrations")
+		}
+		appLogger.WithField("applied", len(applied)).Info("Database schema is up to date")
+	}
+
 	repo, err := factory.CreateOrderRepository()
 	if err != nil {
 		appLogger.WithError(err).Fatal("Failed to create repository")
 	}
+	idempotencyRepo, err := factory.CreateIdempotencyRepository()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create idempotency repository")
+	}
+	couponRepo, err := factory.CreateCouponRepository()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create coupon repository")
+	}
+	users, err := factory.CreateUserDirectory()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create user directory")
+	}
+	outbox, err := factory.CreateOutbox()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create outbox")
+	}
 
 	// Create use cases
-	createUC := usecase.NewCreateOrderCase(repo, appLogger)
+	createUC := usecase.NewCreateOrderCase(repo, couponRepo, users, appLogger)
+	if cfg.PaymentServiceURL != "" {
+		createUC.WithPaymentSagas(cfg.PaymentSagaTimeout)
+	}
 	getOrderByIDUC := usecase.NewGetOrderByIDCase(repo, appLogger)
-	handler := httpDelivery.NewOrderHandler(createUC, getOrderByIDUC, appLogger)
+	updateStatusUC := usecase.NewUpdateOrderStatusCase(repo, appLogger)
+	listOrdersUC := usecase.NewListOrdersCase(repo, appLogger)
+	idempotencyUC := usecase.NewIdempotencyCase(idempotencyRepo, cfg.IdempotencyKeyTTL, cfg.IdempotencyLease, appLogger)
+	handler := httpDelivery.NewOrderHandler(createUC, getOrderByIDUC, updateStatusUC, listOrdersUC, idempotencyUC, appLogger)
+
+	createCouponUC := usecase.NewCreateCouponCase(couponRepo, appLogger)
+	getCouponUC := usecase.NewGetCouponCase(couponRepo, appLogger)
+	couponHandler := httpDelivery.NewCouponHandler(createCouponUC, getCouponUC, appLogger)
+
+	// Publish domain events committed to the outbox on the event bus
+	bus := infra.NewEventBus(appLogger)
+	defer bus.Close()
+	for _, topic := range []string{events.OrderCreatedEvent, events.OrderStatusChangedEvent} {
+		if _, err := bus.Subscribe(topic, "order-service-log", infra.NewEventLogger(appLogger)); err != nil {
+			appLogger.WithError(err).Fatal("Failed to subscribe to order events")
+		}
+	}
+
+	// Events leave the outbox only once every destination has acknowledged them,
+	// so none are lost to a restart or a destination outage
+	forwardClient := &http.Client{Timeout: cfg.RequestTimeout}
+	if len(cfg.EventForwardURLs) > 0 && cfg.EventForwardSecret == "" {
+		appLogger.Fatal("EVENT_FORWARD_SECRET is required to forward events")
+	}
+	publishers := []events.Publisher{bus}
+	for _, url := range cfg.EventForwardURLs {
+		publishers = append(publishers, events.PublisherFunc(events.HTTPForwarder(forwardClient, url, []byte(cfg.EventForwardSecret))))
+	}
+
+	// Charge orders created with a payment token through the payment service
+	if cfg.PaymentServiceURL != "" {
+		sagaRepo, err := factory.CreatePaymentSagaRepository()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create payment saga repository")
+		}
+		payments, err := factory.CreatePaymentService()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create payment service client")
+		}
+		sagaUC := usecase.NewPaymentSagaCase(sagaRepo, repo, payments, usecase.PaymentSagaConfig{
+			Timeout:      cfg.PaymentSagaTimeout,
+			RetryBackoff: cfg.PaymentSagaRetryBackoff,
+		}, appLogger)
+		
+		// Sagas are created with their orders and driven from storage, so those in flight when the service stopped resume here
+		go func() {
+			ticker := time.NewTicker(cfg.PaymentSagaPollInterval)
+			defer ticker.Stop()
+			for {
+				if _, err := sagaUC.ResumeDue(context.Background()); err != nil {
+					appLogger.WithError(err).Error("Failed to resume payment sagas")
+				}
+				<-ticker.C
+			}
+		}()
+	}
+
+	relay := events.NewRelay(outbox, events.Fanout(publishers...), events.RelayConfig{
+		Interval:  cfg.OutboxRelayInterval,
+		BatchSize: cfg.OutboxBatchSize,
+		OnError: func(err error) {
+			appLogger.WithError(err).Error("Failed to relay outbox events")
+		},
+		OnDeadLetter: infra.NewRelayDeadLetterLogger(appLogger),
+	})
+	go relay.Run(context.Background())
+
+	// Periodically drop idempotency keys whose TTL has elapsed
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for range ticker.C {
+			if deleted, err := idempotencyUC.PurgeExpired(context.Background()); err == nil && deleted > 0 {
+				appLogger.WithField("deleted", deleted).Info("Purged expired idempotency keys")
+			}
+		}
+	}()
 
 	// Setup router with middleware
 	r := chi.NewRouter()
@@ -52,11 +178,39 @@ func main() {
 	r.Use(pkgMiddleware.RequestID)
 	r.Use(pkgMiddleware.Logging(appLogger))
 	r.Use(pkgMiddleware.ErrorLogging(appLogger))
+	r.Use(pkgMiddleware.Timeout(cfg.RequestTimeout))
 	r.Use(middleware.Recoverer)
+	
+	// Every order and coupon route requires a bearer token from the user service;
+	// what the caller may do with it is decided by the use cases' policy
+	if cfg.AuthEnabled {
+		r.Use(pkgMiddleware.Authenticate(pkgMiddleware.AuthConfig{
+			Keys:     pkgMiddleware.NewJWKSKeySource(cfg.AuthJWKSURL, pkgMiddleware.JWKSConfig{CacheTTL: cfg.AuthJWKSCacheTTL}),
+			Issuer:   cfg.AuthIssuer,
+			Audience: cfg.AuthAudience,
+			Leeway:   cfg.AuthLeeway,
+		}, appLogger))
+		r.Use(httpDelivery.AuthenticatedPrincipal)
+	} else {
+		appLogger.Warn("AUTH_ENABLED=false; every route is served without authentication, as an admin")
+		r.Use(httpDelivery.AnonymousPrincipal)
+	}
 
 	r.Route("/orders", func(r chi.Router) {
 		r.Post("/", handler.CreateOrder)
+		r.Get("/", handler.ListOrders)
 		r.Get("/{id}", handler.GetOrderByID)
+		r.Post("/{id}/confirm", handler.ConfirmOrder)
+		r.Post("/{id}/pay", handler.PayOrder)
+		r.Post("/{id}/ship", handler.ShipOrder)
+		r.Post("/{id}/deliver", handler.DeliverOrder)
+		r.Post("/{id}/cancel", handler.CancelOrder)
+		r.Post("/{id}/refund", handler.RefundOrder)
+	})
+
+	r.Route("/coupons", func(r chi.Router) {
+		r.Post("/", couponHandler.CreateCoupon)
+		r.Get("/{code}", couponHandler.GetCoupon)
 	})
 
 	appLogger.WithField("port", cfg.ServerPort).Info("Starting HTTP server")
@@ -7,6 +7,8 @@ require (
 	github.com/google/uuid v1.6.0
 	github.com/joho/godotenv v1.5.1
 	github.com/robrt95x/godops/pkg v0.0.0-00010101000000-000000000000
+	github.com/robrt95x/godops/pkg/db v0.0.0-00010101000000-000000000000
+	github.com/robrt95x/godops/pkg/events v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -15,6 +17,13 @@ require (
 	gopkg.in/natefinch/lumberjack.v2 v2.2.1
 )
 
-require golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.2 // indirect
+	golang.org/x/sys v0.0.0-20220715151400-c0bba94af5f8 // indirect
+)
 
 replace github.com/robrt95x/godops/pkg => ../../pkg
+
+replace github.com/robrt95x/godops/pkg/db => ../../pkg/db
+
+replace github.com/robrt95x/godops/pkg/events => ../../pkg/events
@@ -4,6 +4,8 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -19,9 +21,50 @@ type Config struct {
 	DBPassword string `env:"DB_PASSWORD" default:"pass"`
 	DBName     string `env:"DB_NAME" default:"godops"`
 	DBSSLMode  string `env:"DB_SSLMODE" default:"disable"`
+	DBAutoMigrate bool `env:"DB_AUTO_MIGRATE" default:"true"`
 	
 	// Server Configuration
 	ServerPort string `env:"SERVER_PORT" default:"8080"`
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" default:"10s"`
+	
+	// Idempotency Configuration
+	IdempotencyKeyTTL time.Duration `env:"IDEMPOTENCY_KEY_TTL" default:"24h"`
+	IdempotencyLease  time.Duration `env:"IDEMPOTENCY_LEASE" default:"1m"`
+	
+	// Outbox Configuration
+	OutboxRelayInterval time.Duration `env:"OUTBOX_RELAY_INTERVAL" default:"1s"`
+	OutboxBatchSize     int           `env:"OUTBOX_BATCH_SIZE" default:"100"`
+	
+	// EventForwardURLs receive every order event over HTTP, e.g. the notification service
+	EventForwardURLs   []string `env:"EVENT_FORWARD_URLS" default:""`
+	// EventForwardSecret signs forwarded events; receivers must share it
+	EventForwardSecret string   `env:"EVENT_FORWARD_SECRET" default:""`
+	
+	// User Service Configuration; orders are only accepted for users it knows
+	UserServiceURL              string        `env:"USER_SERVICE_URL" default:"http://localhost:8081"`
+	UserServiceTimeout          time.Duration `env:"USER_SERVICE_TIMEOUT" default:"2s"`
+	UserServiceMaxRetries       int           `env:"USER_SERVICE_MAX_RETRIES" default:"2"`
+	UserServiceRetryBackoff     time.Duration `env:"USER_SERVICE_RETRY_BACKOFF" default:"100ms"`
+	UserServiceBreakerThreshold int           `env:"USER_SERVICE_BREAKER_THRESHOLD" default:"5"`
+	UserServiceBreakerOpen      time.Duration `env:"USER_SERVICE_BREAKER_OPEN" default:"30s"`
+	// The order service's own user service account, an admin, which user
+	// lookups are made as; without one the caller's token is forwarded
+	UserServiceEmail    string `env:"USER_SERVICE_EMAIL" default:""`
+	UserServicePassword string `env:"USER_SERVICE_PASSWORD" default:""`
+	
+	// Payment Saga Configuration; an empty PaymentServiceURL disables the saga
+	PaymentServiceURL       string        `env:"PAYMENT_SERVICE_URL" default:""`
+	PaymentSagaTimeout      time.Duration `env:"PAYMENT_SAGA_TIMEOUT" default:"5m"`
+	PaymentSagaPollInterval time.Duration `env:"PAYMENT_SAGA_POLL_INTERVAL" default:"1s"`
+	PaymentSagaRetryBackoff time.Duration `env:"PAYMENT_SAGA_RETRY_BACKOFF" default:"1s"`
+	
+	// Authentication Configuration; tokens are verified against the user service's JWKS
+	AuthEnabled      bool          `env:"AUTH_ENABLED" default:"true"`
+	AuthJWKSURL      string        `env:"AUTH_JWKS_URL" default:"http://localhost:8081/.well-known/jwks.json"`
+	AuthJWKSCacheTTL time.Duration `env:"AUTH_JWKS_CACHE_TTL" default:"5m"`
+	AuthIssuer       string        `env:"AUTH_ISSUER" default:"godops-user-service"`
+	AuthAudience     string        `env:"AUTH_AUDIENCE" default:"godops"`
+	AuthLeeway       time.Duration `env:"AUTH_LEEWAY" default:"30s"`
 	
 	// Logging Configuration
 	LogLevel       string `env:"LOG_LEVEL" default:"info"`
@@ -51,7 +94,33 @@ func Load() *Config {
 		DBPassword:     getEnv("DB_PASSWORD", "pass"),
 		DBName:         getEnv("DB_NAME", "godops"),
 		DBSSLMode:      getEnv("DB_SSLMODE", "disable"),
+		DBAutoMigrate:  getEnvBool("DB_AUTO_MIGRATE", true),
 		ServerPort:     getEnv("SERVER_PORT", "8080"),
+		RequestTimeout: getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		IdempotencyKeyTTL: getEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour),
+		IdempotencyLease:  getEnvDuration("IDEMPOTENCY_LEASE", time.Minute),
+		OutboxRelayInterval: getEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		OutboxBatchSize:     getEnvInt("OUTBOX_BATCH_SIZE", 100),
+		EventForwardURLs:    getEnvList("EVENT_FORWARD_URLS"),
+		EventForwardSecret:  getEnv("EVENT_FORWARD_SECRET", ""),
+		UserServiceURL:              getEnv("USER_SERVICE_URL", "http://localhost:8081"),
+		UserServiceTimeout:          getEnvDuration("USER_SERVICE_TIMEOUT", 2*time.Second),
+		UserServiceMaxRetries:       getEnvInt("USER_SERVICE_MAX_RETRIES", 2),
+		UserServiceRetryBackoff:     getEnvDuration("USER_SERVICE_RETRY_BACKOFF", 100*time.Millisecond),
+		UserServiceBreakerThreshold: getEnvInt("USER_SERVICE_BREAKER_THRESHOLD", 5),
+		UserServiceBreakerOpen:      getEnvDuration("USER_SERVICE_BREAKER_OPEN", 30*time.Second),
+		UserServiceEmail:            getEnv("USER_SERVICE_EMAIL", ""),
+		UserServicePassword:         getEnv("USER_SERVICE_PASSWORD", ""),
+		PaymentServiceURL:       getEnv("PAYMENT_SERVICE_URL", ""),
+		PaymentSagaTimeout:      getEnvDuration("PAYMENT_SAGA_TIMEOUT", 5*time.Minute),
+		PaymentSagaPollInterval: getEnvDuration("PAYMENT_SAGA_POLL_INTERVAL", time.Second),
+		PaymentSagaRetryBackoff: getEnvDuration("PAYMENT_SAGA_RETRY_BACKOFF", time.Second),
+		AuthEnabled:      getEnvBool("AUTH_ENABLED", true),
+		AuthJWKSURL:      getEnv("AUTH_JWKS_URL", "http://localhost:8081/.well-known/jwks.json"),
+		AuthJWKSCacheTTL: getEnvDuration("AUTH_JWKS_CACHE_TTL", 5*time.Minute),
+		AuthIssuer:       getEnv("AUTH_ISSUER", "godops-user-service"),
+		AuthAudience:     getEnv("AUTH_AUDIENCE", "godops"),
+		AuthLeeway:       getEnvDuration("AUTH_LEEWAY", 30*time.Second),
 		LogLevel:       getEnv("LOG_LEVEL", "info"),
 		LogFormat:      getEnv("LOG_FORMAT", "json"),
 		LogOutput:      getEnv("LOG_OUTPUT", "console"),
@@ -102,3 +171,23 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
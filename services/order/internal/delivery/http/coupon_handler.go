@@ -0,0 +1,85 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+type CouponHandler struct {
+	CreateUC     *usecase.CreateCouponCase
+	GetUC        *usecase.GetCouponCase
+	ErrorHandler *pkgErrors.HTTPErrorHandler
+	Logger       *logrus.Logger
+}
+
+func NewCouponHandler(createUC *usecase.CreateCouponCase, getUC *usecase.GetCouponCase, logger *logrus.Logger) *CouponHandler {
+	errorCatalog := errors.NewOrderErrorCatalog()
+	return &CouponHandler{
+		CreateUC:     createUC,
+		GetUC:        getUC,
+		ErrorHandler: pkgErrors.NewHTTPErrorHandler(logger, errorCatalog).WithTranslations(errors.Translations),
+		Logger:       logger,
+	}
+}
+
+func (h *CouponHandler) CreateCoupon(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Request-ID")
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "CreateCoupon",
+		"request_id": requestID,
+	})
+
+	logEntry.Debug("Processing create coupon request")
+
+	var coupon entity.Coupon
+	if err := json.NewDecoder(r.Body).Decode(&coupon); err != nil {
+		logEntry.WithError(err).Warning("Failed to decode request body")
+		h.ErrorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+
+	created, err := h.CreateUC.Execute(r.Context(), &coupon)
+	if err != nil {
+		logEntry.WithError(err).Warning("Create coupon use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.WithField("coupon_code", created.Code).Info("Coupon created successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(created)
+}
+
+func (h *CouponHandler) GetCoupon(w http.ResponseWriter, r *http.Request) {
+	code := chi.URLParam(r, "code")
+	requestID := r.Header.Get("X-Request-ID")
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":     "GetCoupon",
+		"request_id":  requestID,
+		"coupon_code": code,
+	})
+
+	logEntry.Debug("Processing get coupon request")
+
+	coupon, err := h.GetUC.Execute(r.Context(), code)
+	if err != nil {
+		logEntry.WithError(err).Warning("Get coupon use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.Info("Coupon retrieved successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(coupon)
+}
@@ -1,41 +1,63 @@
 package http
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	pkgErrors "github.com/robrt95x/godops/pkg/errors"
 	"github.com/robrt95x/godops/services/order/internal/entity"
 	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
 	"github.com/robrt95x/godops/services/order/internal/usecase"
 	"github.com/sirupsen/logrus"
 )
 
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 type OrderHandler struct {
 	CreateUC       *usecase.CreateOrderCase
 	GetOrderByIDUC *usecase.GetOrderByIDCase
+	UpdateStatusUC *usecase.UpdateOrderStatusCase
+	ListOrdersUC   *usecase.ListOrdersCase
+	IdempotencyUC  *usecase.IdempotencyCase
 	ErrorHandler   *pkgErrors.HTTPErrorHandler
 	Logger         *logrus.Logger
 }
 
-func NewOrderHandler(createUC *usecase.CreateOrderCase, getOrderByIDUC *usecase.GetOrderByIDCase, logger *logrus.Logger) *OrderHandler {
+func NewOrderHandler(createUC *usecase.CreateOrderCase, getOrderByIDUC *usecase.GetOrderByIDCase, updateStatusUC *usecase.UpdateOrderStatusCase, listOrdersUC *usecase.ListOrdersCase, idempotencyUC *usecase.IdempotencyCase, logger *logrus.Logger) *OrderHandler {
 	errorCatalog := errors.NewOrderErrorCatalog()
 	return &OrderHandler{
 		CreateUC:       createUC,
 		GetOrderByIDUC: getOrderByIDUC,
-		ErrorHandler:   pkgErrors.NewHTTPErrorHandler(logger, errorCatalog),
+		UpdateStatusUC: updateStatusUC,
+		ListOrdersUC:   listOrdersUC,
+		IdempotencyUC:  idempotencyUC,
+		ErrorHandler:   pkgErrors.NewHTTPErrorHandler(logger, errorCatalog).WithTranslations(errors.Translations),
 		Logger:         logger,
 	}
 }
 
 type CreateOrderRequest struct {
-	UserID string             `json:"user_id"`
-	Items  []entity.OrderItem `json:"items"`
+	UserID          string             `json:"user_id"`
+	Items           []entity.OrderItem `json:"items"`
+	CouponCode      string             `json:"coupon_code"`
+	ShippingAddress entity.Address     `json:"shipping_address"`
+	PaymentToken    string             `json:"payment_token"`
 }
 
 func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	requestID := r.Header.Get("X-Request-ID")
+	idempotencyKey := r.Header.Get(IdempotencyKeyHeader)
 	logEntry := h.Logger.WithFields(logrus.Fields{
 		"handler":    "CreateOrder",
 		"request_id": requestID,
@@ -43,8 +65,15 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	
 	logEntry.Debug("Processing create order request")
 	
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		logEntry.WithError(err).Warning("Failed to read request body")
+		h.ErrorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+	
 	var req CreateOrderRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := json.Unmarshal(body, &req); err != nil {
 		logEntry.WithError(err).Warning("Failed to decode request body")
 		h.ErrorHandler.HandleValidationError(w, r, "Invalid request body format")
 		return
@@ -55,18 +84,62 @@ func (h *OrderHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		"items_count": len(req.Items),
 	})
 	
-	order, err := h.CreateUC.Execute(req.UserID, req.Items)
+	if idempotencyKey != "" {
+		logEntry = logEntry.WithField("idempotency_key", idempotencyKey)
+		
+		// A stored response is only replayed to a caller who may place the order
+		// now; the use case authorizes requests that are executed
+		if err := policy.Authorize(r.Context(), policy.ActionCreateOrder, req.UserID); err != nil {
+			logEntry.WithError(err).Warning("Idempotent create order request not authorized")
+			h.ErrorHandler.HandleError(w, r, err)
+			return
+		}
+		
+		requestHash := sha256.Sum256(body)
+		stored, err := h.IdempotencyUC.Begin(r.Context(), idempotencyKey, hex.EncodeToString(requestHash[:]))
+		if err != nil {
+			logEntry.WithError(err).Warning("Idempotency check failed")
+			h.ErrorHandler.HandleError(w, r, err)
+			return
+		}
+		if stored != nil {
+			logEntry.Info("Replaying stored create order response")
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set(IdempotentReplayedHeader, "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.ResponseBody)
+			return
+		}
+	}
+	
+	order, err := h.CreateUC.Execute(r.Context(), req.UserID, req.Items, req.CouponCode, req.ShippingAddress, req.PaymentToken)
 	if err != nil {
 		logEntry.WithError(err).Error("Create order use case failed")
+		if idempotencyKey != "" {
+			// Free the key even when the request deadline is what failed the order
+			h.IdempotencyUC.Release(context.WithoutCancel(r.Context()), idempotencyKey)
+		}
 		h.ErrorHandler.HandleError(w, r, err)
 		return
 	}
 	
 	logEntry.WithField("order_id", order.ID).Info("Order created successfully")
 	
+	response, err := json.Marshal(order)
+	if err != nil {
+		h.ErrorHandler.HandleInternalError(w, r, err)
+		return
+	}
+	
+	if idempotencyKey != "" {
+		if err := h.IdempotencyUC.Complete(context.WithoutCancel(r.Context()), idempotencyKey, http.StatusCreated, response); err != nil {
+			logEntry.WithError(err).Warning("Order created but response was not stored for replay")
+		}
+	}
+	
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(order)
+	w.Write(response)
 }
 
 func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
@@ -81,7 +154,7 @@ func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 	
 	logEntry.Debug("Processing get order by ID request")
 	
-	order, err := h.GetOrderByIDUC.Execute(orderID)
+	order, err := h.GetOrderByIDUC.Execute(r.Context(), orderID)
 	if err != nil {
 		logEntry.WithError(err).Warning("Get order by ID use case failed")
 		h.ErrorHandler.HandleError(w, r, err)
@@ -93,3 +166,117 @@ func (h *OrderHandler) GetOrderByID(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(order)
 }
+
+func (h *OrderHandler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Request-ID")
+	query := r.URL.Query()
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "ListOrders",
+		"request_id": requestID,
+		"user_id":    query.Get("user_id"),
+		"product_id": query.Get("product_id"),
+	})
+
+	logEntry.Debug("Processing list orders request")
+
+	input := usecase.ListOrdersInput{
+		UserID:    query.Get("user_id"),
+		ProductID: query.Get("product_id"),
+		Status:    entity.OrderStatus(query.Get("status")),
+		Cursor:    query.Get("cursor"),
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			logEntry.WithError(err).Warning("Failed to parse limit")
+			h.ErrorHandler.HandleError(w, r, errors.ErrValidationInvalidLimit)
+			return
+		}
+		input.Limit = limit
+	}
+
+	var err error
+	if input.CreatedFrom, err = parseTimeParam(query.Get("created_from")); err != nil {
+		logEntry.WithError(err).Warning("Failed to parse created_from")
+		h.ErrorHandler.HandleError(w, r, errors.ErrValidationInvalidDateRange)
+		return
+	}
+	if input.CreatedTo, err = parseTimeParam(query.Get("created_to")); err != nil {
+		logEntry.WithError(err).Warning("Failed to parse created_to")
+		h.ErrorHandler.HandleError(w, r, errors.ErrValidationInvalidDateRange)
+		return
+	}
+
+	page, err := h.ListOrdersUC.Execute(r.Context(), input)
+	if err != nil {
+		logEntry.WithError(err).Warning("List orders use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.WithField("orders_count", len(page.Orders)).Info("Orders listed successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// parseTimeParam parses an optional RFC 3339 query parameter
+func parseTimeParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (h *OrderHandler) ConfirmOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "ConfirmOrder", entity.Confirmed)
+}
+
+func (h *OrderHandler) PayOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "PayOrder", entity.Paid)
+}
+
+func (h *OrderHandler) ShipOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "ShipOrder", entity.Shipped)
+}
+
+func (h *OrderHandler) DeliverOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "DeliverOrder", entity.Delivered)
+}
+
+func (h *OrderHandler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "CancelOrder", entity.Cancelled)
+}
+
+func (h *OrderHandler) RefundOrder(w http.ResponseWriter, r *http.Request) {
+	h.updateOrderStatus(w, r, "RefundOrder", entity.Refunded)
+}
+
+// updateOrderStatus is shared by the per-transition endpoints
+func (h *OrderHandler) updateOrderStatus(w http.ResponseWriter, r *http.Request, handlerName string, status entity.OrderStatus) {
+	orderID := chi.URLParam(r, "id")
+	requestID := r.Header.Get("X-Request-ID")
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":       handlerName,
+		"request_id":    requestID,
+		"order_id":      orderID,
+		"target_status": status,
+	})
+
+	logEntry.Debug("Processing update order status request")
+
+	order, err := h.UpdateStatusUC.Execute(r.Context(), orderID, status)
+	if err != nil {
+		logEntry.WithError(err).Warning("Update order status use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.Info("Order status updated successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(order)
+}
@@ -0,0 +1,103 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	orderHttp "github.com/robrt95x/godops/services/order/internal/delivery/http"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+const createOrderBody = `{
+	"user_id": "user-1",
+	"items": [{"product_id": "product-1", "quantity": 1, "price": {"amount": 2500, "currency": "USD"}}],
+	"shipping_address": {"recipient": "Test User", "line1": "123 Test St", "city": "Springfield", "region": "IL", "postal_code": "62701", "country": "US"}
+}`
+
+func newOrderHandler() *orderHttp.OrderHandler {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	orders := memory.NewOrderMemoryRepository()
+	return orderHttp.NewOrderHandler(
+		usecase.NewCreateOrderCase(orders, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger),
+		usecase.NewGetOrderByIDCase(orders, testLogger),
+		usecase.NewUpdateOrderStatusCase(orders, testLogger),
+		usecase.NewListOrdersCase(orders, testLogger),
+		usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger),
+		testLogger,
+	)
+}
+
+// createOrder posts createOrderBody as principal with idempotency key
+func createOrder(handler *orderHttp.OrderHandler, principal policy.Principal, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(createOrderBody))
+	req = req.WithContext(policy.WithPrincipal(context.Background(), principal))
+	req.Header.Set(orderHttp.IdempotencyKeyHeader, key)
+	rec := httptest.NewRecorder()
+	handler.CreateOrder(rec, req)
+	return rec
+}
+
+func orderID(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var order struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &order); err != nil || order.ID == "" {
+		t.Fatalf("Expected an order in the response, got %q", rec.Body.String())
+	}
+	return order.ID
+}
+
+func TestOrderHandler_CreateOrder_Idempotency(t *testing.T) {
+	owner := policy.Principal{Subject: "user-1", Roles: []policy.Role{policy.RoleCustomer}}
+	stranger := policy.Principal{Subject: "user-2", Roles: []policy.Role{policy.RoleCustomer}}
+	admin := policy.Principal{Subject: "admin-1", Roles: []policy.Role{policy.RoleAdmin}}
+
+	handler := newOrderHandler()
+	first := createOrder(handler, owner, "key-1")
+	if first.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", first.Code, first.Body.String())
+	}
+	created := orderID(t, first)
+
+	t.Run("should replay the response to the same caller", func(t *testing.T) {
+		rec := createOrder(handler, owner, "key-1")
+
+		if rec.Code != http.StatusCreated || rec.Header().Get(orderHttp.IdempotentReplayedHeader) != "true" {
+			t.Fatalf("Expected a replayed 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if id := orderID(t, rec); id != created {
+			t.Errorf("Expected order %s, got %s", created, id)
+		}
+	})
+
+	t.Run("should not replay to a caller who may not create the order", func(t *testing.T) {
+		rec := createOrder(handler, stranger, "key-1")
+
+		if rec.Code != http.StatusForbidden || rec.Header().Get(orderHttp.IdempotentReplayedHeader) != "" {
+			t.Errorf("Expected 403 without a replay, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if strings.Contains(rec.Body.String(), created) {
+			t.Errorf("Expected the stored order not to leak, got %s", rec.Body.String())
+		}
+	})
+
+	t.Run("should treat the same key from another caller as a new request", func(t *testing.T) {
+		rec := createOrder(handler, admin, "key-1")
+
+		if rec.Code != http.StatusCreated || rec.Header().Get(orderHttp.IdempotentReplayedHeader) != "" {
+			t.Fatalf("Expected a new 201, got %d: %s", rec.Code, rec.Body.String())
+		}
+		if id := orderID(t, rec); id == created {
+			t.Errorf("Expected a new order, got the stored %s", id)
+		}
+	})
+}
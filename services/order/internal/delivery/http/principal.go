@@ -0,0 +1,35 @@
+package http
+
+import (
+	"net/http"
+
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+)
+
+// anonymousPrincipal stands in for every caller when authentication is disabled
+var anonymousPrincipal = policy.Principal{Subject: "anonymous", Roles: []policy.Role{policy.RoleAdmin}}
+
+// AuthenticatedPrincipal hands the subject and roles verified by
+// pkgMiddleware.Authenticate to the use cases' authorization policy. It must
+// run after Authenticate; a request without a subject carries no principal
+// and is refused by the use cases.
+func AuthenticatedPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject := pkgMiddleware.GetSubject(r)
+		if subject == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		principal := policy.NewPrincipal(subject, pkgMiddleware.GetRoles(r))
+		next.ServeHTTP(w, r.WithContext(policy.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// AnonymousPrincipal lets every request act as an admin, for running the
+// service with AUTH_ENABLED=false in development
+func AnonymousPrincipal(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(policy.WithPrincipal(r.Context(), anonymousPrincipal)))
+	})
+}
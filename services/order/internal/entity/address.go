@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"regexp"
+	"strings"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+const maxAddressFieldLength = 200
+
+// Address is a postal address; Country is an ISO 3166-1 alpha-2 code
+type Address struct {
+	Recipient  string `json:"recipient"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// countryFormat describes how addresses are written in a country
+type countryFormat struct {
+	postalCode     *regexp.Regexp
+	regionRequired bool
+	regions        map[string]bool // when set, Region must be one of these codes
+}
+
+var (
+	usStates = codeSet("AL", "AK", "AZ", "AR", "CA", "CO", "CT", "DE", "DC", "FL", "GA", "HI", "ID", "IL",
+		"IN", "IA", "KS", "KY", "LA", "ME", "MD", "MA", "MI", "MN", "MS", "MO", "MT", "NE", "NV", "NH", "NJ",
+		"NM", "NY", "NC", "ND", "OH", "OK", "OR", "PA", "RI", "SC", "SD", "TN", "TX", "UT", "VT", "VA", "WA",
+		"WV", "WI", "WY", "AS", "GU", "MP", "PR", "VI", "AA", "AE", "AP")
+	caProvinces = codeSet("AB", "BC", "MB", "NB", "NL", "NS", "NT", "NU", "ON", "PE", "QC", "SK", "YT")
+	auStates    = codeSet("ACT", "NSW", "NT", "QLD", "SA", "TAS", "VIC", "WA")
+)
+
+// countryFormats lists the countries we ship to
+var countryFormats = map[string]countryFormat{
+	"US": {postalCode: regexp.MustCompile(`^\d{5}(-\d{4})?$`), regionRequired: true, regions: usStates},
+	"CA": {postalCode: regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`), regionRequired: true, regions: caProvinces},
+	"AU": {postalCode: regexp.MustCompile(`^\d{4}$`), regionRequired: true, regions: auStates},
+	"MX": {postalCode: regexp.MustCompile(`^\d{5}$`), regionRequired: true},
+	"BR": {postalCode: regexp.MustCompile(`^\d{5}-?\d{3}$`), regionRequired: true},
+	"GB": {postalCode: regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`)},
+	"IE": {postalCode: regexp.MustCompile(`^([AC-FHKNPRTV-Y]\d{2}|D6W) ?[0-9AC-FHKNPRTV-Y]{4}$`)},
+	"DE": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"FR": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"ES": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"IT": {postalCode: regexp.MustCompile(`^\d{5}$`)},
+	"NL": {postalCode: regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`)},
+	"JP": {postalCode: regexp.MustCompile(`^\d{3}-?\d{4}$`)},
+	"IN": {postalCode: regexp.MustCompile(`^\d{6}$`)},
+}
+
+func codeSet(codes ...string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+	return set
+}
+
+// IsZero reports whether no address was provided at all
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// Normalize trims whitespace and upper-cases the country, region and postal code
+func (a Address) Normalize() Address {
+	return Address{
+		Recipient:  strings.TrimSpace(a.Recipient),
+		Line1:      strings.TrimSpace(a.Line1),
+		Line2:      strings.TrimSpace(a.Line2),
+		City:       strings.TrimSpace(a.City),
+		Region:     strings.ToUpper(strings.TrimSpace(a.Region)),
+		PostalCode: strings.ToUpper(strings.Join(strings.Fields(a.PostalCode), " ")),
+		Country:    strings.ToUpper(strings.TrimSpace(a.Country)),
+	}
+}
+
+// Validate checks a normalized address against the rules of its country.
+// Every invalid field is reported, as a *ValidationErrors whose pointers are
+// relative to the address.
+func (a Address) Validate() error {
+	if a.IsZero() {
+		return errors.ErrValidationMissingShippingAddress
+	}
+
+	var violations pkgErrors.ValidationErrors
+	if a.Recipient == "" || len(a.Recipient) > maxAddressFieldLength {
+		violations.Add("/recipient", errors.ErrValidationInvalidRecipient)
+	}
+	if a.Line1 == "" || len(a.Line1) > maxAddressFieldLength {
+		violations.Add("/line1", errors.ErrValidationInvalidAddressLine)
+	}
+	if len(a.Line2) > maxAddressFieldLength {
+		violations.Add("/line2", errors.ErrValidationInvalidAddressLine)
+	}
+	if a.City == "" || len(a.City) > maxAddressFieldLength {
+		violations.Add("/city", errors.ErrValidationInvalidCity)
+	}
+
+	// Region and postal code can only be checked against a country we ship to
+	format, supported := countryFormats[a.Country]
+	if !supported {
+		violations.Add("/country", errors.ErrValidationInvalidCountry)
+		return violations.Err()
+	}
+
+	if format.regionRequired && a.Region == "" {
+		violations.Add("/region", errors.ErrValidationInvalidRegion)
+	} else if format.regions != nil && !format.regions[a.Region] {
+		violations.Add("/region", errors.ErrValidationInvalidRegion)
+	}
+
+	if !format.postalCode.MatchString(a.PostalCode) {
+		violations.Add("/postal_code", errors.ErrValidationInvalidPostalCode)
+	}
+
+	return violations.Err()
+}
@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+type DiscountType string
+
+const (
+	PercentageDiscount  DiscountType = "PERCENTAGE"
+	FixedAmountDiscount DiscountType = "FIXED_AMOUNT"
+)
+
+// Coupon defines a discount applied to an order's subtotal.
+// Zero MinBasket, ExpiresAt and MaxUsesPerUser mean no restriction.
+type Coupon struct {
+	Code           string       `json:"code"`
+	Type           DiscountType `json:"type"`
+	PercentOff     int          `json:"percent_off,omitempty"`
+	AmountOff      money.Money  `json:"amount_off"`
+	MinBasket      money.Money  `json:"min_basket"`
+	ExpiresAt      time.Time    `json:"expires_at"`
+	MaxUsesPerUser int          `json:"max_uses_per_user,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// CouponRedemption records a coupon being used by a user on an order
+type CouponRedemption struct {
+	CouponCode string
+	UserID     string
+	OrderID    string
+	RedeemedAt time.Time
+}
+
+// NormalizeCouponCode makes coupon codes case-insensitive
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks that the coupon definition is usable
+func (c *Coupon) Validate() error {
+	if c.Code == "" {
+		return errors.ErrValidationInvalidCoupon
+	}
+
+	switch c.Type {
+	case PercentageDiscount:
+		if c.PercentOff < 1 || c.PercentOff > 100 {
+			return errors.ErrValidationInvalidCoupon
+		}
+	case FixedAmountDiscount:
+		if !c.AmountOff.IsPositive() || c.AmountOff.Validate() != nil {
+			return errors.ErrValidationInvalidCoupon
+		}
+	default:
+		return errors.ErrValidationInvalidCoupon
+	}
+
+	if c.MinBasket.IsNegative() {
+		return errors.ErrValidationInvalidCoupon
+	}
+	if c.MinBasket.IsPositive() && c.MinBasket.Validate() != nil {
+		return errors.ErrValidationInvalidCoupon
+	}
+	if c.Type == FixedAmountDiscount && c.MinBasket.IsPositive() && !c.MinBasket.SameCurrency(c.AmountOff) {
+		return errors.ErrValidationInvalidCoupon
+	}
+	if c.MaxUsesPerUser < 0 {
+		return errors.ErrValidationInvalidCoupon
+	}
+
+	return nil
+}
+
+func (c *Coupon) IsExpired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// DiscountFor returns the discount the coupon grants on subtotal, never more than subtotal
+func (c *Coupon) DiscountFor(subtotal money.Money, now time.Time) (money.Money, error) {
+	if c.IsExpired(now) {
+		return money.Money{}, errors.ErrCouponExpired
+	}
+
+	if c.MinBasket.IsPositive() {
+		if !c.MinBasket.SameCurrency(subtotal) {
+			return money.Money{}, errors.ErrCouponCurrencyMismatch
+		}
+		if subtotal.Amount < c.MinBasket.Amount {
+			return money.Money{}, errors.ErrCouponMinBasketNotMet
+		}
+	}
+
+	var discount money.Money
+	switch c.Type {
+	case PercentageDiscount:
+		// Percentages round down to the nearest minor unit; splitting the
+		// subtotal keeps the multiplication from overflowing
+		percent := int64(c.PercentOff)
+		amount := subtotal.Amount/100*percent + subtotal.Amount%100*percent/100
+		discount = money.Money{Amount: amount, Currency: subtotal.Currency}
+	case FixedAmountDiscount:
+		if !c.AmountOff.SameCurrency(subtotal) {
+			return money.Money{}, errors.ErrCouponCurrencyMismatch
+		}
+		discount = c.AmountOff
+	default:
+		return money.Money{}, errors.ErrCouponInvalid
+	}
+
+	if discount.Amount > subtotal.Amount {
+		discount.Amount = subtotal.Amount
+	}
+	return discount, nil
+}
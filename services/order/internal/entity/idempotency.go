@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// IdempotencyRecord remembers the response to a request sent with an Idempotency-Key.
+// Keys are scoped to the Subject that sent them, so one caller can never be
+// answered with another's response. A zero StatusCode means the original
+// request is still being processed, and ExpiresAt is then the end of its lease.
+type IdempotencyRecord struct {
+	Subject      string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func (r *IdempotencyRecord) IsCompleted() bool {
+	return r.StatusCode != 0
+}
+
+func (r *IdempotencyRecord) IsExpired(now time.Time) bool {
+	return !now.Before(r.ExpiresAt)
+}
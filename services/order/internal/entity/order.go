@@ -1,19 +1,56 @@
 package entity
 
-import "time"
+import (
+	"time"
+
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
 
 type OrderStatus string
 
 const (
 	Pending   OrderStatus = "PENDING"
-	Completed OrderStatus = "COMPLETED"
+	Confirmed OrderStatus = "CONFIRMED"
+	Paid      OrderStatus = "PAID"
+	Shipped   OrderStatus = "SHIPPED"
+	Delivered OrderStatus = "DELIVERED"
 	Cancelled OrderStatus = "CANCELLED"
+	Refunded  OrderStatus = "REFUNDED"
+
+	// Completed is kept for orders stored before the lifecycle existed; it is terminal.
+	Completed OrderStatus = "COMPLETED"
 )
 
+// orderTransitions lists the statuses each status may move to
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	Pending:   {Confirmed, Cancelled},
+	Confirmed: {Paid, Cancelled},
+	Paid:      {Shipped, Refunded},
+	Shipped:   {Delivered},
+	Delivered: {Refunded},
+}
+
 func (s OrderStatus) IsPending() bool {
 	return s == Pending
 }
 
+func (s OrderStatus) IsConfirmed() bool {
+	return s == Confirmed
+}
+
+func (s OrderStatus) IsPaid() bool {
+	return s == Paid
+}
+
+func (s OrderStatus) IsShipped() bool {
+	return s == Shipped
+}
+
+func (s OrderStatus) IsDelivered() bool {
+	return s == Delivered
+}
+
 func (s OrderStatus) IsCompleted() bool {
 	return s == Completed
 }
@@ -22,20 +59,70 @@ func (s OrderStatus) IsCancelled() bool {
 	return s == Cancelled
 }
 
+func (s OrderStatus) IsRefunded() bool {
+	return s == Refunded
+}
+
+// IsValid reports whether s is a known order status
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case Pending, Confirmed, Paid, Shipped, Delivered, Cancelled, Refunded, Completed:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsTerminal reports whether no further transitions are allowed from s
+func (s OrderStatus) IsTerminal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
 type Order struct {
-	ID        string
-	UserID    string
-	Items     []OrderItem
-	Status    OrderStatus
-	CouponCode string
-	Total     float64
-	ShippingAddress string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              string      `json:"id"`
+	UserID          string      `json:"user_id"`
+	Items           []OrderItem `json:"items"`
+	Status          OrderStatus `json:"status"`
+	CouponCode      string      `json:"coupon_code,omitempty"`
+	Subtotal        money.Money `json:"subtotal"`
+	Discount        money.Money `json:"discount"`
+	Total           money.Money `json:"total"`
+	ShippingAddress Address     `json:"shipping_address"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// TransitionTo moves the order to next, rejecting moves the lifecycle does not allow
+func (o *Order) TransitionTo(next OrderStatus, at time.Time) error {
+	if !o.Status.CanTransitionTo(next) {
+		return &errors.TransitionError{From: string(o.Status), To: string(next)}
+	}
+	o.Status = next
+	o.UpdatedAt = at
+	return nil
 }
 
 type OrderItem struct {
-	ProductID string
-	Quantity  int
-	Price     float64
+	ProductID string      `json:"product_id"`
+	Quantity  int         `json:"quantity"`
+	Price     money.Money `json:"price"`
+}
+
+// MaxItemQuantity bounds the quantity of a single order item
+const MaxItemQuantity = 10000
+
+// Subtotal returns the item price multiplied by its quantity, or
+// money.ErrOverflow when the product does not fit in an amount
+func (i OrderItem) Subtotal() (money.Money, error) {
+	return i.Price.Multiply(int64(i.Quantity))
 }
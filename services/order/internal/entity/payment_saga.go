@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/robrt95x/godops/pkg/money"
+)
+
+type SagaState string
+
+const (
+	SagaStarted           SagaState = "STARTED"
+	SagaOrderConfirmed    SagaState = "ORDER_CONFIRMED"
+	SagaPaymentCreated    SagaState = "PAYMENT_CREATED"
+	SagaPaymentAuthorized SagaState = "PAYMENT_AUTHORIZED"
+	SagaPaymentCaptured   SagaState = "PAYMENT_CAPTURED"
+	SagaCompleted         SagaState = "COMPLETED"
+	SagaCompensating      SagaState = "COMPENSATING"
+	SagaCompensated       SagaState = "COMPENSATED"
+)
+
+// Reasons a payment saga is compensated
+const (
+	SagaReasonDeclined        = "payment_declined"
+	SagaReasonTimeout         = "timeout"
+	SagaReasonOrderNotPayable = "order_not_payable"
+	SagaReasonPaymentRejected = "payment_rejected"
+)
+
+// IsTerminal reports whether the saga has nothing left to do
+func (s SagaState) IsTerminal() bool {
+	return s == SagaCompleted || s == SagaCompensated
+}
+
+// IsCommitted reports whether the payment has been captured, after which the
+// saga only moves forward: a captured payment is never voided by a timeout
+func (s SagaState) IsCommitted() bool {
+	return s == SagaPaymentCaptured || s == SagaCompleted
+}
+
+// PaymentSaga tracks charging an order through the payment service. It is
+// persisted after every step so an interrupted saga resumes where it stopped.
+type PaymentSaga struct {
+	OrderID       string      `json:"order_id"`
+	State         SagaState   `json:"state"`
+	PaymentToken  string      `json:"-"`
+	Amount        money.Money `json:"amount"`
+	PaymentID     string      `json:"payment_id,omitempty"`
+	FailureReason string      `json:"failure_reason,omitempty"`
+	LastError     string      `json:"last_error,omitempty"`
+	Attempts      int         `json:"attempts"`
+	Deadline      time.Time   `json:"deadline"`
+	NextAttemptAt time.Time   `json:"next_attempt_at"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+}
+
+// NewPaymentSaga starts charging an order with paymentToken; the saga must
+// capture amount before deadline or it is compensated
+func NewPaymentSaga(orderID, paymentToken string, amount money.Money, deadline, now time.Time) *PaymentSaga {
+	return &PaymentSaga{
+		OrderID:       orderID,
+		State:         SagaStarted,
+		PaymentToken:  paymentToken,
+		Amount:        amount,
+		Deadline:      deadline,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// MoveTo records that the saga reached state, clearing the retry bookkeeping
+// of the previous step. A finished saga no longer needs the payment token, so
+// it is dropped rather than kept at rest.
+func (s *PaymentSaga) MoveTo(state SagaState, at time.Time) {
+	s.State = state
+	if state.IsTerminal() {
+		s.PaymentToken = ""
+	}
+	s.Attempts = 0
+	s.LastError = ""
+	s.NextAttemptAt = at
+	s.UpdatedAt = at
+}
+
+// Compensate switches the saga to undoing its completed steps
+func (s *PaymentSaga) Compensate(reason string, at time.Time) {
+	s.FailureReason = reason
+	s.MoveTo(SagaCompensating, at)
+}
+
+// Retry records a failed step to be attempted again at next
+func (s *PaymentSaga) Retry(err error, next, at time.Time) {
+	s.Attempts++
+	s.LastError = err.Error()
+	s.NextAttemptAt = next
+	s.UpdatedAt = at
+}
@@ -2,6 +2,8 @@ package errors
 
 import (
 	"errors"
+	"net/http"
+	"fmt"
 	
 	pkgErrors "github.com/robrt95x/godops/pkg/errors"
 )
@@ -12,6 +14,25 @@ const (
 	OrderNotFound     = "ORDER_NOT_FOUND"
 	OrderInvalidID    = "ORDER_INVALID_ID"
 	OrderAlreadyExists = "ORDER_ALREADY_EXISTS"
+	OrderInvalidTransition = "ORDER_INVALID_TRANSITION"
+	OrderUnknownUser = "ORDER_UNKNOWN_USER"
+	
+	// Authorization errors, shared with pkg/middleware
+	AuthUnauthenticated = pkgErrors.AuthUnauthenticated
+	AuthForbidden       = pkgErrors.AuthForbidden
+	
+	// Coupon errors
+	CouponInvalid           = "COUPON_INVALID"
+	CouponExpired           = "COUPON_EXPIRED"
+	CouponMinBasketNotMet   = "COUPON_MIN_BASKET_NOT_MET"
+	CouponUsageLimitReached = "COUPON_USAGE_LIMIT_REACHED"
+	CouponCurrencyMismatch  = "COUPON_CURRENCY_MISMATCH"
+	CouponNotFound          = "COUPON_NOT_FOUND"
+	CouponAlreadyExists     = "COUPON_ALREADY_EXISTS"
+	
+	// Idempotency errors
+	IdempotencyKeyMismatch       = "IDEMPOTENCY_KEY_MISMATCH"
+	IdempotencyRequestInProgress = "IDEMPOTENCY_REQUEST_IN_PROGRESS"
 	
 	// Validation errors
 	ValidationMissingUserID    = "VALIDATION_MISSING_USER_ID"
@@ -20,6 +41,22 @@ const (
 	ValidationInvalidPrice     = "VALIDATION_INVALID_PRICE"
 	ValidationMissingProductID = "VALIDATION_MISSING_PRODUCT_ID"
 	ValidationInvalidRequest   = "VALIDATION_INVALID_REQUEST"
+	ValidationInvalidStatus    = "VALIDATION_INVALID_STATUS"
+	ValidationInvalidCursor    = "VALIDATION_INVALID_CURSOR"
+	ValidationInvalidLimit     = "VALIDATION_INVALID_LIMIT"
+	ValidationInvalidDateRange = "VALIDATION_INVALID_DATE_RANGE"
+	ValidationInvalidCurrency  = "VALIDATION_INVALID_CURRENCY"
+	ValidationCurrencyMismatch = "VALIDATION_CURRENCY_MISMATCH"
+	ValidationInvalidIdempotencyKey = "VALIDATION_INVALID_IDEMPOTENCY_KEY"
+	ValidationInvalidCoupon    = "VALIDATION_INVALID_COUPON"
+	ValidationMissingShippingAddress = "VALIDATION_MISSING_SHIPPING_ADDRESS"
+	ValidationInvalidRecipient       = "VALIDATION_INVALID_RECIPIENT"
+	ValidationInvalidAddressLine     = "VALIDATION_INVALID_ADDRESS_LINE"
+	ValidationInvalidCity            = "VALIDATION_INVALID_CITY"
+	ValidationInvalidRegion          = "VALIDATION_INVALID_REGION"
+	ValidationInvalidPostalCode      = "VALIDATION_INVALID_POSTAL_CODE"
+	ValidationInvalidCountry         = "VALIDATION_INVALID_COUNTRY"
+	ValidationAmountTooLarge         = "VALIDATION_AMOUNT_TOO_LARGE"
 	
 	// Database errors
 	DatabaseConnectionError = "DATABASE_CONNECTION_ERROR"
@@ -37,13 +74,45 @@ var (
 	ErrOrderNotFound     = errors.New("order not found")
 	ErrOrderInvalidID    = errors.New("invalid order ID")
 	ErrOrderAlreadyExists = errors.New("order already exists")
+	ErrOrderInvalidTransition = errors.New("order status transition not allowed")
+	ErrOrderUnknownUser = errors.New("order user does not exist")
+	
+	ErrAuthUnauthenticated = errors.New("caller is not authenticated")
+	ErrAuthForbidden       = errors.New("caller is not allowed to perform this action")
+	
+	ErrCouponInvalid           = errors.New("coupon code is not valid")
+	ErrCouponExpired           = errors.New("coupon has expired")
+	ErrCouponMinBasketNotMet   = errors.New("order subtotal is below the coupon minimum")
+	ErrCouponUsageLimitReached = errors.New("coupon usage limit reached for this user")
+	ErrCouponCurrencyMismatch  = errors.New("coupon currency does not match the order")
+	ErrCouponNotFound          = errors.New("coupon not found")
+	ErrCouponAlreadyExists     = errors.New("coupon already exists")
+	
+	ErrIdempotencyKeyMismatch       = errors.New("idempotency key reused with a different request")
+	ErrIdempotencyRequestInProgress = errors.New("request with this idempotency key is still in progress")
 	
 	ErrValidationMissingUserID    = errors.New("user ID is required")
 	ErrValidationEmptyItems       = errors.New("order must contain at least one item")
-	ErrValidationInvalidQuantity  = errors.New("item quantity must be greater than zero")
+	ErrValidationInvalidQuantity  = errors.New("item quantity must be between 1 and 10000")
 	ErrValidationInvalidPrice     = errors.New("item price must be greater than zero")
 	ErrValidationMissingProductID = errors.New("product ID is required for all items")
 	ErrValidationInvalidRequest   = errors.New("invalid request format")
+	ErrValidationInvalidStatus    = errors.New("unknown order status")
+	ErrValidationInvalidCursor    = errors.New("invalid pagination cursor")
+	ErrValidationInvalidLimit     = errors.New("invalid page limit")
+	ErrValidationInvalidDateRange = errors.New("invalid created-at range")
+	ErrValidationInvalidCurrency  = errors.New("item price currency is not a supported ISO 4217 code")
+	ErrValidationCurrencyMismatch = errors.New("all items in an order must share a currency")
+	ErrValidationInvalidIdempotencyKey = errors.New("invalid idempotency key")
+	ErrValidationInvalidCoupon    = errors.New("invalid coupon definition")
+	ErrValidationMissingShippingAddress = errors.New("shipping address is required")
+	ErrValidationInvalidRecipient       = errors.New("shipping recipient is missing or too long")
+	ErrValidationInvalidAddressLine     = errors.New("shipping address line is missing or too long")
+	ErrValidationInvalidCity            = errors.New("shipping city is missing or too long")
+	ErrValidationInvalidRegion          = errors.New("shipping region is not valid for the country")
+	ErrValidationInvalidPostalCode      = errors.New("shipping postal code does not match the country format")
+	ErrValidationInvalidCountry         = errors.New("shipping country is not supported")
+	ErrValidationAmountTooLarge         = errors.New("order amount is out of range")
 	
 	ErrDatabaseConnection = errors.New("database connection failed")
 	ErrDatabaseQuery      = errors.New("database query failed")
@@ -54,96 +123,86 @@ var (
 	ErrSystemTimeout = errors.New("request timeout")
 )
 
-// ErrorInfo represents error information for API responses
-type ErrorInfo struct {
-	Code    string `json:"error_code"`
-	Message string `json:"error_message"`
-}
+// Catalog resolves domain errors to API error responses; errors wrapped with
+// %w resolve like the error they wrap
+var Catalog = pkgErrors.NewRegistry(
+	pkgErrors.Entry{Code: SystemInternalError, Message: "An unexpected error occurred", Meta: pkgErrors.Internal},
 
-// ErrorCatalog maps domain errors to API error responses
-var ErrorCatalog = map[error]ErrorInfo{
-	ErrOrderNotFound:     {OrderNotFound, "The requested order could not be found"},
-	ErrOrderInvalidID:    {OrderInvalidID, "Invalid order ID format"},
-	ErrOrderAlreadyExists: {OrderAlreadyExists, "Order with this ID already exists"},
-	
-	ErrValidationMissingUserID:    {ValidationMissingUserID, "User ID is required"},
-	ErrValidationEmptyItems:       {ValidationEmptyItems, "Order must contain at least one item"},
-	ErrValidationInvalidQuantity:  {ValidationInvalidQuantity, "Item quantity must be greater than zero"},
-	ErrValidationInvalidPrice:     {ValidationInvalidPrice, "Item price must be greater than zero"},
-	ErrValidationMissingProductID: {ValidationMissingProductID, "Product ID is required for all items"},
-	ErrValidationInvalidRequest:   {ValidationInvalidRequest, "Invalid request format"},
-	
-	ErrDatabaseConnection:  {DatabaseConnectionError, "Database connection failed"},
-	ErrDatabaseQuery:       {DatabaseQueryError, "Database query failed"},
-	ErrDatabaseTransaction: {DatabaseTransactionError, "Database transaction failed"},
-	
-	ErrSystemInternal:           {SystemInternalError, "An internal error occurred"},
-	ErrSystemServiceUnavailable: {SystemServiceUnavailable, "Service is temporarily unavailable"},
-	ErrSystemTimeout:            {SystemTimeout, "Request timeout"},
-}
+	pkgErrors.Entry{Err: ErrOrderNotFound, Code: OrderNotFound, Message: "The requested order could not be found", Meta: pkgErrors.NotFound},
+	pkgErrors.Entry{Err: ErrOrderInvalidID, Code: OrderInvalidID, Message: "Invalid order ID format", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrOrderAlreadyExists, Code: OrderAlreadyExists, Message: "Order with this ID already exists", Meta: pkgErrors.Conflict},
+	pkgErrors.As(pkgErrors.Entry{Code: OrderInvalidTransition, Meta: pkgErrors.FailedPrecondition}, func(e *TransitionError) string {
+		return fmt.Sprintf("Order cannot move from %s to %s", e.From, e.To)
+	}),
+	pkgErrors.Entry{Err: ErrOrderInvalidTransition, Code: OrderInvalidTransition, Message: "Order cannot move to the requested status from its current status", Meta: pkgErrors.FailedPrecondition},
+	pkgErrors.Entry{Err: ErrOrderUnknownUser, Code: OrderUnknownUser, Message: "No user exists with the given user ID", Meta: pkgErrors.BadRequest},
 
-// GetErrorInfo returns the ErrorInfo for a given error
-func GetErrorInfo(err error) ErrorInfo {
-	if info, exists := ErrorCatalog[err]; exists {
-		return info
-	}
-	// Default error for unknown errors
-	return ErrorInfo{
-		Code:    SystemInternalError,
-		Message: "An unexpected error occurred",
-	}
-}
+	pkgErrors.Entry{Err: ErrAuthUnauthenticated, Code: AuthUnauthenticated, Message: "A valid bearer token is required", Meta: pkgErrors.Unauthorized},
+	pkgErrors.Entry{Err: ErrAuthForbidden, Code: AuthForbidden, Message: "You are not allowed to perform this action", Meta: pkgErrors.Forbidden},
 
-// IsValidationError checks if the error is a validation error
-func IsValidationError(err error) bool {
-	switch err {
-	case ErrValidationMissingUserID, ErrValidationEmptyItems, ErrValidationInvalidQuantity,
-		 ErrValidationInvalidPrice, ErrValidationMissingProductID, ErrValidationInvalidRequest:
-		return true
-	default:
-		return false
-	}
-}
+	pkgErrors.Entry{Err: ErrCouponInvalid, Code: CouponInvalid, Message: "The coupon code is not valid", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrCouponExpired, Code: CouponExpired, Message: "The coupon has expired", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrCouponMinBasketNotMet, Code: CouponMinBasketNotMet, Message: "The order subtotal does not reach the coupon minimum", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrCouponUsageLimitReached, Code: CouponUsageLimitReached, Message: "The coupon has already been used the maximum number of times", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrCouponCurrencyMismatch, Code: CouponCurrencyMismatch, Message: "The coupon cannot be applied to orders in this currency", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrCouponNotFound, Code: CouponNotFound, Message: "The requested coupon could not be found", Meta: pkgErrors.NotFound},
+	pkgErrors.Entry{Err: ErrCouponAlreadyExists, Code: CouponAlreadyExists, Message: "A coupon with this code already exists", Meta: pkgErrors.Conflict},
 
-// IsDatabaseError checks if the error is a database error
-func IsDatabaseError(err error) bool {
-	switch err {
-	case ErrDatabaseConnection, ErrDatabaseQuery, ErrDatabaseTransaction:
-		return true
-	default:
-		return false
-	}
-}
+	pkgErrors.Entry{Err: ErrIdempotencyKeyMismatch, Code: IdempotencyKeyMismatch, Message: "Idempotency key was already used with a different request body", Meta: pkgErrors.Meta{
+		Status: http.StatusUnprocessableEntity, GRPC: pkgErrors.GRPCFailedPrecondition, LogLevel: pkgErrors.LogInfo,
+	}},
+	pkgErrors.Entry{Err: ErrIdempotencyRequestInProgress, Code: IdempotencyRequestInProgress, Message: "A request with this idempotency key is still being processed", Meta: pkgErrors.Meta{
+		Status: http.StatusConflict, GRPC: pkgErrors.GRPCAborted, Retryable: true, LogLevel: pkgErrors.LogInfo,
+	}},
 
-// OrderErrorCatalog implements the pkgErrors.ErrorCatalog interface
-type OrderErrorCatalog struct{}
+	pkgErrors.Entry{Err: ErrValidationMissingUserID, Code: ValidationMissingUserID, Message: "User ID is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationEmptyItems, Code: ValidationEmptyItems, Message: "Order must contain at least one item", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidQuantity, Code: ValidationInvalidQuantity, Message: "Item quantity must be between 1 and 10000", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidPrice, Code: ValidationInvalidPrice, Message: "Item price must be greater than zero", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingProductID, Code: ValidationMissingProductID, Message: "Product ID is required for all items", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRequest, Code: ValidationInvalidRequest, Message: "Invalid request format", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidStatus, Code: ValidationInvalidStatus, Message: "Unknown order status", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCursor, Code: ValidationInvalidCursor, Message: "Invalid pagination cursor", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidLimit, Code: ValidationInvalidLimit, Message: "Limit must be between 1 and 100", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidDateRange, Code: ValidationInvalidDateRange, Message: "Created-at range must use RFC 3339 timestamps with from before to", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCurrency, Code: ValidationInvalidCurrency, Message: "Item price currency must be a supported ISO 4217 code", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationCurrencyMismatch, Code: ValidationCurrencyMismatch, Message: "All items in an order must share the same currency", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidIdempotencyKey, Code: ValidationInvalidIdempotencyKey, Message: "Idempotency-Key header must be at most 255 characters", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCoupon, Code: ValidationInvalidCoupon, Message: "Coupon definition is invalid", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingShippingAddress, Code: ValidationMissingShippingAddress, Message: "Shipping address is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRecipient, Code: ValidationInvalidRecipient, Message: "Shipping recipient is required and must be at most 200 characters", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidAddressLine, Code: ValidationInvalidAddressLine, Message: "Shipping address line 1 is required and lines must be at most 200 characters", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCity, Code: ValidationInvalidCity, Message: "Shipping city is required and must be at most 200 characters", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRegion, Code: ValidationInvalidRegion, Message: "Shipping region is missing or not valid for the country", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidPostalCode, Code: ValidationInvalidPostalCode, Message: "Shipping postal code does not match the country's format", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCountry, Code: ValidationInvalidCountry, Message: "Shipping country must be a supported ISO 3166-1 alpha-2 code", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationAmountTooLarge, Code: ValidationAmountTooLarge, Message: "Order amount is too large", Meta: pkgErrors.BadRequest},
 
-// NewOrderErrorCatalog creates a new OrderErrorCatalog
-func NewOrderErrorCatalog() *OrderErrorCatalog {
-	return &OrderErrorCatalog{}
-}
+	pkgErrors.Entry{Err: ErrDatabaseConnection, Code: DatabaseConnectionError, Message: "Database connection failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseQuery, Code: DatabaseQueryError, Message: "Database query failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseTransaction, Code: DatabaseTransactionError, Message: "Database transaction failed", Meta: pkgErrors.DatabaseFailure},
+
+	pkgErrors.Entry{Err: ErrSystemInternal, Code: SystemInternalError, Message: "An internal error occurred", Meta: pkgErrors.Internal},
+	pkgErrors.Entry{Err: ErrSystemServiceUnavailable, Code: SystemServiceUnavailable, Message: "Service is temporarily unavailable", Meta: pkgErrors.Unavailable},
+	pkgErrors.Entry{Err: ErrSystemTimeout, Code: SystemTimeout, Message: "Request timeout", Meta: pkgErrors.Timeout},
+)
 
 // GetErrorInfo returns the ErrorInfo for a given error
-func (c *OrderErrorCatalog) GetErrorInfo(err error) pkgErrors.ErrorInfo {
-	if info, exists := ErrorCatalog[err]; exists {
-		return pkgErrors.ErrorInfo{
-			Code:    info.Code,
-			Message: info.Message,
-		}
-	}
-	// Default error for unknown errors
-	return pkgErrors.ErrorInfo{
-		Code:    SystemInternalError,
-		Message: "An unexpected error occurred",
-	}
+func GetErrorInfo(err error) pkgErrors.ErrorInfo {
+	return Catalog.GetErrorInfo(err)
 }
 
 // IsValidationError checks if the error is a validation error
-func (c *OrderErrorCatalog) IsValidationError(err error) bool {
-	return IsValidationError(err)
+func IsValidationError(err error) bool {
+	return Catalog.IsValidationError(err)
 }
 
 // IsDatabaseError checks if the error is a database error
-func (c *OrderErrorCatalog) IsDatabaseError(err error) bool {
-	return IsDatabaseError(err)
+func IsDatabaseError(err error) bool {
+	return Catalog.IsDatabaseError(err)
+}
+
+// NewOrderErrorCatalog returns the catalog the HTTP error handler reports errors with
+func NewOrderErrorCatalog() pkgErrors.ErrorCatalog {
+	return Catalog
 }
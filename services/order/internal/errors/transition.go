@@ -0,0 +1,23 @@
+package errors
+
+import "fmt"
+
+// TransitionError reports an order status change the lifecycle does not allow.
+// It matches ErrOrderInvalidTransition, so callers can still test for that.
+type TransitionError struct {
+	From string
+	To   string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("order status transition from %s to %s not allowed", e.From, e.To)
+}
+
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrOrderInvalidTransition
+}
+
+// ProblemExtensions adds the statuses involved to problem+json responses
+func (e *TransitionError) ProblemExtensions() map[string]any {
+	return map[string]any{"current_status": e.From, "requested_status": e.To}
+}
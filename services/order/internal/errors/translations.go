@@ -0,0 +1,14 @@
+package errors
+
+import (
+	"embed"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Translations answers catalog errors in the languages shipped in locales/,
+// one bundle per locale; the catalog messages themselves are English
+var Translations = pkgErrors.MustLoadTranslations(locales, "locales", "en")
@@ -0,0 +1,12 @@
+package errors_test
+
+import (
+	"testing"
+
+	"github.com/robrt95x/godops/pkg/errors/errorstest"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+func TestTranslations(t *testing.T) {
+	errorstest.RequireTranslations(t, errors.Catalog, errors.Translations)
+}
@@ -0,0 +1,47 @@
+package infra
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/sirupsen/logrus"
+)
+
+// NewEventBus returns a bus backed by the in-memory transport. Events a consumer
+// group gives up on are logged, since nothing else keeps them.
+func NewEventBus(logger *logrus.Logger) *events.Bus {
+	return events.NewBus(events.NewMemoryTransport(events.MemoryTransportConfig{
+		OnDeadLetter: func(topic, group string, envelope events.Envelope, err error) {
+			logger.WithError(err).WithFields(logrus.Fields{
+				"topic":          topic,
+				"consumer_group": group,
+				"event_id":       envelope.ID,
+				"aggregate_id":   envelope.AggregateID,
+			}).Error("Dropped domain event after failed deliveries")
+		},
+	}))
+}
+
+// NewEventLogger returns a handler that logs every event it receives
+func NewEventLogger(logger *logrus.Logger) events.Handler {
+	return func(ctx context.Context, envelope events.Envelope) error {
+		logger.WithFields(logrus.Fields{
+			"event_id":     envelope.ID,
+			"event_type":   envelope.Type,
+			"aggregate_id": envelope.AggregateID,
+		}).Info("Published domain event")
+		return nil
+	}
+}
+
+// NewRelayDeadLetterLogger returns a callback that logs events the relay dropped
+// because a destination rejected them
+func NewRelayDeadLetterLogger(logger *logrus.Logger) func(envelope events.Envelope, err error) {
+	return func(envelope events.Envelope, err error) {
+		logger.WithError(err).WithFields(logrus.Fields{
+			"event_id":     envelope.ID,
+			"event_type":   envelope.Type,
+			"aggregate_id": envelope.AggregateID,
+		}).Error("Dropped domain event rejected by a destination")
+	}
+}
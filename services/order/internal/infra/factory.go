@@ -4,16 +4,26 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/http"
 
 	_ "github.com/lib/pq"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/pkg/events"
 	"github.com/robrt95x/godops/services/order/internal/config"
 	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/infra/paymentclient"
 	"github.com/robrt95x/godops/services/order/internal/infra/postgres"
+	"github.com/robrt95x/godops/services/order/internal/infra/userclient"
+	"github.com/robrt95x/godops/services/order/internal/payment"
 	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/robrt95x/godops/services/order/internal/user"
 )
 
 type RepositoryFactory struct {
-	config *config.Config
+	config       *config.Config
+	db           *sql.DB
+	memoryOutbox *events.MemoryOutbox
+	memorySagas  *memory.PaymentSagaMemoryRepository
 }
 
 func NewRepositoryFactory(config *config.Config) *RepositoryFactory {
@@ -24,21 +34,167 @@ func (f *RepositoryFactory) CreateOrderRepository() (repository.OrderRepository,
 	switch {
 	case f.config.IsMemoryStorage():
 		log.Println("Using in-memory storage for orders")
-		return memory.NewOrderMemoryRepository(), nil
+		return memory.NewOrderMemoryRepositoryWithStores(f.sharedMemoryOutbox(), f.sharedMemorySagas()), nil
 		
 	case f.config.IsPostgresStorage():
 		log.Println("Using PostgreSQL storage for orders")
-		db, err := f.createPostgresConnection()
+		db, err := f.postgresConnection()
 		if err != nil {
-			return nil, fmt.Errorf("failed to create postgres connection: %w", err)
+			return nil, err
 		}
-		return postgres.NewOrderPostgresRepository(db), nil
+		outbox, err := events.NewPostgresOutbox(db, events.DefaultOutboxTable)
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewOrderPostgresRepository(db, outbox), nil
+		
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+func (f *RepositoryFactory) CreateIdempotencyRepository() (repository.IdempotencyRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for idempotency keys")
+		return memory.NewIdempotencyMemoryRepository(), nil
+		
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for idempotency keys")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewIdempotencyPostgresRepository(db), nil
+		
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+func (f *RepositoryFactory) CreateCouponRepository() (repository.CouponRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for coupons")
+		return memory.NewCouponMemoryRepository(), nil
+		
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for coupons")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewCouponPostgresRepository(db), nil
+		
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+func (f *RepositoryFactory) CreatePaymentSagaRepository() (repository.PaymentSagaRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for payment sagas")
+		return f.sharedMemorySagas(), nil
+		
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for payment sagas")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewPaymentSagaPostgresRepository(db), nil
 		
 	default:
 		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
 	}
 }
 
+// CreatePaymentService returns the client for the payment service at PAYMENT_SERVICE_URL
+func (f *RepositoryFactory) CreatePaymentService() (payment.Service, error) {
+	if f.config.PaymentServiceURL == "" {
+		return nil, fmt.Errorf("payment service URL is not configured")
+	}
+	return paymentclient.NewClient(f.config.PaymentServiceURL, &http.Client{Timeout: f.config.RequestTimeout}), nil
+}
+
+// CreateUserDirectory returns the client for the user service at USER_SERVICE_URL
+func (f *RepositoryFactory) CreateUserDirectory() (user.UserDirectory, error) {
+	if f.config.UserServiceURL == "" {
+		return nil, fmt.Errorf("user service URL is not configured")
+	}
+	return userclient.NewClient(f.config.UserServiceURL, nil, userclient.Config{
+		Timeout:          f.config.UserServiceTimeout,
+		MaxRetries:       f.config.UserServiceMaxRetries,
+		RetryBackoff:     f.config.UserServiceRetryBackoff,
+		FailureThreshold: f.config.UserServiceBreakerThreshold,
+		OpenDuration:     f.config.UserServiceBreakerOpen,
+		Email:            f.config.UserServiceEmail,
+		Password:         f.config.UserServicePassword,
+	}), nil
+}
+
+// CreateOutbox returns the outbox that order repositories from this factory write to
+func (f *RepositoryFactory) CreateOutbox() (events.Outbox, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		return f.sharedMemoryOutbox(), nil
+		
+	case f.config.IsPostgresStorage():
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return events.NewPostgresOutbox(db, events.DefaultOutboxTable)
+		
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+// sharedMemoryOutbox lets the relay read what in-memory order repositories write
+func (f *RepositoryFactory) sharedMemoryOutbox() *events.MemoryOutbox {
+	if f.memoryOutbox == nil {
+		f.memoryOutbox = events.NewMemoryOutbox()
+	}
+	return f.memoryOutbox
+}
+
+// sharedMemorySagas lets the saga runner read the sagas in-memory order repositories create
+func (f *RepositoryFactory) sharedMemorySagas() *memory.PaymentSagaMemoryRepository {
+	if f.memorySagas == nil {
+		f.memorySagas = memory.NewPaymentSagaMemoryRepository()
+	}
+	return f.memorySagas
+}
+
+// CreateMigrator returns the schema migrator; only postgres storage has a schema
+func (f *RepositoryFactory) CreateMigrator() (*db.Migrator, error) {
+	if !f.config.IsPostgresStorage() {
+		return nil, fmt.Errorf("migrations require postgres storage, got: %s", f.config.StorageType)
+	}
+	
+	conn, err := f.postgresConnection()
+	if err != nil {
+		return nil, err
+	}
+	return postgres.NewMigrator(conn)
+}
+
+// postgresConnection opens the shared connection pool on first use
+func (f *RepositoryFactory) postgresConnection() (*sql.DB, error) {
+	if f.db != nil {
+		return f.db, nil
+	}
+	
+	db, err := f.createPostgresConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection: %w", err)
+	}
+	f.db = db
+	return db, nil
+}
+
 func (f *RepositoryFactory) createPostgresConnection() (*sql.DB, error) {
 	db, err := sql.Open("postgres", f.config.GetDatabaseURL())
 	if err != nil {
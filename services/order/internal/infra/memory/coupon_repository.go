@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+type CouponMemoryRepository struct {
+	coupons     map[string]*entity.Coupon
+	redemptions []entity.CouponRedemption
+	mutex       sync.RWMutex
+}
+
+func NewCouponMemoryRepository() *CouponMemoryRepository {
+	return &CouponMemoryRepository{
+		coupons: make(map[string]*entity.Coupon),
+	}
+}
+
+// Save stores coupon; a code that is already taken fails with errors.ErrCouponAlreadyExists
+func (r *CouponMemoryRepository) Save(ctx context.Context, coupon *entity.Coupon) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.coupons[coupon.Code]; exists {
+		return errors.ErrCouponAlreadyExists
+	}
+
+	couponCopy := *coupon
+	r.coupons[coupon.Code] = &couponCopy
+	return nil
+}
+
+func (r *CouponMemoryRepository) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	coupon, exists := r.coupons[code]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	couponCopy := *coupon
+	return &couponCopy, nil
+}
+
+func (r *CouponMemoryRepository) RecordRedemption(ctx context.Context, redemption *entity.CouponRedemption, maxUses int) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if maxUses > 0 {
+		used := 0
+		for _, existing := range r.redemptions {
+			if existing.CouponCode == redemption.CouponCode && existing.UserID == redemption.UserID {
+				used++
+			}
+		}
+		if used >= maxUses {
+			return false, nil
+		}
+	}
+
+	r.redemptions = append(r.redemptions, *redemption)
+	return true, nil
+}
+
+func (r *CouponMemoryRepository) DeleteRedemption(ctx context.Context, couponCode, orderID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	kept := r.redemptions[:0]
+	for _, existing := range r.redemptions {
+		if existing.CouponCode != couponCode || existing.OrderID != orderID {
+			kept = append(kept, existing)
+		}
+	}
+	r.redemptions = kept
+	return nil
+}
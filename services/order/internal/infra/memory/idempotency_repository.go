@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+// idempotencyKey identifies a record; keys are scoped to the subject that sent them
+type idempotencyKey struct {
+	subject string
+	key     string
+}
+
+type IdempotencyMemoryRepository struct {
+	records map[idempotencyKey]*entity.IdempotencyRecord
+	mutex   sync.Mutex
+}
+
+func NewIdempotencyMemoryRepository() *IdempotencyMemoryRepository {
+	return &IdempotencyMemoryRepository{
+		records: make(map[idempotencyKey]*entity.IdempotencyRecord),
+	}
+}
+
+func (r *IdempotencyMemoryRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	id := idempotencyKey{subject: record.Subject, key: record.Key}
+	if existing, exists := r.records[id]; exists && !existing.IsExpired(record.CreatedAt) {
+		return copyIdempotencyRecord(existing), nil
+	}
+
+	r.records[id] = copyIdempotencyRecord(record)
+	return nil, nil
+}
+
+func (r *IdempotencyMemoryRepository) Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte, expiresAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	record, exists := r.records[idempotencyKey{subject: subject, key: key}]
+	if !exists || record.IsCompleted() {
+		return sql.ErrNoRows
+	}
+
+	record.StatusCode = statusCode
+	record.ResponseBody = append([]byte(nil), responseBody...)
+	record.ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *IdempotencyMemoryRepository) Delete(ctx context.Context, subject, key string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.records, idempotencyKey{subject: subject, key: key})
+	return nil
+}
+
+func (r *IdempotencyMemoryRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var deleted int64
+	for id, record := range r.records {
+		if record.IsExpired(now) {
+			delete(r.records, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func copyIdempotencyRecord(record *entity.IdempotencyRecord) *entity.IdempotencyRecord {
+	recordCopy := *record
+	recordCopy.ResponseBody = append([]byte(nil), record.ResponseBody...)
+	return &recordCopy
+}
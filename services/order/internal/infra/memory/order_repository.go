@@ -1,25 +1,40 @@
 package memory
 
 import (
+	"context"
 	"database/sql"
+	"sort"
 	"sync"
+	"time"
 
+	"github.com/robrt95x/godops/pkg/events"
 	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/repository"
 )
 
 type OrderMemoryRepository struct {
 	orders map[string]*entity.Order
+	outbox *events.MemoryOutbox
+	sagas  *PaymentSagaMemoryRepository
 	mutex  sync.RWMutex
 }
 
 func NewOrderMemoryRepository() *OrderMemoryRepository {
+	return NewOrderMemoryRepositoryWithStores(events.NewMemoryOutbox(), NewPaymentSagaMemoryRepository())
+}
+
+// NewOrderMemoryRepositoryWithStores stores events saved with orders in
+// outbox and their payment sagas in sagas
+func NewOrderMemoryRepositoryWithStores(outbox *events.MemoryOutbox, sagas *PaymentSagaMemoryRepository) *OrderMemoryRepository {
 	return &OrderMemoryRepository{
 		orders: make(map[string]*entity.Order),
+		outbox: outbox,
+		sagas:  sagas,
 		mutex:  sync.RWMutex{},
 	}
 }
 
-func (r *OrderMemoryRepository) Save(order *entity.Order) error {
+func (r *OrderMemoryRepository) Save(ctx context.Context, order *entity.Order, saga *entity.PaymentSaga, envelopes ...events.Envelope) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	
@@ -29,11 +44,17 @@ func (r *OrderMemoryRepository) Save(order *entity.Order) error {
 	copy(itemsCopy, order.Items)
 	orderCopy.Items = itemsCopy
 	
+	if saga != nil {
+		if _, err := r.sagas.Create(ctx, saga); err != nil {
+			return err
+		}
+	}
 	r.orders[order.ID] = &orderCopy
+	r.outbox.Append(envelopes...)
 	return nil
 }
 
-func (r *OrderMemoryRepository) FindByID(id string) (*entity.Order, error) {
+func (r *OrderMemoryRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
@@ -51,7 +72,99 @@ func (r *OrderMemoryRepository) FindByID(id string) (*entity.Order, error) {
 	return &orderCopy, nil
 }
 
+func (r *OrderMemoryRepository) Update(ctx context.Context, order *entity.Order, from entity.OrderStatus, envelopes ...events.Envelope) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	stored, exists := r.orders[order.ID]
+	if !exists || stored.Status != from {
+		return sql.ErrNoRows
+	}
+	
+	orderCopy := *order
+	itemsCopy := make([]entity.OrderItem, len(order.Items))
+	copy(itemsCopy, order.Items)
+	orderCopy.Items = itemsCopy
+	
+	r.orders[order.ID] = &orderCopy
+	r.outbox.Append(envelopes...)
+	return nil
+}
+
+func (r *OrderMemoryRepository) List(ctx context.Context, filter repository.OrderFilter) ([]*entity.Order, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	
+	orders := make([]*entity.Order, 0)
+	for _, order := range r.orders {
+		if !matchesFilter(order, filter) {
+			continue
+		}
+		orderCopy := *order
+		itemsCopy := make([]entity.OrderItem, len(order.Items))
+		copy(itemsCopy, order.Items)
+		orderCopy.Items = itemsCopy
+		orders = append(orders, &orderCopy)
+	}
+	
+	sort.Slice(orders, func(i, j int) bool {
+		return sortsBefore(orders[i].CreatedAt, orders[i].ID, orders[j].CreatedAt, orders[j].ID)
+	})
+	
+	if filter.Limit > 0 && len(orders) > filter.Limit {
+		orders = orders[:filter.Limit]
+	}
+	return orders, nil
+}
+
+func matchesFilter(order *entity.Order, filter repository.OrderFilter) bool {
+	if filter.UserID != "" && order.UserID != filter.UserID {
+		return false
+	}
+	if filter.ProductID != "" && !containsProduct(order, filter.ProductID) {
+		return false
+	}
+	if filter.Status != "" && order.Status != filter.Status {
+		return false
+	}
+	if !filter.CreatedFrom.IsZero() && order.CreatedAt.Before(filter.CreatedFrom) {
+		return false
+	}
+	if !filter.CreatedTo.IsZero() && !order.CreatedAt.Before(filter.CreatedTo) {
+		return false
+	}
+	if filter.After != nil && !sortsBefore(filter.After.CreatedAt, filter.After.ID, order.CreatedAt, order.ID) {
+		return false
+	}
+	return true
+}
+
+func containsProduct(order *entity.Order, productID string) bool {
+	for _, item := range order.Items {
+		if item.ProductID == productID {
+			return true
+		}
+	}
+	return false
+}
+
+// sortsBefore orders newest first, breaking CreatedAt ties by descending ID
+func sortsBefore(createdAtA time.Time, idA string, createdAtB time.Time, idB string) bool {
+	if !createdAtA.Equal(createdAtB) {
+		return createdAtA.After(createdAtB)
+	}
+	return idA > idB
+}
+
 // Additional helper methods for testing
+func (r *OrderMemoryRepository) Outbox() *events.MemoryOutbox {
+	return r.outbox
+}
+
+func (r *OrderMemoryRepository) Sagas() *PaymentSagaMemoryRepository {
+	return r.sagas
+}
+
 func (r *OrderMemoryRepository) Clear() {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+type PaymentSagaMemoryRepository struct {
+	sagas map[string]*entity.PaymentSaga
+	mutex sync.Mutex
+}
+
+func NewPaymentSagaMemoryRepository() *PaymentSagaMemoryRepository {
+	return &PaymentSagaMemoryRepository{
+		sagas: make(map[string]*entity.PaymentSaga),
+	}
+}
+
+func (r *PaymentSagaMemoryRepository) Create(ctx context.Context, saga *entity.PaymentSaga) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.sagas[saga.OrderID]; exists {
+		return false, nil
+	}
+
+	sagaCopy := *saga
+	r.sagas[saga.OrderID] = &sagaCopy
+	return true, nil
+}
+
+func (r *PaymentSagaMemoryRepository) FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentSaga, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	saga, exists := r.sagas[orderID]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	sagaCopy := *saga
+	return &sagaCopy, nil
+}
+
+func (r *PaymentSagaMemoryRepository) Update(ctx context.Context, saga *entity.PaymentSaga, from entity.SagaState, lease time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.sagas[saga.OrderID]
+	if !exists || stored.State != from || !stored.NextAttemptAt.Equal(lease) {
+		return sql.ErrNoRows
+	}
+
+	sagaCopy := *saga
+	r.sagas[saga.OrderID] = &sagaCopy
+	return nil
+}
+
+func (r *PaymentSagaMemoryRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.PaymentSaga, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var due []*entity.PaymentSaga
+	for _, saga := range r.sagas {
+		if !saga.State.IsTerminal() && !saga.NextAttemptAt.After(now) {
+			due = append(due, saga)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].NextAttemptAt.Before(due[j].NextAttemptAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*entity.PaymentSaga, 0, len(due))
+	for _, saga := range due {
+		saga.NextAttemptAt = leaseUntil
+		sagaCopy := *saga
+		claimed = append(claimed, &sagaCopy)
+	}
+	return claimed, nil
+}
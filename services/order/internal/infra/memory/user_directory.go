@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/robrt95x/godops/services/order/internal/user"
+)
+
+// UserDirectory is an in-memory user.UserDirectory for tests and local runs
+type UserDirectory struct {
+	users map[string]*user.User
+	err   error
+	mutex sync.RWMutex
+}
+
+// NewUserDirectory returns a directory that knows the users with the given IDs
+func NewUserDirectory(ids ...string) *UserDirectory {
+	directory := &UserDirectory{users: make(map[string]*user.User)}
+	for _, id := range ids {
+		directory.Add(&user.User{ID: id})
+	}
+	return directory
+}
+
+func (d *UserDirectory) Add(u *user.User) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	userCopy := *u
+	d.users[u.ID] = &userCopy
+}
+
+// FailWith makes every lookup return err until it is called again with nil
+func (d *UserDirectory) FailWith(err error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.err = err
+}
+
+func (d *UserDirectory) FindByID(ctx context.Context, id string) (*user.User, error) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+
+	if d.err != nil {
+		return nil, d.err
+	}
+	u, exists := d.users[id]
+	if !exists {
+		return nil, user.ErrNotFound
+	}
+
+	userCopy := *u
+	return &userCopy, nil
+}
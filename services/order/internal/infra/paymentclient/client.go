@@ -0,0 +1,104 @@
+package paymentclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/payment"
+)
+
+// Client calls the payment service's HTTP API
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+func (c *Client) CreatePayment(ctx context.Context, orderID string, amount money.Money) (*payment.Payment, error) {
+	return c.do(ctx, http.MethodPost, "/payments", map[string]interface{}{
+		"order_id": orderID,
+		"amount":   amount,
+	})
+}
+
+func (c *Client) GetPayment(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	return c.do(ctx, http.MethodGet, "/payments/"+url.PathEscape(paymentID), nil)
+}
+
+func (c *Client) Authorize(ctx context.Context, paymentID, cardToken string) (*payment.Payment, error) {
+	return c.do(ctx, http.MethodPost, "/payments/"+url.PathEscape(paymentID)+"/authorize", map[string]string{
+		"card_token": cardToken,
+	})
+}
+
+func (c *Client) Capture(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	return c.do(ctx, http.MethodPost, "/payments/"+url.PathEscape(paymentID)+"/capture", nil)
+}
+
+func (c *Client) Void(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	return c.do(ctx, http.MethodPost, "/payments/"+url.PathEscape(paymentID)+"/void", nil)
+}
+
+// do sends a request and decodes the payment in the response, mapping error
+// responses onto the payment port's errors
+func (c *Client) do(ctx context.Context, method, path string, body interface{}) (*payment.Payment, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calling payment service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		var result payment.Payment
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("decoding payment service response: %w", err)
+		}
+		return &result, nil
+	}
+
+	var errorInfo pkgErrors.ErrorInfo
+	json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&errorInfo)
+
+	switch resp.StatusCode {
+	case http.StatusPaymentRequired:
+		return nil, fmt.Errorf("%w: %s", payment.ErrDeclined, errorInfo.Message)
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity:
+		return nil, fmt.Errorf("%w: %s %s", payment.ErrRejected, resp.Status, errorInfo.Code)
+	default:
+		return nil, fmt.Errorf("payment service returned %s %s", resp.Status, errorInfo.Code)
+	}
+}
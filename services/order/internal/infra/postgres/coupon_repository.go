@@ -0,0 +1,149 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+
+	"github.com/lib/pq"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+// uniqueViolation is the PostgreSQL error code for a unique index conflict
+const uniqueViolation = "23505"
+
+type CouponPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewCouponPostgresRepository(db *sql.DB) *CouponPostgresRepository {
+	return &CouponPostgresRepository{db: db}
+}
+
+// Save inserts coupon; a code that is already taken fails with errors.ErrCouponAlreadyExists
+func (r *CouponPostgresRepository) Save(ctx context.Context, coupon *entity.Coupon) error {
+	// Fixed-amount and minimum-basket values share the coupon's single currency column
+	currency := coupon.AmountOff.Currency
+	if currency == "" {
+		currency = coupon.MinBasket.Currency
+	}
+
+	var expiresAt sql.NullTime
+	if !coupon.ExpiresAt.IsZero() {
+		expiresAt = sql.NullTime{Time: coupon.ExpiresAt, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO coupons (code, type, percent_off, amount_off, min_basket, currency, expires_at, max_uses_per_user, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		coupon.Code,
+		coupon.Type,
+		coupon.PercentOff,
+		coupon.AmountOff.Amount,
+		coupon.MinBasket.Amount,
+		currency,
+		expiresAt,
+		coupon.MaxUsesPerUser,
+		coupon.CreatedAt,
+	)
+
+	var pqErr *pq.Error
+	if stdErrors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return errors.ErrCouponAlreadyExists
+	}
+	return err
+}
+
+func (r *CouponPostgresRepository) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	var coupon entity.Coupon
+	var currency string
+	var expiresAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT code, type, percent_off, amount_off, min_basket, currency, expires_at, max_uses_per_user, created_at
+		FROM coupons WHERE code = $1`, code).Scan(
+		&coupon.Code,
+		&coupon.Type,
+		&coupon.PercentOff,
+		&coupon.AmountOff.Amount,
+		&coupon.MinBasket.Amount,
+		&currency,
+		&expiresAt,
+		&coupon.MaxUsesPerUser,
+		&coupon.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if coupon.AmountOff.Amount != 0 {
+		coupon.AmountOff.Currency = currency
+	}
+	if coupon.MinBasket.Amount != 0 {
+		coupon.MinBasket.Currency = currency
+	}
+	if expiresAt.Valid {
+		coupon.ExpiresAt = expiresAt.Time
+	}
+
+	return &coupon, nil
+}
+
+// RecordRedemption inserts the redemption unless the user has used the coupon
+// maxUses times already. The count and insert run under a transaction-scoped
+// advisory lock on the (coupon, user) pair: under READ COMMITTED two
+// concurrent orders would otherwise both see a count below the limit.
+func (r *CouponPostgresRepository) RecordRedemption(ctx context.Context, redemption *entity.CouponRedemption, maxUses int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if maxUses > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`SELECT pg_advisory_xact_lock(hashtext('coupon_redemption:' || $1 || ':' || $2))`,
+			redemption.CouponCode,
+			redemption.UserID,
+		); err != nil {
+			return false, err
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO coupon_redemptions (coupon_code, user_id, order_id, redeemed_at)
+		SELECT $1, $2, $3, $4
+		WHERE $5 = 0 OR (
+			SELECT COUNT(*) FROM coupon_redemptions WHERE coupon_code = $1 AND user_id = $2
+		) < $5`,
+		redemption.CouponCode,
+		redemption.UserID,
+		redemption.OrderID,
+		redemption.RedeemedAt,
+		maxUses,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected != 1 {
+		return false, nil
+	}
+
+	return true, tx.Commit()
+}
+
+func (r *CouponPostgresRepository) DeleteRedemption(ctx context.Context, couponCode, orderID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM coupon_redemptions WHERE coupon_code = $1 AND order_id = $2`,
+		couponCode,
+		orderID,
+	)
+	return err
+}
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+type IdempotencyPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewIdempotencyPostgresRepository(db *sql.DB) *IdempotencyPostgresRepository {
+	return &IdempotencyPostgresRepository{db: db}
+}
+
+func (r *IdempotencyPostgresRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	// Take over the key only when it is free or its previous record has expired,
+	// which for an in-progress record means its lease has run out
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO idempotency_keys (subject, key, request_hash, status_code, response_body, created_at, expires_at)
+		VALUES ($1, $2, $3, 0, NULL, $4, $5)
+		ON CONFLICT (subject, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = 0,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at`,
+		record.Subject,
+		record.Key,
+		record.RequestHash,
+		record.CreatedAt,
+		record.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if affected == 1 {
+		return nil, nil
+	}
+
+	var existing entity.IdempotencyRecord
+	err = r.db.QueryRowContext(ctx,
+		`SELECT subject, key, request_hash, status_code, response_body, created_at, expires_at
+		FROM idempotency_keys WHERE subject = $1 AND key = $2`, record.Subject, record.Key).Scan(
+		&existing.Subject,
+		&existing.Key,
+		&existing.RequestHash,
+		&existing.StatusCode,
+		&existing.ResponseBody,
+		&existing.CreatedAt,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &existing, nil
+}
+
+func (r *IdempotencyPostgresRepository) Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte, expiresAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE idempotency_keys SET status_code = $3, response_body = $4, expires_at = $5
+		WHERE subject = $1 AND key = $2 AND status_code = 0`,
+		subject,
+		key,
+		statusCode,
+		responseBody,
+		expiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (r *IdempotencyPostgresRepository) Delete(ctx context.Context, subject, key string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE subject = $1 AND key = $2`, subject, key)
+	return err
+}
+
+func (r *IdempotencyPostgresRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,23 @@
+CREATE TABLE orders (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    items JSONB NOT NULL,
+    status TEXT NOT NULL,
+    coupon_code TEXT NOT NULL DEFAULT '',
+    subtotal_amount BIGINT NOT NULL,
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    total_amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    shipping_recipient TEXT NOT NULL DEFAULT '',
+    shipping_line1 TEXT NOT NULL DEFAULT '',
+    shipping_line2 TEXT NOT NULL DEFAULT '',
+    shipping_city TEXT NOT NULL DEFAULT '',
+    shipping_region TEXT NOT NULL DEFAULT '',
+    shipping_postal_code TEXT NOT NULL DEFAULT '',
+    shipping_country CHAR(2) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- Serves cursor-paginated listing by user, newest first
+CREATE INDEX orders_user_created_idx ON orders (user_id, created_at DESC, id DESC);
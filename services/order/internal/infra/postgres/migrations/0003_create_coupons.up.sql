@@ -0,0 +1,21 @@
+CREATE TABLE coupons (
+    code TEXT PRIMARY KEY,
+    type TEXT NOT NULL,
+    percent_off INTEGER NOT NULL DEFAULT 0,
+    amount_off BIGINT NOT NULL DEFAULT 0,
+    min_basket BIGINT NOT NULL DEFAULT 0,
+    currency TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ,
+    max_uses_per_user INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE coupon_redemptions (
+    coupon_code TEXT NOT NULL REFERENCES coupons (code),
+    user_id TEXT NOT NULL,
+    order_id TEXT NOT NULL,
+    redeemed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (coupon_code, order_id)
+);
+
+CREATE INDEX coupon_redemptions_user_idx ON coupon_redemptions (coupon_code, user_id);
@@ -0,0 +1,16 @@
+ALTER TABLE orders ADD COLUMN items JSONB NOT NULL DEFAULT '[]';
+
+UPDATE orders o SET items = (
+    SELECT jsonb_agg(jsonb_build_object(
+               'product_id', i.product_id,
+               'quantity', i.quantity,
+               'price', jsonb_build_object('amount', i.unit_price_amount, 'currency', i.currency)
+           ) ORDER BY i.line_number)
+    FROM order_items i
+    WHERE i.order_id = o.id
+)
+WHERE EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = o.id);
+
+ALTER TABLE orders ALTER COLUMN items DROP DEFAULT;
+
+DROP TABLE order_items;
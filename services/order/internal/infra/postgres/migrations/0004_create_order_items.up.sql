@@ -0,0 +1,24 @@
+CREATE TABLE order_items (
+    order_id TEXT NOT NULL REFERENCES orders (id) ON DELETE CASCADE,
+    line_number INTEGER NOT NULL,
+    product_id TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price_amount BIGINT NOT NULL,
+    currency CHAR(3) NOT NULL,
+    PRIMARY KEY (order_id, line_number)
+);
+
+-- Serves per-product queries such as "orders containing product X"
+CREATE INDEX order_items_product_idx ON order_items (product_id, order_id);
+
+INSERT INTO order_items (order_id, line_number, product_id, quantity, unit_price_amount, currency)
+SELECT o.id,
+       item.ordinality,
+       item.value ->> 'product_id',
+       (item.value ->> 'quantity')::INTEGER,
+       (item.value -> 'price' ->> 'amount')::BIGINT,
+       COALESCE(item.value -> 'price' ->> 'currency', o.currency)
+FROM orders o
+CROSS JOIN LATERAL jsonb_array_elements(o.items) WITH ORDINALITY AS item (value, ordinality);
+
+ALTER TABLE orders DROP COLUMN items;
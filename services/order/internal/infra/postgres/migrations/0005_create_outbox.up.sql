@@ -0,0 +1,13 @@
+CREATE TABLE outbox (
+    position BIGSERIAL PRIMARY KEY,
+    id TEXT NOT NULL UNIQUE,
+    event_type TEXT NOT NULL,
+    aggregate_type TEXT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    dispatched_at TIMESTAMPTZ
+);
+
+-- The relay only ever scans undispatched rows in insertion order
+CREATE INDEX outbox_pending_idx ON outbox (position) WHERE dispatched_at IS NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE payment_sagas (
+    order_id TEXT PRIMARY KEY,
+    state TEXT NOT NULL,
+    payment_token TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    payment_id TEXT NOT NULL DEFAULT '',
+    failure_reason TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    deadline TIMESTAMPTZ NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+-- The saga runner only ever scans unfinished sagas by when they are next due
+CREATE INDEX payment_sagas_due_idx ON payment_sagas (next_attempt_at)
+    WHERE state NOT IN ('COMPLETED', 'COMPENSATED');
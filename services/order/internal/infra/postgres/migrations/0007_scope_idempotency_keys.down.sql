@@ -0,0 +1,6 @@
+-- Different callers may hold the same key, which a key-only primary key
+-- cannot store; the records are short-lived, so they are dropped
+DELETE FROM idempotency_keys;
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys DROP COLUMN subject;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
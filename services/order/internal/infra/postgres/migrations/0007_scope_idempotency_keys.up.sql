@@ -0,0 +1,5 @@
+-- Keys are scoped to the caller that sent them; records from before the
+-- scoping belong to no caller and are left to expire
+ALTER TABLE idempotency_keys ADD COLUMN subject TEXT NOT NULL DEFAULT '';
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (subject, key);
@@ -1,65 +1,319 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/lib/pq"
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/pkg/money"
 	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/repository"
 )
 
+const orderColumns = `id, user_id, status, coupon_code, subtotal_amount, discount_amount, total_amount, currency,
+	shipping_recipient, shipping_line1, shipping_line2, shipping_city, shipping_region, shipping_postal_code, shipping_country,
+	created_at, updated_at`
+
 type OrderPostgresRespository struct {
-	db *sql.DB
+	db     *sql.DB
+	outbox *events.PostgresOutbox
 }
 
-func NewOrderPostgresRepository(db *sql.DB) *OrderPostgresRespository {
-	return &OrderPostgresRespository{db: db}
+func NewOrderPostgresRepository(db *sql.DB, outbox *events.PostgresOutbox) *OrderPostgresRespository {
+	return &OrderPostgresRespository{db: db, outbox: outbox}
 }
 
-func (r *OrderPostgresRespository) Save(order *entity.Order) error {
-	itemsJson, _ := json.Marshal(order.Items)
+// Save writes the order row, its items, its payment saga and its outbox events in one transaction
+func (r *OrderPostgresRespository) Save(ctx context.Context, order *entity.Order, saga *entity.PaymentSaga, envelopes ...events.Envelope) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
 
-	_, err := r.db.Exec(
-		`INSERT INTO orders (id, user_id, items, status, coupon_code, total, shipping_address, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO orders (`+orderColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
 		order.ID,
 		order.UserID,
-		itemsJson,
 		order.Status,
 		order.CouponCode,
-		order.Total,
-		order.ShippingAddress,
+		order.Subtotal.Amount,
+		order.Discount.Amount,
+		order.Total.Amount,
+		order.Total.Currency,
+		order.ShippingAddress.Recipient,
+		order.ShippingAddress.Line1,
+		order.ShippingAddress.Line2,
+		order.ShippingAddress.City,
+		order.ShippingAddress.Region,
+		order.ShippingAddress.PostalCode,
+		order.ShippingAddress.Country,
 		order.CreatedAt,
 		order.UpdatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	for i, item := range order.Items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO order_items (order_id, line_number, product_id, quantity, unit_price_amount, currency)
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			order.ID,
+			i+1,
+			item.ProductID,
+			item.Quantity,
+			item.Price.Amount,
+			item.Price.Currency,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if saga != nil {
+		if _, err := insertPaymentSaga(ctx, tx, saga); err != nil {
+			return err
+		}
+	}
+
+	if err := r.outbox.Insert(ctx, tx, envelopes...); err != nil {
+		return err
+	}
 
-	return err
+	return tx.Commit()
 }
 
-func (r *OrderPostgresRespository) FindByID(id string) (*entity.Order, error) {
-	var order entity.Order
-	var itemsJson []byte
+func (r *OrderPostgresRespository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+qualifiedColumns(orderColumns, "o")+`,
+			i.product_id, i.quantity, i.unit_price_amount, i.currency
+		FROM orders o
+		LEFT JOIN order_items i ON i.order_id = o.id
+		WHERE o.id = $1
+		ORDER BY i.line_number`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	err := r.db.QueryRow(
-		`SELECT id, user_id, items, status, coupon_code, total, shipping_address, created_at, updated_at 
-		FROM orders WHERE id = $1`, id).Scan(
+	var order *entity.Order
+	for rows.Next() {
+		var scanned entity.Order
+		var productID, currency sql.NullString
+		var quantity, unitPrice sql.NullInt64
+
+		dest := append(orderFields(&scanned), &productID, &quantity, &unitPrice, &currency)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		if order == nil {
+			order = finishOrder(&scanned)
+		}
+		// The left join yields a single row of NULLs for an order without items
+		if productID.Valid {
+			order.Items = append(order.Items, entity.OrderItem{
+				ProductID: productID.String,
+				Quantity:  int(quantity.Int64),
+				Price:     money.Money{Amount: unitPrice.Int64, Currency: currency.String},
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	return order, nil
+}
+
+func (r *OrderPostgresRespository) List(ctx context.Context, filter repository.OrderFilter) ([]*entity.Order, error) {
+	var conditions []string
+	var args []interface{}
+
+	addArg := func(value interface{}) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.UserID != "" {
+		conditions = append(conditions, "user_id = "+addArg(filter.UserID))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+addArg(filter.Status))
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= "+addArg(filter.CreatedFrom))
+	}
+	if !filter.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < "+addArg(filter.CreatedTo))
+	}
+	if filter.ProductID != "" {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM order_items i WHERE i.order_id = orders.id AND i.product_id = "+addArg(filter.ProductID)+")")
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) < (%s, %s)", addArg(filter.After.CreatedAt), addArg(filter.After.ID)))
+	}
+
+	query := `SELECT ` + orderColumns + ` FROM orders`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT " + addArg(filter.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orders := make([]*entity.Order, 0)
+	for rows.Next() {
+		var order entity.Order
+		if err := rows.Scan(orderFields(&order)...); err != nil {
+			return nil, err
+		}
+		orders = append(orders, finishOrder(&order))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.loadItems(ctx, orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// loadItems fetches the items of a page of orders in a single query
+func (r *OrderPostgresRespository) loadItems(ctx context.Context, orders []*entity.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*entity.Order, len(orders))
+	ids := make([]string, 0, len(orders))
+	for _, order := range orders {
+		byID[order.ID] = order
+		ids = append(ids, order.ID)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT order_id, product_id, quantity, unit_price_amount, currency
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, line_number`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderID string
+		var item entity.OrderItem
+		if err := rows.Scan(&orderID, &item.ProductID, &item.Quantity, &item.Price.Amount, &item.Price.Currency); err != nil {
+			return err
+		}
+		byID[orderID].Items = append(byID[orderID].Items, item)
+	}
+
+	return rows.Err()
+}
+
+func (r *OrderPostgresRespository) Update(ctx context.Context, order *entity.Order, from entity.OrderStatus, envelopes ...events.Envelope) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE orders SET status = $2, coupon_code = $3, subtotal_amount = $4, discount_amount = $5, total_amount = $6, currency = $7,
+		shipping_recipient = $8, shipping_line1 = $9, shipping_line2 = $10, shipping_city = $11, shipping_region = $12,
+		shipping_postal_code = $13, shipping_country = $14, updated_at = $15
+		WHERE id = $1 AND status = $16`,
+		order.ID,
+		order.Status,
+		order.CouponCode,
+		order.Subtotal.Amount,
+		order.Discount.Amount,
+		order.Total.Amount,
+		order.Total.Currency,
+		order.ShippingAddress.Recipient,
+		order.ShippingAddress.Line1,
+		order.ShippingAddress.Line2,
+		order.ShippingAddress.City,
+		order.ShippingAddress.Region,
+		order.ShippingAddress.PostalCode,
+		order.ShippingAddress.Country,
+		order.UpdatedAt,
+		from,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	if err := r.outbox.Insert(ctx, tx, envelopes...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// orderFields returns scan destinations matching orderColumns
+func orderFields(order *entity.Order) []interface{} {
+	return []interface{}{
 		&order.ID,
 		&order.UserID,
-		&itemsJson,
 		&order.Status,
 		&order.CouponCode,
-		&order.Total,
-		&order.ShippingAddress,
+		&order.Subtotal.Amount,
+		&order.Discount.Amount,
+		&order.Total.Amount,
+		&order.Total.Currency,
+		&order.ShippingAddress.Recipient,
+		&order.ShippingAddress.Line1,
+		&order.ShippingAddress.Line2,
+		&order.ShippingAddress.City,
+		&order.ShippingAddress.Region,
+		&order.ShippingAddress.PostalCode,
+		&order.ShippingAddress.Country,
 		&order.CreatedAt,
 		&order.UpdatedAt,
-	)
-
-	if err != nil {
-		return nil, err
 	}
+}
 
-	if err := json.Unmarshal(itemsJson, &order.Items); err != nil {
-		return nil, err
+// qualifiedColumns prefixes each column in a comma-separated list with a table alias
+func qualifiedColumns(columns, alias string) string {
+	fields := strings.Split(columns, ",")
+	for i, field := range fields {
+		fields[i] = alias + "." + strings.TrimSpace(field)
 	}
+	return strings.Join(fields, ", ")
+}
 
-	return &order, nil
+func finishOrder(order *entity.Order) *entity.Order {
+	// The breakdown amounts share the order's single currency column
+	order.Subtotal.Currency = order.Total.Currency
+	order.Discount.Currency = order.Total.Currency
+	order.Items = make([]entity.OrderItem, 0)
+	return order
 }
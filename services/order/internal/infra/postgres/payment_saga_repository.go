@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+const paymentSagaColumns = `order_id, state, payment_token, amount, currency, payment_id, failure_reason,
+	last_error, attempts, deadline, next_attempt_at, created_at, updated_at`
+
+type PaymentSagaPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentSagaPostgresRepository(db *sql.DB) *PaymentSagaPostgresRepository {
+	return &PaymentSagaPostgresRepository{db: db}
+}
+
+func (r *PaymentSagaPostgresRepository) Create(ctx context.Context, saga *entity.PaymentSaga) (bool, error) {
+	return insertPaymentSaga(ctx, r.db, saga)
+}
+
+// insertPaymentSaga stores saga through exec, which is the order's transaction
+// when the saga is created with its order
+func insertPaymentSaga(ctx context.Context, exec events.Execer, saga *entity.PaymentSaga) (bool, error) {
+	result, err := exec.ExecContext(ctx,
+		`INSERT INTO payment_sagas (`+paymentSagaColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (order_id) DO NOTHING`,
+		saga.OrderID,
+		saga.State,
+		saga.PaymentToken,
+		saga.Amount.Amount,
+		saga.Amount.Currency,
+		saga.PaymentID,
+		saga.FailureReason,
+		saga.LastError,
+		saga.Attempts,
+		saga.Deadline,
+		saga.NextAttemptAt,
+		saga.CreatedAt,
+		saga.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *PaymentSagaPostgresRepository) FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentSaga, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+paymentSagaColumns+` FROM payment_sagas WHERE order_id = $1`, orderID)
+	return scanPaymentSaga(row)
+}
+
+func (r *PaymentSagaPostgresRepository) Update(ctx context.Context, saga *entity.PaymentSaga, from entity.SagaState, lease time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE payment_sagas
+		SET state = $2, payment_token = $3, payment_id = $4, failure_reason = $5, last_error = $6,
+			attempts = $7, next_attempt_at = $8, updated_at = $9
+		WHERE order_id = $1 AND state = $10 AND next_attempt_at = $11`,
+		saga.OrderID,
+		saga.State,
+		saga.PaymentToken,
+		saga.PaymentID,
+		saga.FailureReason,
+		saga.LastError,
+		saga.Attempts,
+		saga.NextAttemptAt,
+		saga.UpdatedAt,
+		from,
+		lease,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PaymentSagaPostgresRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.PaymentSaga, error) {
+	// SKIP LOCKED lets several order service instances claim disjoint batches
+	rows, err := r.db.QueryContext(ctx,
+		`UPDATE payment_sagas SET next_attempt_at = $2
+		WHERE order_id IN (
+			SELECT order_id FROM payment_sagas
+			WHERE state NOT IN ('COMPLETED', 'COMPENSATED') AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+paymentSagaColumns,
+		now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sagas []*entity.PaymentSaga
+	for rows.Next() {
+		saga, err := scanPaymentSaga(rows)
+		if err != nil {
+			return nil, err
+		}
+		sagas = append(sagas, saga)
+	}
+	return sagas, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPaymentSaga(row rowScanner) (*entity.PaymentSaga, error) {
+	var saga entity.PaymentSaga
+	err := row.Scan(
+		&saga.OrderID,
+		&saga.State,
+		&saga.PaymentToken,
+		&saga.Amount.Amount,
+		&saga.Amount.Currency,
+		&saga.PaymentID,
+		&saga.FailureReason,
+		&saga.LastError,
+		&saga.Attempts,
+		&saga.Deadline,
+		&saga.NextAttemptAt,
+		&saga.CreatedAt,
+		&saga.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &saga, nil
+}
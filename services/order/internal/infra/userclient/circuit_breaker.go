@@ -0,0 +1,65 @@
+package userclient
+
+import (
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops calls to a failing service. After threshold consecutive
+// failures it opens for openDuration and rejects calls; it then lets a single
+// trial call through, closing again if that call succeeds.
+type circuitBreaker struct {
+	threshold    int
+	openDuration time.Duration
+
+	failures  int
+	openUntil time.Time
+	probing   bool
+	mutex     sync.Mutex
+}
+
+func newCircuitBreaker(threshold int, openDuration time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, openDuration: openDuration}
+}
+
+// Allow reports whether a call may proceed; every allowed call must be followed
+// by Record or Release
+func (b *circuitBreaker) Allow(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// Record reports the outcome of an allowed call
+func (b *circuitBreaker) Record(success bool, now time.Time) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = now.Add(b.openDuration)
+	}
+}
+
+// Release ends an allowed call without counting its outcome, letting another
+// trial call through if this one was the probe
+func (b *circuitBreaker) Release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.probing = false
+}
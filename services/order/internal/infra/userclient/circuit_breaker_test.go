@@ -0,0 +1,99 @@
+package userclient
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	openDuration := time.Minute
+
+	openBreaker := func(t *testing.T) *circuitBreaker {
+		t.Helper()
+		breaker := newCircuitBreaker(2, openDuration)
+		for i := 0; i < 2; i++ {
+			if !breaker.Allow(start) {
+				t.Fatalf("Expected call %d to be allowed while closed", i+1)
+			}
+			breaker.Record(false, start)
+		}
+		return breaker
+	}
+
+	t.Run("should stay closed below the threshold", func(t *testing.T) {
+		breaker := newCircuitBreaker(2, openDuration)
+		breaker.Allow(start)
+		breaker.Record(false, start)
+		breaker.Allow(start)
+		breaker.Record(true, start)
+		breaker.Allow(start)
+		breaker.Record(false, start)
+
+		if !breaker.Allow(start) {
+			t.Error("Expected a success to reset the failure count")
+		}
+	})
+
+	t.Run("should reject calls while open", func(t *testing.T) {
+		breaker := openBreaker(t)
+
+		if breaker.Allow(start) || breaker.Allow(start.Add(openDuration-time.Second)) {
+			t.Error("Expected calls to be rejected while open")
+		}
+	})
+
+	t.Run("should let a single trial call through once the open period ends", func(t *testing.T) {
+		breaker := openBreaker(t)
+		halfOpen := start.Add(openDuration)
+
+		if !breaker.Allow(halfOpen) {
+			t.Fatal("Expected a trial call to be allowed")
+		}
+		if breaker.Allow(halfOpen) {
+			t.Error("Expected a second call to wait for the trial")
+		}
+	})
+
+	t.Run("should close after a successful trial call", func(t *testing.T) {
+		breaker := openBreaker(t)
+		halfOpen := start.Add(openDuration)
+
+		breaker.Allow(halfOpen)
+		breaker.Record(true, halfOpen)
+
+		if !breaker.Allow(halfOpen) || !breaker.Allow(halfOpen) {
+			t.Error("Expected the breaker to be closed")
+		}
+	})
+
+	t.Run("should reopen after a failed trial call", func(t *testing.T) {
+		breaker := openBreaker(t)
+		halfOpen := start.Add(openDuration)
+
+		breaker.Allow(halfOpen)
+		breaker.Record(false, halfOpen)
+
+		if breaker.Allow(halfOpen.Add(openDuration - time.Second)) {
+			t.Error("Expected the breaker to be open again")
+		}
+		if !breaker.Allow(halfOpen.Add(openDuration)) {
+			t.Error("Expected another trial call after the new open period")
+		}
+	})
+
+	t.Run("should allow another trial call after a released one", func(t *testing.T) {
+		breaker := openBreaker(t)
+		halfOpen := start.Add(openDuration)
+
+		breaker.Allow(halfOpen)
+		breaker.Release()
+
+		if !breaker.Allow(halfOpen) {
+			t.Error("Expected a released trial to let the next one through")
+		}
+		if breaker.Allow(halfOpen) {
+			t.Error("Expected the breaker to still be half-open")
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package userclient
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/order/internal/user"
+)
+
+// Config tunes the user service client
+type Config struct {
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// MaxRetries is how many times a failed attempt is repeated, waiting
+	// RetryBackoff before the first retry and twice as long before each next one
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// FailureThreshold consecutive failed lookups open the circuit for
+	// OpenDuration, during which lookups fail without calling the service
+	FailureThreshold int
+	OpenDuration     time.Duration
+	// Email and Password are the order service's own account in the user
+	// service. When set, lookups use its access token instead of forwarding
+	// the caller's, which is missing when authentication is disabled and may
+	// not be allowed to see the user.
+	Email    string
+	Password string
+}
+
+// Client looks users up through the user service's HTTP API
+type Client struct {
+	baseURL     string
+	httpClient  *http.Client
+	config      Config
+	breaker     *circuitBreaker
+	credentials *serviceCredentials
+}
+
+func NewClient(baseURL string, httpClient *http.Client, config Config) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+
+	client := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: httpClient,
+		config:     config,
+		breaker:    newCircuitBreaker(config.FailureThreshold, config.OpenDuration),
+	}
+	if config.Email != "" {
+		client.credentials = &serviceCredentials{
+			loginURL:   client.baseURL + "/auth/login",
+			email:      config.Email,
+			password:   config.Password,
+			httpClient: httpClient,
+		}
+	}
+	return client
+}
+
+func (c *Client) FindByID(ctx context.Context, id string) (*user.User, error) {
+	if !c.breaker.Allow(time.Now()) {
+		return nil, fmt.Errorf("%w: circuit open", user.ErrUnavailable)
+	}
+
+	found, err := c.findWithRetries(ctx, id)
+	if err != nil && (ctx.Err() != nil || stdErrors.Is(err, user.ErrUnauthorized)) {
+		// The caller gave up or the credentials were refused, neither of which
+		// says anything about the service's health
+		c.breaker.Release()
+		return found, err
+	}
+	// A missing user is a healthy answer
+	c.breaker.Record(err == nil || stdErrors.Is(err, user.ErrNotFound), time.Now())
+	return found, err
+}
+
+func (c *Client) findWithRetries(ctx context.Context, id string) (*user.User, error) {
+	var lastErr error
+	delay := c.config.RetryBackoff
+	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, fmt.Errorf("%w: %v", user.ErrUnavailable, ctx.Err())
+			case <-timer.C:
+			}
+			delay *= 2
+		}
+
+		found, retryable, err := c.find(ctx, id)
+		if err == nil || !retryable {
+			return found, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("%w: %v", user.ErrUnavailable, lastErr)
+}
+
+// find makes a single attempt, reporting whether a failure is worth retrying
+func (c *Client) find(ctx context.Context, id string) (*user.User, bool, error) {
+	if c.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/users/"+url.PathEscape(id), nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", user.ErrUnavailable, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if requestID := pkgMiddleware.GetRequestIDFromContext(ctx); requestID != "" {
+		req.Header.Set(pkgMiddleware.RequestIDHeader, requestID)
+	}
+	// The user service only answers authenticated callers, so act as the
+	// order service's own account or, without one, as the caller
+	token := pkgMiddleware.BearerTokenFromContext(ctx)
+	if c.credentials != nil {
+		var retryable bool
+		if token, retryable, err = c.credentials.Token(ctx); err != nil {
+			return nil, retryable, err
+		}
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		var found user.User
+		if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+			return nil, false, fmt.Errorf("%w: decoding response: %v", user.ErrUnavailable, err)
+		}
+		return &found, false, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, false, user.ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		if c.credentials != nil && resp.StatusCode == http.StatusUnauthorized {
+			c.credentials.Invalidate(token)
+		}
+		return nil, false, fmt.Errorf("%w: user service returned %s", user.ErrUnauthorized, resp.Status)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		io.Copy(io.Discard, resp.Body)
+		return nil, true, fmt.Errorf("user service returned %s", resp.Status)
+	default:
+		return nil, false, fmt.Errorf("%w: user service returned %s", user.ErrUnavailable, resp.Status)
+	}
+}
@@ -0,0 +1,216 @@
+package userclient
+
+import (
+	"context"
+	"encoding/json"
+	stdErrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/user"
+)
+
+func newTestClient(url string, config Config) *Client {
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = time.Millisecond
+	}
+	if config.OpenDuration == 0 {
+		config.OpenDuration = time.Minute
+	}
+	return NewClient(url, nil, config)
+}
+
+// statusServer answers with the given statuses in turn, then with a user
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call := int(atomic.AddInt32(&calls, 1))
+		if call <= len(statuses) {
+			w.WriteHeader(statuses[call-1])
+			return
+		}
+		if r.URL.Path != "/users/user-1" {
+			t.Errorf("Expected path /users/user-1, got %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(user.User{ID: "user-1", Name: "Ada", Email: "ada@example.com"})
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestClient_FindByID(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should retry server errors until the user is found", func(t *testing.T) {
+		server, calls := statusServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+		found, err := newTestClient(server.URL, Config{MaxRetries: 2}).FindByID(ctx, "user-1")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if found.ID != "user-1" || found.Email != "ada@example.com" {
+			t.Errorf("Expected user-1, got %+v", found)
+		}
+		if *calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", *calls)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		statuses      []int
+		maxRetries    int
+		expectedErr   error
+		expectedCalls int32
+	}{
+		{"missing user without retrying", []int{http.StatusNotFound}, 2, user.ErrNotFound, 1},
+		{"client error without retrying", []int{http.StatusBadRequest}, 2, user.ErrUnavailable, 1},
+		{"server error once retries run out", []int{500, 502, 503}, 2, user.ErrUnavailable, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run("should report a "+tt.name, func(t *testing.T) {
+			server, calls := statusServer(t, tt.statuses...)
+
+			_, err := newTestClient(server.URL, Config{MaxRetries: tt.maxRetries}).FindByID(ctx, "user-1")
+			if !stdErrors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if *calls != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, *calls)
+			}
+		})
+	}
+
+	t.Run("should not count a missing user as a breaker failure", func(t *testing.T) {
+		server, calls := statusServer(t, http.StatusNotFound, http.StatusNotFound)
+		client := newTestClient(server.URL, Config{FailureThreshold: 1})
+
+		client.FindByID(ctx, "user-1")
+		if _, err := client.FindByID(ctx, "user-1"); !stdErrors.Is(err, user.ErrNotFound) {
+			t.Errorf("Expected %v, got %v", user.ErrNotFound, err)
+		}
+		if *calls != 2 {
+			t.Errorf("Expected 2 calls, got %d", *calls)
+		}
+	})
+
+	t.Run("should open the circuit after consecutive failures", func(t *testing.T) {
+		server, calls := statusServer(t, 500, 500, 500)
+		client := newTestClient(server.URL, Config{FailureThreshold: 2})
+
+		client.FindByID(ctx, "user-1")
+		client.FindByID(ctx, "user-1")
+		_, err := client.FindByID(ctx, "user-1")
+		if !stdErrors.Is(err, user.ErrUnavailable) {
+			t.Errorf("Expected %v, got %v", user.ErrUnavailable, err)
+		}
+		if *calls != 2 {
+			t.Errorf("Expected the open circuit to skip the service, got %d calls", *calls)
+		}
+	})
+
+	t.Run("should close the circuit after a successful trial call", func(t *testing.T) {
+		server, calls := statusServer(t, 500)
+		client := newTestClient(server.URL, Config{FailureThreshold: 1, OpenDuration: time.Millisecond})
+
+		client.FindByID(ctx, "user-1")
+		time.Sleep(5 * time.Millisecond)
+		for i := 0; i < 2; i++ {
+			if _, err := client.FindByID(ctx, "user-1"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if *calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", *calls)
+		}
+	})
+
+	callerTests := []struct {
+		name    string
+		withCtx func(ctx context.Context) (context.Context, context.CancelFunc)
+	}{
+		{"cancelled", func(ctx context.Context) (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(ctx)
+			time.AfterFunc(10*time.Millisecond, cancel)
+			return ctx, cancel
+		}},
+		{"timed out", func(ctx context.Context) (context.Context, context.CancelFunc) {
+			return context.WithTimeout(ctx, 10*time.Millisecond)
+		}},
+	}
+
+	for _, tt := range callerTests {
+		t.Run("should not count a "+tt.name+" caller as a breaker failure", func(t *testing.T) {
+			release := make(chan struct{})
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					<-release
+					return
+				}
+				json.NewEncoder(w).Encode(user.User{ID: "user-1"})
+			}))
+			defer server.Close()
+			defer close(release)
+			client := newTestClient(server.URL, Config{FailureThreshold: 1})
+
+			callCtx, cancel := tt.withCtx(ctx)
+			defer cancel()
+			if _, err := client.FindByID(callCtx, "user-1"); !stdErrors.Is(err, user.ErrUnavailable) {
+				t.Errorf("Expected %v, got %v", user.ErrUnavailable, err)
+			}
+
+			if _, err := client.FindByID(ctx, "user-1"); err != nil {
+				t.Errorf("Expected the circuit to stay closed, got %v", err)
+			}
+		})
+	}
+
+	t.Run("should report refused credentials without opening the circuit", func(t *testing.T) {
+		server, calls := statusServer(t, http.StatusUnauthorized, http.StatusForbidden)
+		client := newTestClient(server.URL, Config{FailureThreshold: 1, MaxRetries: 2})
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.FindByID(ctx, "user-1"); !stdErrors.Is(err, user.ErrUnauthorized) {
+				t.Errorf("Expected %v, got %v", user.ErrUnauthorized, err)
+			}
+		}
+		if _, err := client.FindByID(ctx, "user-1"); err != nil {
+			t.Errorf("Expected the circuit to stay closed, got %v", err)
+		}
+		if *calls != 3 {
+			t.Errorf("Expected 3 calls, got %d", *calls)
+		}
+	})
+
+	t.Run("should look users up with the service account's token", func(t *testing.T) {
+		var logins int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/auth/login" {
+				atomic.AddInt32(&logins, 1)
+				json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "service-token", "expires_in": 900})
+				return
+			}
+			if auth := r.Header.Get("Authorization"); auth != "Bearer service-token" {
+				t.Errorf("Expected the service account's token, got %q", auth)
+			}
+			json.NewEncoder(w).Encode(user.User{ID: "user-1"})
+		}))
+		defer server.Close()
+		client := newTestClient(server.URL, Config{Email: "orders@example.com", Password: "secret"})
+
+		for i := 0; i < 2; i++ {
+			if _, err := client.FindByID(ctx, "user-1"); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		}
+		if atomic.LoadInt32(&logins) != 1 {
+			t.Errorf("Expected the token to be reused, got %d logins", atomic.LoadInt32(&logins))
+		}
+	})
+}
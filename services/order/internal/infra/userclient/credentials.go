@@ -0,0 +1,88 @@
+package userclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/user"
+)
+
+// tokenRenewalMargin is how long before its expiry an access token is replaced
+const tokenRenewalMargin = 30 * time.Second
+
+// serviceCredentials logs in to the user service with the order service's own
+// account and reuses the access token until shortly before it expires
+type serviceCredentials struct {
+	loginURL   string
+	email      string
+	password   string
+	httpClient *http.Client
+
+	mutex     sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// Token returns a current access token, logging in when there is none, and
+// reports whether a failure is worth retrying like Client.find
+func (c *serviceCredentials) Token(ctx context.Context) (string, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token != "" && time.Now().Before(c.expiresAt) {
+		return c.token, false, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"email": c.email, "password": c.password})
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %v", user.ErrUnavailable, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.loginURL, bytes.NewReader(body))
+	if err != nil {
+		return "", false, fmt.Errorf("%w: %v", user.ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", true, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		var tokens struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil || tokens.AccessToken == "" {
+			return "", false, fmt.Errorf("%w: decoding login response: %v", user.ErrUnavailable, err)
+		}
+		c.token = tokens.AccessToken
+		c.expiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn)*time.Second - tokenRenewalMargin)
+		return c.token, false, nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", false, fmt.Errorf("%w: login returned %s", user.ErrUnauthorized, resp.Status)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		io.Copy(io.Discard, resp.Body)
+		return "", true, fmt.Errorf("user service login returned %s", resp.Status)
+	default:
+		return "", false, fmt.Errorf("%w: user service login returned %s", user.ErrUnavailable, resp.Status)
+	}
+}
+
+// Invalidate drops token once the user service refused it, so the next lookup logs in again
+func (c *serviceCredentials) Invalidate(token string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.token == token {
+		c.token = ""
+	}
+}
@@ -0,0 +1,44 @@
+package payment
+
+import (
+	"context"
+	"errors"
+
+	"github.com/robrt95x/godops/pkg/money"
+)
+
+// Payment statuses reported by the payment service
+const (
+	StatusPending    = "PENDING"
+	StatusAuthorized = "AUTHORIZED"
+	StatusCaptured   = "CAPTURED"
+	StatusVoided     = "VOIDED"
+	StatusDeclined   = "DECLINED"
+)
+
+var (
+	// ErrDeclined means the card was declined; retrying will not help
+	ErrDeclined = errors.New("payment declined")
+	// ErrRejected means the payment service refused the request as invalid, e.g.
+	// an unknown payment or a transition its lifecycle does not allow
+	ErrRejected = errors.New("payment request rejected")
+)
+
+// Service is the port to the payment service. Errors other than ErrDeclined
+// and ErrRejected are transient and the call may be retried.
+type Service interface {
+	CreatePayment(ctx context.Context, orderID string, amount money.Money) (*Payment, error)
+	GetPayment(ctx context.Context, paymentID string) (*Payment, error)
+	Authorize(ctx context.Context, paymentID, cardToken string) (*Payment, error)
+	Capture(ctx context.Context, paymentID string) (*Payment, error)
+	Void(ctx context.Context, paymentID string) (*Payment, error)
+}
+
+// Payment is the payment service's view of a payment intent
+type Payment struct {
+	ID            string      `json:"id"`
+	OrderID       string      `json:"order_id"`
+	Amount        money.Money `json:"amount"`
+	Status        string      `json:"status"`
+	DeclineReason string      `json:"decline_reason,omitempty"`
+}
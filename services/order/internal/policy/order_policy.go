@@ -0,0 +1,59 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+// Action is something a principal does to an order
+type Action string
+
+const (
+	ActionCreateOrder  Action = "create_order"
+	ActionReadOrder    Action = "read_order"
+	ActionListOrders   Action = "list_orders"
+	ActionCancelOrder  Action = "cancel_order"
+	ActionChangeStatus Action = "change_status"
+	ActionManageCoupon Action = "manage_coupon"
+)
+
+// AuthorizeOrder decides whether principal may perform action on orders of
+// ownerID, the order's user; ownerID is unused for actions on no particular user.
+//
+//	action         owner  support  admin
+//	create_order   yes    no       yes
+//	read_order     yes    yes      yes
+//	list_orders    yes    yes      yes
+//	cancel_order   yes    no       yes
+//	change_status  no     no       yes
+//	manage_coupon  -      no       yes
+func AuthorizeOrder(principal Principal, action Action, ownerID string) error {
+	isOwner := ownerID != "" && principal.Subject == ownerID
+	isAdmin := principal.HasRole(RoleAdmin)
+
+	var allowed bool
+	switch action {
+	case ActionCreateOrder, ActionCancelOrder:
+		allowed = isOwner || isAdmin
+	case ActionReadOrder, ActionListOrders:
+		allowed = isOwner || principal.IsStaff()
+	case ActionChangeStatus, ActionManageCoupon:
+		allowed = isAdmin
+	}
+
+	if !allowed {
+		return errors.ErrAuthForbidden
+	}
+	return nil
+}
+
+// Authorize applies AuthorizeOrder to the principal in ctx; a context without
+// one is rejected, so use cases fail closed when called without a caller
+func Authorize(ctx context.Context, action Action, ownerID string) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok {
+		return errors.ErrAuthUnauthenticated
+	}
+	return AuthorizeOrder(principal, action, ownerID)
+}
@@ -0,0 +1,62 @@
+package policy
+
+import "context"
+
+// Role is a coarse permission carried in the caller's access token
+type Role string
+
+const (
+	// RoleCustomer places orders and manages only their own
+	RoleCustomer Role = "customer"
+	// RoleSupport reads every order to help customers
+	RoleSupport Role = "support"
+	// RoleAdmin manages every order and coupon
+	RoleAdmin Role = "admin"
+)
+
+// Principal is the caller a use case acts for. Ownership rules apply to any
+// authenticated subject; roles only grant access beyond the subject's own orders.
+type Principal struct {
+	Subject string
+	Roles   []Role
+}
+
+// System is the principal of the service's own background work, such as the payment saga
+var System = Principal{Subject: "system", Roles: []Role{RoleAdmin}}
+
+// NewPrincipal builds a principal from the subject and roles of an access token
+func NewPrincipal(subject string, roles []string) Principal {
+	principal := Principal{Subject: subject}
+	for _, role := range roles {
+		principal.Roles = append(principal.Roles, Role(role))
+	}
+	return principal
+}
+
+// HasRole reports whether the principal has role
+func (p Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStaff reports whether the principal may see orders of other users
+func (p Principal) IsStaff() bool {
+	return p.HasRole(RoleSupport) || p.HasRole(RoleAdmin)
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a context carrying principal for the use cases' policy checks
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored by WithPrincipal
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok && principal.Subject != ""
+}
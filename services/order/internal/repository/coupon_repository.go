@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+type CouponRepository interface {
+	// Save stores a new coupon; a code that is already taken fails with
+	// errors.ErrCouponAlreadyExists, even when another request stored it concurrently
+	Save(ctx context.Context, coupon *entity.Coupon) error
+	FindByCode(ctx context.Context, code string) (*entity.Coupon, error)
+	// RecordRedemption stores redemption unless the user already redeemed the
+	// coupon maxUses times (0 means unlimited); it reports whether it was stored.
+	RecordRedemption(ctx context.Context, redemption *entity.CouponRedemption, maxUses int) (bool, error)
+	DeleteRedemption(ctx context.Context, couponCode, orderID string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores record unless an unexpired record of the same subject
+	// already holds its key, in which case that existing record is returned instead.
+	// An in-progress record expires at the end of its lease, so a reservation
+	// whose request never completed is taken over.
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error)
+	// Complete stores the response of an in-progress record and keeps it until
+	// expiresAt; a response already stored is never overwritten
+	Complete(ctx context.Context, subject, key string, statusCode int, responseBody []byte, expiresAt time.Time) error
+	Delete(ctx context.Context, subject, key string) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
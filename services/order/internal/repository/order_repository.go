@@ -1,8 +1,41 @@
 package repository
 
-import "github.com/robrt95x/godops/services/order/internal/entity"
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
 
 type OrderRepository interface {
-	Save(order *entity.Order) error
-	FindByID(id string) (*entity.Order, error)
+	// Save stores a new order and appends envelopes to the outbox atomically
+	// with it, together with saga when the order is to be charged (nil otherwise)
+	Save(ctx context.Context, order *entity.Order, saga *entity.PaymentSaga, envelopes ...events.Envelope) error
+	FindByID(ctx context.Context, id string) (*entity.Order, error)
+	// Update persists status, pricing and address changes and appends envelopes to
+	// the outbox atomically with them; items are immutable once saved.
+	// It only applies while the stored order is still in status from and returns
+	// sql.ErrNoRows otherwise, so concurrent transitions cannot overwrite each other
+	Update(ctx context.Context, order *entity.Order, from entity.OrderStatus, envelopes ...events.Envelope) error
+	List(ctx context.Context, filter OrderFilter) ([]*entity.Order, error)
+}
+
+// OrderFilter selects orders for listing. Zero values disable a filter;
+// CreatedFrom is inclusive and CreatedTo exclusive.
+// Results are ordered by CreatedAt then ID, newest first.
+type OrderFilter struct {
+	UserID      string
+	ProductID   string // orders with at least one item for this product
+	Status      entity.OrderStatus
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	After       *OrderCursor
+	Limit       int
+}
+
+// OrderCursor is the position of the last order of a previous page
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        string
 }
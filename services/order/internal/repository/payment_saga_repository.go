@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+type PaymentSagaRepository interface {
+	// Create stores saga unless one already exists for its order; it reports whether it was stored
+	Create(ctx context.Context, saga *entity.PaymentSaga) (bool, error)
+	FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentSaga, error)
+	// Update stores saga only while its stored state is still from and its next
+	// attempt is still lease, the time its runner claimed it until; otherwise,
+	// as when the lease ran out and another runner claimed the saga, it returns
+	// sql.ErrNoRows
+	Update(ctx context.Context, saga *entity.PaymentSaga, from entity.SagaState, lease time.Time) error
+	// ClaimDue returns up to limit unfinished sagas whose next attempt is due at
+	// now, oldest first, and defers their next attempt to leaseUntil so that no
+	// other runner claims them while they are being advanced
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.PaymentSaga, error)
+}
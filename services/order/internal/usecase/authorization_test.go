@@ -0,0 +1,134 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+var (
+	owner    = policy.Principal{Subject: "user-1", Roles: []policy.Role{policy.RoleCustomer}}
+	stranger = policy.Principal{Subject: "user-2", Roles: []policy.Role{policy.RoleCustomer}}
+	support  = policy.Principal{Subject: "support-1", Roles: []policy.Role{policy.RoleSupport}}
+	admin    = policy.Principal{Subject: "admin-1", Roles: []policy.Role{policy.RoleAdmin}}
+)
+
+// adminContext carries a principal allowed every action, for tests about something else
+func adminContext() context.Context {
+	return policy.WithPrincipal(context.Background(), admin)
+}
+
+func TestOrderAuthorization(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	forbidden := errors.ErrAuthForbidden
+
+	// Every case runs against a fresh pending order of user-1
+	tests := []struct {
+		name      string
+		principal *policy.Principal
+		get       error
+		cancel    error
+		confirm   error
+		list      error
+		create    error
+	}{
+		{"owner", &owner, nil, nil, forbidden, nil, nil},
+		{"other customer", &stranger, forbidden, forbidden, forbidden, forbidden, forbidden},
+		{"support", &support, nil, forbidden, forbidden, nil, forbidden},
+		{"admin", &admin, nil, nil, nil, nil, nil},
+		{"no principal", nil, errors.ErrAuthUnauthenticated, errors.ErrAuthUnauthenticated, errors.ErrAuthUnauthenticated, errors.ErrAuthUnauthenticated, errors.ErrAuthUnauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.principal != nil {
+				ctx = policy.WithPrincipal(ctx, *tt.principal)
+			}
+
+			newRepo := func() *memory.OrderMemoryRepository {
+				repo := memory.NewOrderMemoryRepository()
+				now := time.Now()
+				repo.Save(context.Background(), &entity.Order{
+					ID:        "order-1",
+					UserID:    "user-1",
+					Items:     []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}},
+					Status:    entity.Pending,
+					Total:     money.Money{Amount: 1000, Currency: "USD"},
+					CreatedAt: now,
+					UpdatedAt: now,
+				}, nil)
+				return repo
+			}
+
+			if _, err := usecase.NewGetOrderByIDCase(newRepo(), testLogger).Execute(ctx, "order-1"); err != tt.get {
+				t.Errorf("get: expected %v, got %v", tt.get, err)
+			}
+			if _, err := usecase.NewUpdateOrderStatusCase(newRepo(), testLogger).Execute(ctx, "order-1", entity.Cancelled); err != tt.cancel {
+				t.Errorf("cancel: expected %v, got %v", tt.cancel, err)
+			}
+			if _, err := usecase.NewUpdateOrderStatusCase(newRepo(), testLogger).Execute(ctx, "order-1", entity.Confirmed); err != tt.confirm {
+				t.Errorf("confirm: expected %v, got %v", tt.confirm, err)
+			}
+			if _, err := usecase.NewListOrdersCase(newRepo(), testLogger).Execute(ctx, usecase.ListOrdersInput{UserID: "user-1"}); err != tt.list {
+				t.Errorf("list: expected %v, got %v", tt.list, err)
+			}
+			createUC := usecase.NewCreateOrderCase(newRepo(), memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+			items := []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}}
+			if _, err := createUC.Execute(ctx, "user-1", items, "", testAddress, ""); err != tt.create {
+				t.Errorf("create: expected %v, got %v", tt.create, err)
+			}
+		})
+	}
+
+	t.Run("should default a customer's listing to their own orders", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		now := time.Now()
+		for _, userID := range []string{"user-1", "user-2"} {
+			repo.Save(context.Background(), &entity.Order{
+				ID:        "order-" + userID,
+				UserID:    userID,
+				Items:     []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}},
+				Status:    entity.Pending,
+				Total:     money.Money{Amount: 1000, Currency: "USD"},
+				CreatedAt: now,
+				UpdatedAt: now,
+			}, nil)
+		}
+		uc := usecase.NewListOrdersCase(repo, testLogger)
+
+		page, err := uc.Execute(policy.WithPrincipal(context.Background(), owner), usecase.ListOrdersInput{ProductID: "product-1"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Orders) != 1 || page.Orders[0].UserID != "user-1" {
+			t.Errorf("Expected only the owner's order, got %v", page.Orders)
+		}
+
+		page, err = uc.Execute(policy.WithPrincipal(context.Background(), support), usecase.ListOrdersInput{ProductID: "product-1"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Orders) != 2 {
+			t.Errorf("Expected support to see both orders, got %d", len(page.Orders))
+		}
+	})
+
+	t.Run("should only let admins create coupons", func(t *testing.T) {
+		for _, principal := range []policy.Principal{owner, support} {
+			coupon := &entity.Coupon{Code: "SAVE10", Type: entity.PercentageDiscount, PercentOff: 10}
+			_, err := usecase.NewCreateCouponCase(memory.NewCouponMemoryRepository(), testLogger).Execute(policy.WithPrincipal(context.Background(), principal), coupon)
+			if err != forbidden {
+				t.Errorf("Expected %v for %s, got %v", forbidden, principal.Subject, err)
+			}
+		}
+	})
+}
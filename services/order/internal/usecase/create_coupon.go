@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type CreateCouponCase struct {
+	repository repository.CouponRepository
+	logger     *logrus.Logger
+}
+
+func NewCreateCouponCase(repository repository.CouponRepository, logger *logrus.Logger) *CreateCouponCase {
+	return &CreateCouponCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *CreateCouponCase) Execute(ctx context.Context, coupon *entity.Coupon) (*entity.Coupon, error) {
+	coupon.Code = entity.NormalizeCouponCode(coupon.Code)
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":    "CreateCoupon",
+		"coupon_code": coupon.Code,
+		"type":        coupon.Type,
+	})
+
+	logEntry.Debug("Starting create coupon use case")
+
+	if err := policy.Authorize(ctx, policy.ActionManageCoupon, ""); err != nil {
+		logEntry.WithError(err).Warning("Create coupon denied")
+		return nil, err
+	}
+
+	if err := coupon.Validate(); err != nil {
+		logEntry.Warning("Create coupon failed: invalid definition")
+		return nil, err
+	}
+
+	_, err := uc.repository.FindByCode(ctx, coupon.Code)
+	if err == nil {
+		logEntry.Warning("Create coupon failed: code already exists")
+		return nil, errors.ErrCouponAlreadyExists
+	}
+	if err != sql.ErrNoRows {
+		logEntry.WithError(err).Error("Failed to check for existing coupon")
+		return nil, repositoryError(ctx, err)
+	}
+
+	coupon.CreatedAt = time.Now()
+	if err := uc.repository.Save(ctx, coupon); err != nil {
+		if err == errors.ErrCouponAlreadyExists {
+			// Another request created the code since it was checked
+			logEntry.Warning("Create coupon failed: code already exists")
+			return nil, errors.ErrCouponAlreadyExists
+		}
+		logEntry.WithError(err).Error("Failed to save coupon to repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	logEntry.Info("Coupon created successfully")
+	return coupon, nil
+}
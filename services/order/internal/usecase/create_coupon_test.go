@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"testing"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+// racingCouponRepository misses the existing coupon on lookup, as a request
+// that races another creation of the same code does
+type racingCouponRepository struct {
+	*memory.CouponMemoryRepository
+}
+
+func (r *racingCouponRepository) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	return nil, sql.ErrNoRows
+}
+
+func TestCreateCouponCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	newCoupon := func() *entity.Coupon {
+		return &entity.Coupon{Code: "save10", Type: entity.PercentageDiscount, PercentOff: 10}
+	}
+
+	t.Run("should reject a code that is taken", func(t *testing.T) {
+		uc := usecase.NewCreateCouponCase(memory.NewCouponMemoryRepository(), testLogger)
+		if _, err := uc.Execute(adminContext(), newCoupon()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, err := uc.Execute(adminContext(), newCoupon()); err != errors.ErrCouponAlreadyExists {
+			t.Errorf("Expected ErrCouponAlreadyExists, got %v", err)
+		}
+	})
+
+	t.Run("should let only one of concurrent creations of a code succeed", func(t *testing.T) {
+		repo := memory.NewCouponMemoryRepository()
+		uc := usecase.NewCreateCouponCase(&racingCouponRepository{repo}, testLogger)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				coupon := newCoupon()
+				coupon.PercentOff = 10 + i
+				_, errs[i] = uc.Execute(adminContext(), coupon)
+			}(i)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			switch err {
+			case nil:
+				created++
+			case errors.ErrCouponAlreadyExists:
+			default:
+				t.Errorf("Expected ErrCouponAlreadyExists, got %v", err)
+			}
+		}
+		if created != 1 {
+			t.Fatalf("Expected exactly one coupon to be created, got %d", created)
+		}
+
+		// The winner's definition is kept rather than overwritten by a loser
+		stored, _ := repo.FindByCode(context.Background(), "SAVE10")
+		winner := 0
+		for i, err := range errs {
+			if err == nil {
+				winner = i
+			}
+		}
+		if stored == nil || stored.PercentOff != 10+winner {
+			t.Errorf("Expected the created coupon to be stored, got %+v", stored)
+		}
+	})
+}
@@ -1,93 +1,259 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
 	"time"
 
 	"github.com/google/uuid"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/pkg/money"
 	"github.com/robrt95x/godops/services/order/internal/entity"
 	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
 	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/robrt95x/godops/services/order/internal/user"
 	"github.com/sirupsen/logrus"
 )
 
 type CreateOrderCase struct {
-	repository repository.OrderRepository
-	logger     *logrus.Logger
+	repository       repository.OrderRepository
+	couponRepository repository.CouponRepository
+	users            user.UserDirectory
+	sagaTimeout      time.Duration
+	logger           *logrus.Logger
 }
 
-func NewCreateOrderCase(repository repository.OrderRepository, logger *logrus.Logger) *CreateOrderCase {
+func NewCreateOrderCase(repository repository.OrderRepository, couponRepository repository.CouponRepository, users user.UserDirectory, logger *logrus.Logger) *CreateOrderCase {
 	return &CreateOrderCase{
-		repository: repository,
-		logger:     logger,
+		repository:       repository,
+		couponRepository: couponRepository,
+		users:            users,
+		logger:           logger,
 	}
 }
 
-func (uc *CreateOrderCase) Execute(userID string, items []entity.OrderItem) (*entity.Order, error) {
+// WithPaymentSagas makes orders created with a payment token start a payment
+// saga, which has timeout to capture the payment. Without it the token is ignored.
+func (uc *CreateOrderCase) WithPaymentSagas(timeout time.Duration) *CreateOrderCase {
+	uc.sagaTimeout = timeout
+	return uc
+}
+
+// Execute creates a pending order. With payment sagas enabled, a non-empty
+// paymentToken is stored on a saga saved in the same transaction as the order,
+// so the order is charged if and only if it exists; the token never leaves the
+// service in events.
+func (uc *CreateOrderCase) Execute(ctx context.Context, userID string, items []entity.OrderItem, couponCode string, shippingAddress entity.Address, paymentToken string) (*entity.Order, error) {
+	couponCode = entity.NormalizeCouponCode(couponCode)
+	shippingAddress = shippingAddress.Normalize()
 	logEntry := uc.logger.WithFields(logrus.Fields{
 		"use_case":    "CreateOrder",
 		"user_id":     userID,
 		"items_count": len(items),
+		"coupon_code": couponCode,
 	})
 	
 	logEntry.Debug("Starting create order use case")
 	
-	// Validate input
-	if userID == "" {
-		logEntry.Warning("Create order failed: missing user ID")
-		return nil, errors.ErrValidationMissingUserID
+	// Validate input, reporting every invalid field at once
+	subtotal, err := validateOrderRequest(userID, items, shippingAddress)
+	if err != nil {
+		logEntry.WithFields(logrus.Fields{
+			"country":     shippingAddress.Country,
+			"postal_code": shippingAddress.PostalCode,
+		}).WithError(err).Warning("Create order failed: invalid request")
+		return nil, err
 	}
 	
-	if len(items) == 0 {
-		logEntry.Warning("Create order failed: no items provided")
-		return nil, errors.ErrValidationEmptyItems
+	if err := policy.Authorize(ctx, policy.ActionCreateOrder, userID); err != nil {
+		logEntry.WithError(err).Warning("Create order denied")
+		return nil, err
 	}
-	
-	// Validate items
-	var total float64
-	for i, item := range items {
-		if item.ProductID == "" {
-			logEntry.WithField("item_index", i).Warning("Create order failed: missing product ID")
-			return nil, errors.ErrValidationMissingProductID
-		}
-		if item.Quantity <= 0 {
-			logEntry.WithFields(logrus.Fields{
-				"item_index": i,
-				"quantity":   item.Quantity,
-			}).Warning("Create order failed: invalid quantity")
-			return nil, errors.ErrValidationInvalidQuantity
-		}
-		if item.Price <= 0 {
-			logEntry.WithFields(logrus.Fields{
-				"item_index": i,
-				"price":      item.Price,
-			}).Warning("Create order failed: invalid price")
-			return nil, errors.ErrValidationInvalidPrice
-		}
-		total += item.Price * float64(item.Quantity)
+
+	// Only ask the user service once the request is otherwise valid
+	if err := uc.checkUser(ctx, logEntry, userID); err != nil {
+		return nil, err
 	}
 
+	now := time.Now()
 	orderID := uuid.NewString()
+
+	discount := money.Zero(subtotal.Currency)
+	var coupon *entity.Coupon
+	if couponCode != "" {
+		var err error
+		coupon, discount, err = uc.priceCoupon(ctx, logEntry, couponCode, subtotal, now)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total, _ := subtotal.Subtract(discount)
 	order := &entity.Order{
-		ID:        orderID,
-		UserID:    userID,
-		Items:     items,
-		Status:    entity.Pending,
-		Total:     total,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:         orderID,
+		UserID:     userID,
+		Items:      items,
+		Status:     entity.Pending,
+		CouponCode: couponCode,
+		Subtotal:   subtotal,
+		Discount:   discount,
+		Total:      total,
+		ShippingAddress: shippingAddress,
+		CreatedAt:  now,
+		UpdatedAt:  now,
 	}
 
 	logEntry = logEntry.WithFields(logrus.Fields{
 		"order_id": orderID,
-		"total":    total,
+		"subtotal": subtotal.String(),
+		"discount": discount.String(),
+		"total":    total.String(),
 	})
 
-	err := uc.repository.Save(order)
+	createdEvent, err := orderCreatedEvent(order)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to build order created event")
+		return nil, errors.ErrSystemInternal
+	}
+
+	var saga *entity.PaymentSaga
+	switch {
+	case paymentToken == "":
+	case uc.sagaTimeout <= 0:
+		logEntry.Warning("Payment sagas are disabled; ignoring the payment token")
+	default:
+		saga = entity.NewPaymentSaga(orderID, paymentToken, total, now.Add(uc.sagaTimeout), now)
+	}
+
+	// Claim the coupon before saving so concurrent orders cannot exceed the per-user limit
+	if coupon != nil {
+		redeemed, err := uc.couponRepository.RecordRedemption(ctx, &entity.CouponRedemption{
+			CouponCode: coupon.Code,
+			UserID:     userID,
+			OrderID:    orderID,
+			RedeemedAt: now,
+		}, coupon.MaxUsesPerUser)
+		if err != nil {
+			logEntry.WithError(err).Error("Failed to record coupon redemption")
+			return nil, repositoryError(ctx, err)
+		}
+		if !redeemed {
+			logEntry.Warning("Create order failed: coupon usage limit reached")
+			return nil, errors.ErrCouponUsageLimitReached
+		}
+	}
+
+	// The saga and event are written in the same transaction as the order, so they exist if and only if the order does
+	err = uc.repository.Save(ctx, order, saga, createdEvent)
 	if err != nil {
 		logEntry.WithError(err).Error("Failed to save order to repository")
-		return nil, errors.ErrDatabaseQuery
+		if coupon != nil {
+			// Release the claim even when the request deadline is what failed the save
+			if releaseErr := uc.couponRepository.DeleteRedemption(context.WithoutCancel(ctx), coupon.Code, orderID); releaseErr != nil {
+				logEntry.WithError(releaseErr).Error("Failed to release coupon redemption")
+			}
+		}
+		return nil, repositoryError(ctx, err)
 	}
 	
 	logEntry.Info("Order created successfully")
 	return order, nil
 }
+
+// validateOrderRequest checks the user, items and shipping address of a new
+// order and returns the items' subtotal. All invalid fields are returned
+// together as a *ValidationErrors with JSON pointers into the request body.
+func validateOrderRequest(userID string, items []entity.OrderItem, shippingAddress entity.Address) (money.Money, error) {
+	var violations pkgErrors.ValidationErrors
+	if userID == "" {
+		violations.Add("/user_id", errors.ErrValidationMissingUserID)
+	}
+	if len(items) == 0 {
+		violations.Add("/items", errors.ErrValidationEmptyItems)
+	}
+
+	// Items must share the currency of the first item with a valid one
+	currency := ""
+	for i, item := range items {
+		if item.ProductID == "" {
+			violations.Add(pkgErrors.Pointer("items", i, "product_id"), errors.ErrValidationMissingProductID)
+		}
+		if item.Quantity <= 0 || item.Quantity > entity.MaxItemQuantity {
+			violations.Add(pkgErrors.Pointer("items", i, "quantity"), errors.ErrValidationInvalidQuantity)
+		}
+		if !item.Price.IsPositive() {
+			violations.Add(pkgErrors.Pointer("items", i, "price", "amount"), errors.ErrValidationInvalidPrice)
+		}
+		switch {
+		case item.Price.Validate() != nil:
+			violations.Add(pkgErrors.Pointer("items", i, "price", "currency"), errors.ErrValidationInvalidCurrency)
+		case currency == "":
+			currency = item.Price.Currency
+		case item.Price.Currency != currency:
+			violations.Add(pkgErrors.Pointer("items", i, "price", "currency"), errors.ErrValidationCurrencyMismatch)
+		}
+	}
+
+	violations.Merge("/shipping_address", shippingAddress.Validate())
+	if err := violations.Err(); err != nil {
+		return money.Money{}, err
+	}
+
+	// Bounded quantities still overflow with absurd prices, so the totals are checked too
+	subtotal := money.Zero(currency)
+	for i, item := range items {
+		itemSubtotal, err := item.Subtotal()
+		if err == nil {
+			subtotal, err = subtotal.Add(itemSubtotal)
+		}
+		if err != nil {
+			violations.Add(pkgErrors.Pointer("items", i), errors.ErrValidationAmountTooLarge)
+			return money.Money{}, violations.Err()
+		}
+	}
+	return subtotal, nil
+}
+
+// priceCoupon looks up couponCode and returns it with the discount it grants on subtotal
+func (uc *CreateOrderCase) priceCoupon(ctx context.Context, logEntry *logrus.Entry, couponCode string, subtotal money.Money, now time.Time) (*entity.Coupon, money.Money, error) {
+	coupon, err := uc.couponRepository.FindByCode(ctx, couponCode)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Warning("Create order failed: unknown coupon")
+			return nil, money.Money{}, errors.ErrCouponInvalid
+		}
+		logEntry.WithError(err).Error("Failed to retrieve coupon from repository")
+		return nil, money.Money{}, repositoryError(ctx, err)
+	}
+
+	discount, err := coupon.DiscountFor(subtotal, now)
+	if err != nil {
+		logEntry.WithError(err).Warning("Create order failed: coupon not applicable")
+		return nil, money.Money{}, err
+	}
+
+	return coupon, discount, nil
+}
+
+// checkUser confirms with the user directory that userID belongs to an existing user
+func (uc *CreateOrderCase) checkUser(ctx context.Context, logEntry *logrus.Entry, userID string) error {
+	_, err := uc.users.FindByID(ctx, userID)
+	switch {
+	case err == nil:
+		return nil
+	case stdErrors.Is(err, user.ErrNotFound):
+		logEntry.Warning("Create order failed: unknown user")
+		return errors.ErrOrderUnknownUser
+	case stdErrors.Is(err, user.ErrUnauthorized):
+		logEntry.WithError(err).Error("Create order failed: user service refused the lookup")
+		return errors.ErrAuthForbidden
+	case stdErrors.Is(ctx.Err(), context.DeadlineExceeded):
+		logEntry.WithError(err).Error("Create order failed: user lookup timed out")
+		return errors.ErrSystemTimeout
+	default:
+		logEntry.WithError(err).Error("Create order failed: user service unavailable")
+		return errors.ErrSystemServiceUnavailable
+	}
+}
@@ -0,0 +1,382 @@
+package usecase_test
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/pkg/events"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+	"github.com/robrt95x/godops/services/order/internal/user"
+)
+
+var testAddress = entity.Address{
+	Recipient:  "Test User",
+	Line1:      "123 Test St",
+	City:       "Springfield",
+	Region:     "IL",
+	PostalCode: "62701",
+	Country:    "US",
+}
+
+func TestCreateOrderCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	t.Run("should total items in minor units", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+
+		order, err := uc.Execute(adminContext(), "user-1", []entity.OrderItem{
+			{ProductID: "product-1", Quantity: 3, Price: money.Money{Amount: 10, Currency: "USD"}},
+			{ProductID: "product-2", Quantity: 1, Price: money.Money{Amount: 2999, Currency: "USD"}},
+		}, "", testAddress, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if order.Total.Amount != 3029 || order.Total.Currency != "USD" {
+			t.Errorf("Expected total 30.29 USD, got %s", order.Total)
+		}
+		if order.Status != entity.Pending {
+			t.Errorf("Expected status %s, got %s", entity.Pending, order.Status)
+		}
+		if repo.Count() != 1 {
+			t.Errorf("Expected 1 stored order, got %d", repo.Count())
+		}
+	})
+
+	t.Run("should write an order.created event with the order", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+
+		order, err := uc.Execute(adminContext(), "user-1", []entity.OrderItem{
+			{ProductID: "product-1", Quantity: 2, Price: money.Money{Amount: 500, Currency: "USD"}},
+		}, "", testAddress, "tok_visa")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		pending, _ := repo.Outbox().Pending(context.Background(), 10)
+		if len(pending) != 1 {
+			t.Fatalf("Expected 1 pending event, got %d", len(pending))
+		}
+		if pending[0].Type != events.OrderCreatedEvent || pending[0].AggregateID != order.ID {
+			t.Errorf("Expected order.created for %s, got %s for %s", order.ID, pending[0].Type, pending[0].AggregateID)
+		}
+
+		var payload events.OrderCreated
+		if err := pending[0].Decode(&payload); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if payload.TotalAmount != 1000 || payload.Currency != "USD" || len(payload.Items) != 1 {
+			t.Errorf("Unexpected payload %+v", payload)
+		}
+		if strings.Contains(string(pending[0].Payload), "tok_visa") {
+			t.Errorf("Expected the payment token to stay out of the event, got %s", pending[0].Payload)
+		}
+	})
+
+	t.Run("should store a payment saga with the order", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger).
+			WithPaymentSagas(time.Minute)
+
+		order, err := uc.Execute(adminContext(), "user-1", []entity.OrderItem{
+			{ProductID: "product-1", Quantity: 2, Price: money.Money{Amount: 500, Currency: "USD"}},
+		}, "", testAddress, "tok_visa")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		saga, err := repo.Sagas().FindByOrderID(context.Background(), order.ID)
+		if err != nil {
+			t.Fatalf("Expected a stored saga, got %v", err)
+		}
+		if saga.State != entity.SagaStarted || saga.PaymentToken != "tok_visa" || saga.Amount != order.Total {
+			t.Errorf("Expected a started saga charging %s with the token, got %+v", order.Total, saga)
+		}
+		if !saga.Deadline.After(order.CreatedAt) || saga.NextAttemptAt.After(time.Now()) {
+			t.Errorf("Expected a saga due now with a future deadline, got %+v", saga)
+		}
+	})
+
+	for _, tt := range []struct {
+		name         string
+		sagasEnabled bool
+		paymentToken string
+	}{
+		{"without a payment token", true, ""},
+		{"with payment sagas disabled", false, "tok_visa"},
+	} {
+		t.Run("should not store a payment saga "+tt.name, func(t *testing.T) {
+			repo := memory.NewOrderMemoryRepository()
+			uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+			if tt.sagasEnabled {
+				uc.WithPaymentSagas(time.Minute)
+			}
+
+			order, err := uc.Execute(adminContext(), "user-1", []entity.OrderItem{
+				{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 500, Currency: "USD"}},
+			}, "", testAddress, tt.paymentToken)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := repo.Sagas().FindByOrderID(context.Background(), order.ID); err != sql.ErrNoRows {
+				t.Errorf("Expected no saga, got %v", err)
+			}
+		})
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		items       []entity.OrderItem
+		expectedErr error
+	}{
+		{"missing user ID", "", []entity.OrderItem{{ProductID: "p", Quantity: 1, Price: money.Money{Amount: 1, Currency: "USD"}}}, errors.ErrValidationMissingUserID},
+		{"no items", "user-1", nil, errors.ErrValidationEmptyItems},
+		{"quantity above the limit", "user-1", []entity.OrderItem{{ProductID: "p", Quantity: entity.MaxItemQuantity + 1, Price: money.Money{Amount: 1, Currency: "USD"}}}, errors.ErrValidationInvalidQuantity},
+		{"item subtotal overflow", "user-1", []entity.OrderItem{{ProductID: "p", Quantity: 2, Price: money.Money{Amount: math.MaxInt64/2 + 1, Currency: "USD"}}}, errors.ErrValidationAmountTooLarge},
+		{"order subtotal overflow", "user-1", []entity.OrderItem{
+			{ProductID: "p1", Quantity: 1, Price: money.Money{Amount: math.MaxInt64 - 1, Currency: "USD"}},
+			{ProductID: "p2", Quantity: 1, Price: money.Money{Amount: 2, Currency: "USD"}},
+		}, errors.ErrValidationAmountTooLarge},
+		{"zero price", "user-1", []entity.OrderItem{{ProductID: "p", Quantity: 1, Price: money.Money{Currency: "USD"}}}, errors.ErrValidationInvalidPrice},
+		{"unknown currency", "user-1", []entity.OrderItem{{ProductID: "p", Quantity: 1, Price: money.Money{Amount: 1, Currency: "ABC"}}}, errors.ErrValidationInvalidCurrency},
+		{"mixed currencies", "user-1", []entity.OrderItem{
+			{ProductID: "p1", Quantity: 1, Price: money.Money{Amount: 1, Currency: "USD"}},
+			{ProductID: "p2", Quantity: 1, Price: money.Money{Amount: 1, Currency: "EUR"}},
+		}, errors.ErrValidationCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewOrderMemoryRepository()
+			uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+
+			order, err := uc.Execute(adminContext(), tt.userID, tt.items, "", testAddress, "")
+			if !stdErrors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if order != nil {
+				t.Errorf("Expected nil order, got %v", order)
+			}
+			if repo.Count() != 0 {
+				t.Errorf("Expected nothing stored, got %d orders", repo.Count())
+			}
+			if len(repo.Outbox().All()) != 0 {
+				t.Errorf("Expected no events, got %d", len(repo.Outbox().All()))
+			}
+		})
+	}
+}
+
+func TestCreateOrderCase_UserDirectory(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	items := []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}}
+
+	tests := []struct {
+		name        string
+		userID      string
+		lookupErr   error
+		expectedErr error
+	}{
+		{"known user", "user-1", nil, nil},
+		{"unknown user", "user-404", nil, errors.ErrOrderUnknownUser},
+		{"user service unavailable", "user-1", user.ErrUnavailable, errors.ErrSystemServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewOrderMemoryRepository()
+			users := memory.NewUserDirectory("user-1")
+			users.FailWith(tt.lookupErr)
+			uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), users, testLogger)
+
+			_, err := uc.Execute(adminContext(), tt.userID, items, "", testAddress, "")
+			if err != tt.expectedErr {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil && repo.Count() != 0 {
+				t.Errorf("Expected nothing stored, got %d orders", repo.Count())
+			}
+		})
+	}
+}
+
+func TestCreateOrderCase_Coupons(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	items := []entity.OrderItem{{ProductID: "product-1", Quantity: 2, Price: money.Money{Amount: 2999, Currency: "USD"}}}
+
+	coupons := []*entity.Coupon{
+		{Code: "TENOFF", Type: entity.PercentageDiscount, PercentOff: 10},
+		{Code: "FIVE", Type: entity.FixedAmountDiscount, AmountOff: money.Money{Amount: 500, Currency: "USD"}},
+		{Code: "HUGE", Type: entity.FixedAmountDiscount, AmountOff: money.Money{Amount: 100000, Currency: "USD"}},
+		{Code: "EUROS", Type: entity.FixedAmountDiscount, AmountOff: money.Money{Amount: 500, Currency: "EUR"}},
+		{Code: "BIGBASKET", Type: entity.PercentageDiscount, PercentOff: 50, MinBasket: money.Money{Amount: 10000, Currency: "USD"}},
+		{Code: "OLD", Type: entity.PercentageDiscount, PercentOff: 10, ExpiresAt: time.Now().Add(-time.Hour)},
+		{Code: "ONCE", Type: entity.PercentageDiscount, PercentOff: 10, MaxUsesPerUser: 1},
+	}
+
+	newCase := func() (*usecase.CreateOrderCase, *memory.OrderMemoryRepository) {
+		repo := memory.NewOrderMemoryRepository()
+		couponRepo := memory.NewCouponMemoryRepository()
+		for _, coupon := range coupons {
+			couponRepo.Save(context.Background(), coupon)
+		}
+		return usecase.NewCreateOrderCase(repo, couponRepo, memory.NewUserDirectory("user-1", "user-2"), testLogger), repo
+	}
+
+	tests := []struct {
+		name             string
+		code             string
+		expectedDiscount int64
+		expectedErr      error
+	}{
+		{"percentage discount", "tenoff", 599, nil},
+		{"fixed discount", "FIVE", 500, nil},
+		{"discount capped at subtotal", "HUGE", 5998, nil},
+		{"unknown coupon", "NOPE", 0, errors.ErrCouponInvalid},
+		{"expired coupon", "OLD", 0, errors.ErrCouponExpired},
+		{"minimum basket not met", "BIGBASKET", 0, errors.ErrCouponMinBasketNotMet},
+		{"coupon in another currency", "EUROS", 0, errors.ErrCouponCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, repo := newCase()
+
+			order, err := uc.Execute(adminContext(), "user-1", items, tt.code, testAddress, "")
+			if err != tt.expectedErr {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				if repo.Count() != 0 {
+					t.Errorf("Expected nothing stored, got %d orders", repo.Count())
+				}
+				return
+			}
+
+			if order.Subtotal.Amount != 5998 {
+				t.Errorf("Expected subtotal 5998, got %d", order.Subtotal.Amount)
+			}
+			if order.Discount.Amount != tt.expectedDiscount {
+				t.Errorf("Expected discount %d, got %d", tt.expectedDiscount, order.Discount.Amount)
+			}
+			if order.Total.Amount != 5998-tt.expectedDiscount {
+				t.Errorf("Expected total %d, got %d", 5998-tt.expectedDiscount, order.Total.Amount)
+			}
+			if order.CouponCode != entity.NormalizeCouponCode(tt.code) {
+				t.Errorf("Expected coupon code %s, got %s", entity.NormalizeCouponCode(tt.code), order.CouponCode)
+			}
+		})
+	}
+
+	t.Run("should enforce the per-user usage limit", func(t *testing.T) {
+		uc, _ := newCase()
+
+		if _, err := uc.Execute(adminContext(), "user-1", items, "ONCE", testAddress, ""); err != nil {
+			t.Fatalf("Expected first use to succeed, got %v", err)
+		}
+		if _, err := uc.Execute(adminContext(), "user-1", items, "ONCE", testAddress, ""); err != errors.ErrCouponUsageLimitReached {
+			t.Errorf("Expected ErrCouponUsageLimitReached, got %v", err)
+		}
+		if _, err := uc.Execute(adminContext(), "user-2", items, "ONCE", testAddress, ""); err != nil {
+			t.Errorf("Expected another user to use the coupon, got %v", err)
+		}
+	})
+}
+
+func TestCreateOrderCase_ShippingAddress(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	items := []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}}
+
+	withAddress := func(change func(a *entity.Address)) entity.Address {
+		address := testAddress
+		change(&address)
+		return address
+	}
+
+	tests := []struct {
+		name        string
+		address     entity.Address
+		expectedErr error
+	}{
+		{"valid US address", testAddress, nil},
+		{"US ZIP+4", withAddress(func(a *entity.Address) { a.PostalCode = "62701-1234" }), nil},
+		{"lower-case Canadian address", entity.Address{Recipient: "A", Line1: "1 Rue", City: "Ottawa", Region: "on", PostalCode: "k1a0b1", Country: "ca"}, nil},
+		{"UK address without region", entity.Address{Recipient: "A", Line1: "10 Downing St", City: "London", PostalCode: "SW1A 2AA", Country: "GB"}, nil},
+		{"missing address", entity.Address{}, errors.ErrValidationMissingShippingAddress},
+		{"unsupported country", withAddress(func(a *entity.Address) { a.Country = "ZZ" }), errors.ErrValidationInvalidCountry},
+		{"missing recipient", withAddress(func(a *entity.Address) { a.Recipient = " " }), errors.ErrValidationInvalidRecipient},
+		{"missing line 1", withAddress(func(a *entity.Address) { a.Line1 = "" }), errors.ErrValidationInvalidAddressLine},
+		{"missing city", withAddress(func(a *entity.Address) { a.City = "" }), errors.ErrValidationInvalidCity},
+		{"unknown US state", withAddress(func(a *entity.Address) { a.Region = "XX" }), errors.ErrValidationInvalidRegion},
+		{"malformed ZIP code", withAddress(func(a *entity.Address) { a.PostalCode = "6270" }), errors.ErrValidationInvalidPostalCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.NewCreateOrderCase(memory.NewOrderMemoryRepository(), memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+
+			order, err := uc.Execute(adminContext(), "user-1", items, "", tt.address, "")
+			if !stdErrors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr == nil && order.ShippingAddress != tt.address.Normalize() {
+				t.Errorf("Expected normalized address %+v, got %+v", tt.address.Normalize(), order.ShippingAddress)
+			}
+		})
+	}
+}
+
+func TestCreateOrderCase_FieldErrors(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	repo := memory.NewOrderMemoryRepository()
+	uc := usecase.NewCreateOrderCase(repo, memory.NewCouponMemoryRepository(), memory.NewUserDirectory("user-1"), testLogger)
+
+	address := testAddress
+	address.City = ""
+	address.PostalCode = "6270"
+
+	_, err := uc.Execute(adminContext(), "", []entity.OrderItem{
+		{ProductID: "p1", Quantity: 1, Price: money.Money{Amount: 100, Currency: "USD"}},
+		{ProductID: "", Quantity: 0, Price: money.Money{Amount: 100, Currency: "USD"}},
+		{ProductID: "p3", Quantity: 1, Price: money.Money{Amount: 0, Currency: "EUR"}},
+	}, "", address, "")
+
+	var violations *pkgErrors.ValidationErrors
+	if !stdErrors.As(err, &violations) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+
+	expected := []pkgErrors.FieldError{
+		{Pointer: "/user_id", Err: errors.ErrValidationMissingUserID},
+		{Pointer: "/items/1/product_id", Err: errors.ErrValidationMissingProductID},
+		{Pointer: "/items/1/quantity", Err: errors.ErrValidationInvalidQuantity},
+		{Pointer: "/items/2/price/amount", Err: errors.ErrValidationInvalidPrice},
+		{Pointer: "/items/2/price/currency", Err: errors.ErrValidationCurrencyMismatch},
+		{Pointer: "/shipping_address/city", Err: errors.ErrValidationInvalidCity},
+		{Pointer: "/shipping_address/postal_code", Err: errors.ErrValidationInvalidPostalCode},
+	}
+	if len(violations.Fields) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %v", len(expected), violations.Fields)
+	}
+	for i, field := range expected {
+		if violations.Fields[i] != field {
+			t.Errorf("Expected %s: %v, got %s: %v", field.Pointer, field.Err, violations.Fields[i].Pointer, violations.Fields[i].Err)
+		}
+	}
+	if repo.Count() != 0 {
+		t.Errorf("Expected nothing stored, got %d orders", repo.Count())
+	}
+}
@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type GetCouponCase struct {
+	repository repository.CouponRepository
+	logger     *logrus.Logger
+}
+
+func NewGetCouponCase(repository repository.CouponRepository, logger *logrus.Logger) *GetCouponCase {
+	return &GetCouponCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *GetCouponCase) Execute(ctx context.Context, code string) (*entity.Coupon, error) {
+	code = entity.NormalizeCouponCode(code)
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":    "GetCoupon",
+		"coupon_code": code,
+	})
+
+	logEntry.Debug("Starting get coupon use case")
+
+	coupon, err := uc.repository.FindByCode(ctx, code)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Info("Coupon not found")
+			return nil, errors.ErrCouponNotFound
+		}
+		logEntry.WithError(err).Error("Failed to retrieve coupon from repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	logEntry.Info("Coupon retrieved successfully")
+	return coupon, nil
+}
@@ -1,10 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"database/sql"
 
 	"github.com/robrt95x/godops/services/order/internal/entity"
 	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
 	"github.com/robrt95x/godops/services/order/internal/repository"
 	"github.com/sirupsen/logrus"
 )
@@ -21,7 +23,7 @@ func NewGetOrderByIDCase(repository repository.OrderRepository, logger *logrus.L
 	}
 }
 
-func (uc *GetOrderByIDCase) Execute(id string) (*entity.Order, error) {
+func (uc *GetOrderByIDCase) Execute(ctx context.Context, id string) (*entity.Order, error) {
 	logEntry := uc.logger.WithFields(logrus.Fields{
 		"use_case": "GetOrderByID",
 		"order_id": id,
@@ -34,14 +36,19 @@ func (uc *GetOrderByIDCase) Execute(id string) (*entity.Order, error) {
 		return nil, errors.ErrOrderInvalidID
 	}
 
-	order, err := uc.repository.FindByID(id)
+	order, err := uc.repository.FindByID(ctx, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			logEntry.Info("Order not found")
 			return nil, errors.ErrOrderNotFound
 		}
 		logEntry.WithError(err).Error("Failed to retrieve order from repository")
-		return nil, errors.ErrDatabaseQuery
+		return nil, repositoryError(ctx, err)
+	}
+
+	if err := policy.Authorize(ctx, policy.ActionReadOrder, order.UserID); err != nil {
+		logEntry.WithError(err).Warning("Get order denied")
+		return nil, err
 	}
 
 	logEntry.Info("Order retrieved successfully")
@@ -1,10 +1,12 @@
 package usecase_test
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
 	"github.com/robrt95x/godops/services/order/internal/entity"
 	"github.com/robrt95x/godops/services/order/internal/errors"
 	"github.com/robrt95x/godops/services/order/internal/infra/memory"
@@ -25,26 +27,33 @@ func TestGetOrderByIDCase_Execute(t *testing.T) {
 			{
 				ProductID: "product-1",
 				Quantity:  2,
-				Price:     29.99,
+				Price:     money.Money{Amount: 2999, Currency: "USD"},
 			},
 		},
 		Status:          entity.Pending,
 		CouponCode:      "DISCOUNT10",
-		Total:           59.98,
-		ShippingAddress: "123 Test St",
+		Total:           money.Money{Amount: 5998, Currency: "USD"},
+		ShippingAddress: entity.Address{
+			Recipient:  "Test User",
+			Line1:      "123 Test St",
+			City:       "Springfield",
+			Region:     "IL",
+			PostalCode: "62701",
+			Country:    "US",
+		},
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
 	// Save the test order
-	err := repo.Save(testOrder)
+	err := repo.Save(context.Background(), testOrder, nil)
 	if err != nil {
 		t.Fatalf("Failed to save test order: %v", err)
 	}
 
 	t.Run("should return order when found", func(t *testing.T) {
 		// Execute
-		result, err := uc.Execute("test-order-123")
+		result, err := uc.Execute(adminContext(), "test-order-123")
 
 		// Assert
 		if err != nil {
@@ -62,14 +71,14 @@ func TestGetOrderByIDCase_Execute(t *testing.T) {
 		if len(result.Items) != 1 {
 			t.Errorf("Expected 1 item, got %d", len(result.Items))
 		}
-		if result.Total != 59.98 {
-			t.Errorf("Expected total 59.98, got %f", result.Total)
+		if result.Total.Amount != 5998 || result.Total.Currency != "USD" {
+			t.Errorf("Expected total 59.98 USD, got %s", result.Total)
 		}
 	})
 
 	t.Run("should return error when order not found", func(t *testing.T) {
 		// Execute
-		result, err := uc.Execute("non-existent-order")
+		result, err := uc.Execute(adminContext(), "non-existent-order")
 
 		// Assert
 		if err != errors.ErrOrderNotFound {
@@ -82,7 +91,7 @@ func TestGetOrderByIDCase_Execute(t *testing.T) {
 
 	t.Run("should return error for invalid order ID", func(t *testing.T) {
 		// Execute
-		result, err := uc.Execute("")
+		result, err := uc.Execute(adminContext(), "")
 
 		// Assert
 		if err != errors.ErrOrderInvalidID {
@@ -92,6 +101,34 @@ func TestGetOrderByIDCase_Execute(t *testing.T) {
 			t.Errorf("Expected nil result, got %v", result)
 		}
 	})
+
+	t.Run("should report an exceeded deadline as a timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		<-ctx.Done()
+
+		timeoutUC := usecase.NewGetOrderByIDCase(&deadlineOrderRepository{repo}, testLogger)
+		result, err := timeoutUC.Execute(ctx, "test-order-123")
+
+		if err != errors.ErrSystemTimeout {
+			t.Errorf("Expected ErrSystemTimeout, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected nil result, got %v", result)
+		}
+	})
+}
+
+// deadlineOrderRepository fails reads with the context's error, as database/sql does
+type deadlineOrderRepository struct {
+	*memory.OrderMemoryRepository
+}
+
+func (r *deadlineOrderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r.OrderMemoryRepository.FindByID(ctx, id)
 }
 
 func TestMemoryRepository_Isolation(t *testing.T) {
@@ -102,22 +139,22 @@ func TestMemoryRepository_Isolation(t *testing.T) {
 	order1 := &entity.Order{
 		ID:     "order-1",
 		UserID: "user-1",
-		Items:  []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: 10.0}},
+		Items:  []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}},
 		Status: entity.Pending,
-		Total:  10.0,
+		Total:  money.Money{Amount: 1000, Currency: "USD"},
 	}
 
 	order2 := &entity.Order{
 		ID:     "order-2",
 		UserID: "user-2",
-		Items:  []entity.OrderItem{{ProductID: "product-2", Quantity: 2, Price: 15.0}},
+		Items:  []entity.OrderItem{{ProductID: "product-2", Quantity: 2, Price: money.Money{Amount: 1500, Currency: "USD"}}},
 		Status: entity.Completed,
-		Total:  30.0,
+		Total:  money.Money{Amount: 3000, Currency: "USD"},
 	}
 
 	// Save orders
-	repo.Save(order1)
-	repo.Save(order2)
+	repo.Save(context.Background(), order1, nil)
+	repo.Save(context.Background(), order2, nil)
 
 	// Verify count
 	if repo.Count() != 2 {
@@ -133,7 +170,7 @@ func TestMemoryRepository_Isolation(t *testing.T) {
 	}
 
 	// Verify orders are not found
-	_, err := repo.FindByID("order-1")
+	_, err := repo.FindByID(context.Background(), "order-1")
 	if err == nil {
 		t.Error("Expected error when finding order after clear")
 	}
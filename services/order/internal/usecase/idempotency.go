@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const MaxIdempotencyKeyLength = 255
+
+// IdempotencyCase records responses by Idempotency-Key so retried requests
+// are answered from the stored response instead of being executed again.
+// Keys are scoped to the subject of the principal in ctx, so callers choosing
+// the same key never see each other's responses.
+// A reservation is held for lease until its response is stored, so a request
+// that crashed before completing blocks retries only briefly; stored
+// responses are kept for ttl.
+type IdempotencyCase struct {
+	repository repository.IdempotencyRepository
+	ttl        time.Duration
+	lease      time.Duration
+	logger     *logrus.Logger
+}
+
+func NewIdempotencyCase(repository repository.IdempotencyRepository, ttl, lease time.Duration, logger *logrus.Logger) *IdempotencyCase {
+	return &IdempotencyCase{
+		repository: repository,
+		ttl:        ttl,
+		lease:      lease,
+		logger:     logger,
+	}
+}
+
+// Begin reserves key for a request whose body hashes to requestHash.
+// It returns nil when the caller should process the request, or the stored
+// record when an identical request already completed and should be replayed.
+func (uc *IdempotencyCase) Begin(ctx context.Context, key, requestHash string) (*entity.IdempotencyRecord, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":        "Idempotency",
+		"idempotency_key": key,
+	})
+
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logEntry = logEntry.WithField("subject", subject)
+
+	if key == "" || len(key) > MaxIdempotencyKeyLength {
+		logEntry.Warning("Invalid idempotency key")
+		return nil, errors.ErrValidationInvalidIdempotencyKey
+	}
+
+	now := time.Now()
+	existing, err := uc.repository.Reserve(ctx, &entity.IdempotencyRecord{
+		Subject:     subject,
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(uc.lease),
+	})
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to reserve idempotency key")
+		return nil, repositoryError(ctx, err)
+	}
+
+	if existing == nil {
+		logEntry.Debug("Idempotency key reserved")
+		return nil, nil
+	}
+
+	if existing.RequestHash != requestHash {
+		logEntry.Warning("Idempotency key reused with a different request")
+		return nil, errors.ErrIdempotencyKeyMismatch
+	}
+
+	if !existing.IsCompleted() {
+		logEntry.Info("Idempotent request still in progress")
+		return nil, errors.ErrIdempotencyRequestInProgress
+	}
+
+	logEntry.Info("Replaying stored idempotent response")
+	return existing, nil
+}
+
+// Complete stores the response for a key reserved by Begin and keeps it for the TTL
+func (uc *IdempotencyCase) Complete(ctx context.Context, key string, statusCode int, responseBody []byte) error {
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return err
+	}
+	if err := uc.repository.Complete(ctx, subject, key, statusCode, responseBody, time.Now().Add(uc.ttl)); err != nil {
+		uc.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to store idempotent response")
+		return repositoryError(ctx, err)
+	}
+	return nil
+}
+
+// Release frees a key reserved by Begin so a failed request can be retried
+func (uc *IdempotencyCase) Release(ctx context.Context, key string) error {
+	subject, err := idempotencySubject(ctx)
+	if err != nil {
+		return err
+	}
+	if err := uc.repository.Delete(ctx, subject, key); err != nil {
+		uc.logger.WithError(err).WithField("idempotency_key", key).Error("Failed to release idempotency key")
+		return repositoryError(ctx, err)
+	}
+	return nil
+}
+
+// PurgeExpired removes records whose TTL has elapsed
+func (uc *IdempotencyCase) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := uc.repository.DeleteExpired(ctx, time.Now())
+	if err != nil {
+		uc.logger.WithError(err).Error("Failed to purge expired idempotency keys")
+		return 0, repositoryError(ctx, err)
+	}
+	return deleted, nil
+}
+
+// idempotencySubject returns the subject keys are scoped to; a context without
+// a principal is rejected, as by the authorization policy
+func idempotencySubject(ctx context.Context) (string, error) {
+	principal, ok := policy.PrincipalFromContext(ctx)
+	if !ok {
+		return "", errors.ErrAuthUnauthenticated
+	}
+	return principal.Subject, nil
+}
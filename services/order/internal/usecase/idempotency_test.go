@@ -0,0 +1,155 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+func TestIdempotencyCase(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	t.Run("should replay a completed response for an identical retry", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		stored, err := uc.Begin(adminContext(), "key-1", "hash-a")
+		if err != nil || stored != nil {
+			t.Fatalf("Expected fresh reservation, got %v, %v", stored, err)
+		}
+		if err := uc.Complete(adminContext(), "key-1", 201, []byte(`{"id":"order-1"}`)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		stored, err = uc.Begin(adminContext(), "key-1", "hash-a")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if stored == nil || stored.StatusCode != 201 || string(stored.ResponseBody) != `{"id":"order-1"}` {
+			t.Errorf("Expected stored 201 response, got %+v", stored)
+		}
+	})
+
+	t.Run("should reject a different request with the same key", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		uc.Begin(adminContext(), "key-1", "hash-a")
+		uc.Complete(adminContext(), "key-1", 201, []byte(`{}`))
+
+		if _, err := uc.Begin(adminContext(), "key-1", "hash-b"); err != errors.ErrIdempotencyKeyMismatch {
+			t.Errorf("Expected ErrIdempotencyKeyMismatch, got %v", err)
+		}
+	})
+
+	t.Run("should reject a retry while the first request is in progress", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		uc.Begin(adminContext(), "key-1", "hash-a")
+
+		if _, err := uc.Begin(adminContext(), "key-1", "hash-a"); err != errors.ErrIdempotencyRequestInProgress {
+			t.Errorf("Expected ErrIdempotencyRequestInProgress, got %v", err)
+		}
+	})
+
+	t.Run("should let a retry take over a reservation whose lease ran out", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Millisecond, testLogger)
+
+		// The first request never completes, as when the service crashed mid-request
+		uc.Begin(adminContext(), "key-1", "hash-a")
+		time.Sleep(5 * time.Millisecond)
+
+		if stored, err := uc.Begin(adminContext(), "key-1", "hash-a"); err != nil || stored != nil {
+			t.Fatalf("Expected the stale reservation to be taken over, got %v, %v", stored, err)
+		}
+		if err := uc.Complete(adminContext(), "key-1", 201, []byte(`{"id":"order-1"}`)); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// The stored response outlives the lease
+		time.Sleep(5 * time.Millisecond)
+		stored, err := uc.Begin(adminContext(), "key-1", "hash-a")
+		if err != nil || stored == nil || stored.StatusCode != 201 {
+			t.Errorf("Expected the stored 201 response, got %+v, %v", stored, err)
+		}
+	})
+
+	t.Run("should allow a retry after the key is released", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		uc.Begin(adminContext(), "key-1", "hash-a")
+		uc.Release(adminContext(), "key-1")
+
+		if stored, err := uc.Begin(adminContext(), "key-1", "hash-a"); err != nil || stored != nil {
+			t.Errorf("Expected fresh reservation, got %v, %v", stored, err)
+		}
+	})
+
+	t.Run("should forget keys once the TTL elapses", func(t *testing.T) {
+		repo := memory.NewIdempotencyMemoryRepository()
+		uc := usecase.NewIdempotencyCase(repo, time.Millisecond, time.Millisecond, testLogger)
+
+		uc.Begin(adminContext(), "key-1", "hash-a")
+		uc.Complete(adminContext(), "key-1", 201, []byte(`{}`))
+		time.Sleep(5 * time.Millisecond)
+
+		if stored, err := uc.Begin(adminContext(), "key-1", "hash-b"); err != nil || stored != nil {
+			t.Errorf("Expected expired key to be reusable, got %v, %v", stored, err)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		deleted, err := uc.PurgeExpired(adminContext())
+		if err != nil || deleted != 1 {
+			t.Errorf("Expected 1 purged key, got %d, %v", deleted, err)
+		}
+	})
+
+	t.Run("should scope keys to the caller", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+		ownerCtx := policy.WithPrincipal(context.Background(), owner)
+		strangerCtx := policy.WithPrincipal(context.Background(), stranger)
+
+		uc.Begin(ownerCtx, "key-1", "hash-a")
+		uc.Complete(ownerCtx, "key-1", 201, []byte(`{"id":"order-1"}`))
+
+		// The same key and body from another caller is a request of its own
+		stored, err := uc.Begin(strangerCtx, "key-1", "hash-a")
+		if err != nil || stored != nil {
+			t.Fatalf("Expected a fresh reservation for another caller, got %+v, %v", stored, err)
+		}
+		if err := uc.Release(strangerCtx, "key-1"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		// Releasing it leaves the first caller's response in place
+		stored, err = uc.Begin(ownerCtx, "key-1", "hash-a")
+		if err != nil || stored == nil || string(stored.ResponseBody) != `{"id":"order-1"}` {
+			t.Errorf("Expected the owner's stored response, got %+v, %v", stored, err)
+		}
+	})
+
+	t.Run("should reject callers without a principal", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		if _, err := uc.Begin(context.Background(), "key-1", "hash-a"); err != errors.ErrAuthUnauthenticated {
+			t.Errorf("Expected ErrAuthUnauthenticated, got %v", err)
+		}
+		if err := uc.Complete(context.Background(), "key-1", 201, []byte(`{}`)); err != errors.ErrAuthUnauthenticated {
+			t.Errorf("Expected ErrAuthUnauthenticated, got %v", err)
+		}
+	})
+
+	t.Run("should reject oversized keys", func(t *testing.T) {
+		uc := usecase.NewIdempotencyCase(memory.NewIdempotencyMemoryRepository(), time.Hour, time.Minute, testLogger)
+
+		key := strings.Repeat("k", usecase.MaxIdempotencyKeyLength+1)
+		if _, err := uc.Begin(adminContext(), key, "hash-a"); err != errors.ErrValidationInvalidIdempotencyKey {
+			t.Errorf("Expected ErrValidationInvalidIdempotencyKey, got %v", err)
+		}
+	})
+}
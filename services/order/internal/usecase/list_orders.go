@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultListLimit = 20
+	MaxListLimit     = 100
+)
+
+// ListOrdersInput holds the filters and page position for ListOrdersCase.
+// At least one of UserID and ProductID is required; for customers UserID
+// defaults to their own and may not name anyone else.
+type ListOrdersInput struct {
+	UserID      string
+	ProductID   string
+	Status      entity.OrderStatus
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Cursor      string
+	Limit       int
+}
+
+// ListOrdersOutput is one page of orders; NextCursor is empty on the last page
+type ListOrdersOutput struct {
+	Orders     []*entity.Order `json:"orders"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type ListOrdersCase struct {
+	repository repository.OrderRepository
+	logger     *logrus.Logger
+}
+
+func NewListOrdersCase(repository repository.OrderRepository, logger *logrus.Logger) *ListOrdersCase {
+	return &ListOrdersCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *ListOrdersCase) Execute(ctx context.Context, input ListOrdersInput) (*ListOrdersOutput, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "ListOrders",
+		"user_id":    input.UserID,
+		"product_id": input.ProductID,
+		"status":     input.Status,
+		"limit":      input.Limit,
+	})
+
+	logEntry.Debug("Starting list orders use case")
+
+	principal, ok := policy.PrincipalFromContext(ctx)
+	if !ok {
+		logEntry.Warning("List orders denied: no principal")
+		return nil, errors.ErrAuthUnauthenticated
+	}
+	if input.UserID == "" && !principal.IsStaff() {
+		input.UserID = principal.Subject
+	}
+	if err := policy.AuthorizeOrder(principal, policy.ActionListOrders, input.UserID); err != nil {
+		logEntry.WithError(err).Warning("List orders denied")
+		return nil, err
+	}
+
+	if input.UserID == "" && input.ProductID == "" {
+		logEntry.Warning("List orders failed: missing user ID")
+		return nil, errors.ErrValidationMissingUserID
+	}
+
+	if input.Status != "" && !input.Status.IsValid() {
+		logEntry.Warning("List orders failed: unknown status")
+		return nil, errors.ErrValidationInvalidStatus
+	}
+
+	if !input.CreatedFrom.IsZero() && !input.CreatedTo.IsZero() && !input.CreatedFrom.Before(input.CreatedTo) {
+		logEntry.Warning("List orders failed: empty created-at range")
+		return nil, errors.ErrValidationInvalidDateRange
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = DefaultListLimit
+	}
+	if limit < 0 || limit > MaxListLimit {
+		logEntry.Warning("List orders failed: limit out of range")
+		return nil, errors.ErrValidationInvalidLimit
+	}
+
+	filter := repository.OrderFilter{
+		UserID:      input.UserID,
+		ProductID:   input.ProductID,
+		Status:      input.Status,
+		CreatedFrom: input.CreatedFrom,
+		CreatedTo:   input.CreatedTo,
+		Limit:       limit + 1,
+	}
+
+	if input.Cursor != "" {
+		cursor, err := decodeOrderCursor(input.Cursor)
+		if err != nil {
+			logEntry.WithError(err).Warning("List orders failed: malformed cursor")
+			return nil, errors.ErrValidationInvalidCursor
+		}
+		filter.After = cursor
+	}
+
+	orders, err := uc.repository.List(ctx, filter)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to list orders from repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	output := &ListOrdersOutput{Orders: orders}
+	if len(orders) > limit {
+		output.Orders = orders[:limit]
+		last := output.Orders[limit-1]
+		output.NextCursor = encodeOrderCursor(repository.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	logEntry.WithField("orders_count", len(output.Orders)).Info("Orders listed successfully")
+	return output, nil
+}
+
+// Cursors are opaque to clients: base64url of "<created_at RFC3339Nano>|<order ID>"
+func encodeOrderCursor(cursor repository.OrderCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeOrderCursor(value string) (*repository.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	createdAt, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return nil, errors.ErrValidationInvalidCursor
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &repository.OrderCursor{CreatedAt: parsed, ID: id}, nil
+}
@@ -0,0 +1,137 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+func TestListOrdersCase_Execute(t *testing.T) {
+	// Setup
+	repo := memory.NewOrderMemoryRepository()
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	uc := usecase.NewListOrdersCase(repo, testLogger)
+
+	// Five orders for user-1 one hour apart (order-0 oldest), one for user-2
+	base := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		status := entity.Pending
+		if i%2 == 1 {
+			status = entity.Cancelled
+		}
+		repo.Save(context.Background(), &entity.Order{
+			ID:        fmt.Sprintf("order-%d", i),
+			UserID:    "user-1",
+			Items:     []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}},
+			Status:    status,
+			Total:     money.Money{Amount: 1000, Currency: "USD"},
+			CreatedAt: base.Add(time.Duration(i) * time.Hour),
+		}, nil)
+	}
+	repo.Save(context.Background(), &entity.Order{
+		ID:        "other-order",
+		UserID:    "user-2",
+		Items:     []entity.OrderItem{{ProductID: "product-2", Quantity: 1, Price: money.Money{Amount: 500, Currency: "USD"}}},
+		Status:    entity.Pending,
+		CreatedAt: base,
+	}, nil)
+
+	t.Run("should page through a user's orders newest first", func(t *testing.T) {
+		var ids []string
+		cursor := ""
+		pages := 0
+		for {
+			page, err := uc.Execute(adminContext(), usecase.ListOrdersInput{UserID: "user-1", Cursor: cursor, Limit: 2})
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			pages++
+			for _, order := range page.Orders {
+				ids = append(ids, order.ID)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			cursor = page.NextCursor
+		}
+
+		expected := []string{"order-4", "order-3", "order-2", "order-1", "order-0"}
+		if fmt.Sprint(ids) != fmt.Sprint(expected) {
+			t.Errorf("Expected %v, got %v", expected, ids)
+		}
+		if pages != 3 {
+			t.Errorf("Expected 3 pages, got %d", pages)
+		}
+	})
+
+	t.Run("should filter by status and created-at range", func(t *testing.T) {
+		page, err := uc.Execute(adminContext(), usecase.ListOrdersInput{
+			UserID:      "user-1",
+			Status:      entity.Pending,
+			CreatedFrom: base.Add(time.Hour),
+			CreatedTo:   base.Add(4 * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Orders) != 1 || page.Orders[0].ID != "order-2" {
+			t.Errorf("Expected only order-2, got %v", page.Orders)
+		}
+		if page.NextCursor != "" {
+			t.Errorf("Expected no next cursor, got %q", page.NextCursor)
+		}
+	})
+
+	t.Run("should list orders containing a product", func(t *testing.T) {
+		page, err := uc.Execute(adminContext(), usecase.ListOrdersInput{ProductID: "product-2"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Orders) != 1 || page.Orders[0].ID != "other-order" {
+			t.Errorf("Expected only other-order, got %v", page.Orders)
+		}
+
+		page, err = uc.Execute(adminContext(), usecase.ListOrdersInput{UserID: "user-2", ProductID: "product-1"})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(page.Orders) != 0 {
+			t.Errorf("Expected no orders, got %v", page.Orders)
+		}
+	})
+
+	t.Run("should reject invalid input", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			input       usecase.ListOrdersInput
+			expectedErr error
+		}{
+			{"missing user ID", usecase.ListOrdersInput{}, errors.ErrValidationMissingUserID},
+			{"unknown status", usecase.ListOrdersInput{UserID: "user-1", Status: "LOST"}, errors.ErrValidationInvalidStatus},
+			{"malformed cursor", usecase.ListOrdersInput{UserID: "user-1", Cursor: "not a cursor"}, errors.ErrValidationInvalidCursor},
+			{"limit too large", usecase.ListOrdersInput{UserID: "user-1", Limit: usecase.MaxListLimit + 1}, errors.ErrValidationInvalidLimit},
+			{"negative limit", usecase.ListOrdersInput{UserID: "user-1", Limit: -1}, errors.ErrValidationInvalidLimit},
+			{"inverted range", usecase.ListOrdersInput{UserID: "user-1", CreatedFrom: base.Add(time.Hour), CreatedTo: base}, errors.ErrValidationInvalidDateRange},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := uc.Execute(adminContext(), tt.input)
+				if err != tt.expectedErr {
+					t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+				}
+				if result != nil {
+					t.Errorf("Expected nil result, got %v", result)
+				}
+			})
+		}
+	})
+}
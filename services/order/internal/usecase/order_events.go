@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"github.com/robrt95x/godops/pkg/events"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+)
+
+// orderCreatedEvent builds the order.created envelope saved with a new order
+func orderCreatedEvent(order *entity.Order) (events.Envelope, error) {
+	items := make([]events.OrderLineItem, 0, len(order.Items))
+	for _, item := range order.Items {
+		items = append(items, events.OrderLineItem{
+			ProductID:       item.ProductID,
+			Quantity:        item.Quantity,
+			UnitPriceAmount: item.Price.Amount,
+		})
+	}
+
+	return events.NewEnvelope(events.OrderCreatedEvent, events.OrderAggregate, order.ID, events.OrderCreated{
+		OrderID:         order.ID,
+		UserID:          order.UserID,
+		Items:           items,
+		CouponCode:      order.CouponCode,
+		SubtotalAmount:  order.Subtotal.Amount,
+		DiscountAmount:  order.Discount.Amount,
+		TotalAmount:     order.Total.Amount,
+		Currency:        order.Total.Currency,
+		ShippingCountry: order.ShippingAddress.Country,
+		CreatedAt:       order.CreatedAt,
+	}, order.CreatedAt)
+}
+
+// orderStatusChangedEvent builds the order.status_changed envelope saved with a transition
+func orderStatusChangedEvent(order *entity.Order, previous entity.OrderStatus) (events.Envelope, error) {
+	return events.NewEnvelope(events.OrderStatusChangedEvent, events.OrderAggregate, order.ID, events.OrderStatusChanged{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		PreviousStatus: string(previous),
+		Status:         string(order.Status),
+		TotalAmount:    order.Total.Amount,
+		Currency:       order.Total.Currency,
+		ChangedAt:      order.UpdatedAt,
+	}, order.UpdatedAt)
+}
@@ -0,0 +1,298 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/payment"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+// PaymentSagaConfig tunes how payment sagas are driven
+type PaymentSagaConfig struct {
+	// Timeout bounds how long a saga may take to capture the payment before it is compensated
+	Timeout time.Duration
+	// RetryBackoff is the delay before retrying a failed step, doubled on every attempt up to MaxBackoff
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// Lease is how long a claimed saga is hidden from other runners; it must outlast a full advance
+	Lease     time.Duration
+	BatchSize int
+}
+
+// PaymentSagaCase charges new orders through the payment service. Sagas are
+// created with their order by CreateOrderCase; for each one it confirms
+// the order, creates, authorizes and captures a payment intent, then marks the
+// order PAID. A declined card, a rejected payment or a timeout before capture
+// voids the payment and cancels the order instead.
+type PaymentSagaCase struct {
+	sagas        repository.PaymentSagaRepository
+	orders       repository.OrderRepository
+	updateStatus *UpdateOrderStatusCase
+	payments     payment.Service
+	config       PaymentSagaConfig
+	logger       *logrus.Logger
+}
+
+func NewPaymentSagaCase(sagas repository.PaymentSagaRepository, orders repository.OrderRepository, payments payment.Service, config PaymentSagaConfig, logger *logrus.Logger) *PaymentSagaCase {
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Minute
+	}
+	if config.Lease <= 0 {
+		config.Lease = 2 * time.Minute
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	return &PaymentSagaCase{
+		sagas:        sagas,
+		orders:       orders,
+		updateStatus: NewUpdateOrderStatusCase(orders, logger),
+		payments:     payments,
+		config:       config,
+		logger:       logger,
+	}
+}
+
+// ResumeDue advances every saga whose next step is due, including sagas left
+// in flight by a previous process, and returns how many it advanced
+func (uc *PaymentSagaCase) ResumeDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	sagas, err := uc.sagas.ClaimDue(ctx, now, now.Add(uc.config.Lease), uc.config.BatchSize)
+	if err != nil {
+		uc.logger.WithField("use_case", "PaymentSaga").WithError(err).Error("Failed to claim due payment sagas")
+		return 0, repositoryError(ctx, err)
+	}
+
+	for _, saga := range sagas {
+		if ctx.Err() != nil {
+			break
+		}
+		uc.advance(ctx, saga)
+	}
+	return len(sagas), nil
+}
+
+// advance runs the saga's steps until it finishes or a step has to be retried
+// later, persisting the saga after every step. Every save is fenced by the
+// lease the saga was claimed with, so once the lease runs out and another
+// runner claims the saga, this one stops instead of overwriting its progress.
+func (uc *PaymentSagaCase) advance(ctx context.Context, saga *entity.PaymentSaga) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "PaymentSaga",
+		"order_id":   saga.OrderID,
+		"payment_id": saga.PaymentID,
+	})
+	lease := saga.NextAttemptAt
+
+	for !saga.State.IsTerminal() {
+		from := saga.State
+		now := time.Now()
+		if !saga.State.IsCommitted() && saga.State != entity.SagaCompensating && now.After(saga.Deadline) {
+			logEntry.WithField("state", saga.State).Warning("Payment saga timed out; compensating")
+			saga.Compensate(entity.SagaReasonTimeout, now)
+		} else if err := uc.step(ctx, logEntry, saga, now); err != nil {
+			logEntry.WithError(err).WithFields(logrus.Fields{
+				"state":    saga.State,
+				"attempts": saga.Attempts + 1,
+			}).Warning("Payment saga step failed; will retry")
+			saga.Retry(err, now.Add(uc.backoff(saga.Attempts)), now)
+			uc.save(ctx, logEntry, saga, from, lease)
+			return
+		}
+
+		// Keep the saga hidden from other runners while this one advances it
+		if !saga.State.IsTerminal() {
+			saga.NextAttemptAt = lease
+		}
+		if !uc.save(ctx, logEntry, saga, from, lease) {
+			return
+		}
+		logEntry = logEntry.WithField("payment_id", saga.PaymentID)
+		logEntry.WithField("state", saga.State).Debug("Payment saga advanced")
+	}
+
+	if saga.State == entity.SagaCompensated {
+		logEntry.WithField("failure_reason", saga.FailureReason).Warning("Payment saga compensated")
+		return
+	}
+	logEntry.Info("Payment saga completed")
+}
+
+// step performs the saga's next action. Outcomes that retrying cannot change,
+// such as a declined card, move the saga on; the error it returns is transient.
+func (uc *PaymentSagaCase) step(ctx context.Context, logEntry *logrus.Entry, saga *entity.PaymentSaga, now time.Time) error {
+	switch saga.State {
+	case entity.SagaStarted:
+		err := uc.moveOrder(ctx, saga.OrderID, entity.Confirmed)
+		if stdErrors.Is(err, errors.ErrOrderInvalidTransition) || stdErrors.Is(err, errors.ErrOrderNotFound) {
+			saga.Compensate(entity.SagaReasonOrderNotPayable, now)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		saga.MoveTo(entity.SagaOrderConfirmed, now)
+
+	case entity.SagaOrderConfirmed:
+		// The payment service keeps one intent per order, so repeating this step
+		// after an interruption returns the intent the first attempt created
+		created, err := uc.payments.CreatePayment(ctx, saga.OrderID, saga.Amount)
+		if stdErrors.Is(err, payment.ErrRejected) {
+			saga.Compensate(entity.SagaReasonPaymentRejected, now)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		saga.PaymentID = created.ID
+		saga.MoveTo(entity.SagaPaymentCreated, now)
+
+	case entity.SagaPaymentCreated:
+		_, err := uc.payments.Authorize(ctx, saga.PaymentID, saga.PaymentToken)
+		switch {
+		case err == nil:
+			saga.MoveTo(entity.SagaPaymentAuthorized, now)
+		case stdErrors.Is(err, payment.ErrDeclined):
+			saga.Compensate(entity.SagaReasonDeclined, now)
+		case stdErrors.Is(err, payment.ErrRejected):
+			return uc.reconcile(ctx, saga, now)
+		default:
+			return err
+		}
+
+	case entity.SagaPaymentAuthorized:
+		_, err := uc.payments.Capture(ctx, saga.PaymentID)
+		switch {
+		case err == nil:
+			saga.MoveTo(entity.SagaPaymentCaptured, now)
+		case stdErrors.Is(err, payment.ErrRejected):
+			return uc.reconcile(ctx, saga, now)
+		default:
+			return err
+		}
+
+	case entity.SagaPaymentCaptured:
+		err := uc.moveOrder(ctx, saga.OrderID, entity.Paid)
+		if stdErrors.Is(err, errors.ErrOrderInvalidTransition) || stdErrors.Is(err, errors.ErrOrderNotFound) {
+			// The money is taken and cannot be given back by voiding; refunds are manual
+			logEntry.Error("Payment captured for an order that can no longer be paid; refund it manually")
+			saga.FailureReason = entity.SagaReasonOrderNotPayable
+		} else if err != nil {
+			return err
+		}
+		saga.MoveTo(entity.SagaCompleted, now)
+
+	case entity.SagaCompensating:
+		return uc.compensate(ctx, logEntry, saga, now)
+	}
+
+	return nil
+}
+
+// compensate releases the payment, if one was created, and cancels the order
+func (uc *PaymentSagaCase) compensate(ctx context.Context, logEntry *logrus.Entry, saga *entity.PaymentSaga, now time.Time) error {
+	if saga.PaymentID != "" {
+		_, err := uc.payments.Void(ctx, saga.PaymentID)
+		if stdErrors.Is(err, payment.ErrRejected) {
+			current, getErr := uc.payments.GetPayment(ctx, saga.PaymentID)
+			if getErr != nil && !stdErrors.Is(getErr, payment.ErrRejected) {
+				return getErr
+			}
+			// A capture whose response was lost went through after all: finish the order instead
+			if current != nil && current.Status == payment.StatusCaptured {
+				logEntry.Warning("Payment was captured before compensation; completing the order instead")
+				saga.FailureReason = ""
+				saga.MoveTo(entity.SagaPaymentCaptured, now)
+				return nil
+			}
+			// Otherwise the payment is already voided or declined, or never existed
+		} else if err != nil {
+			return err
+		}
+	}
+
+	err := uc.moveOrder(ctx, saga.OrderID, entity.Cancelled)
+	if stdErrors.Is(err, errors.ErrOrderInvalidTransition) || stdErrors.Is(err, errors.ErrOrderNotFound) {
+		logEntry.WithError(err).Warning("Order could not be cancelled during compensation")
+	} else if err != nil {
+		return err
+	}
+
+	saga.MoveTo(entity.SagaCompensated, now)
+	return nil
+}
+
+// reconcile resolves a payment call the payment service rejected, which
+// usually means a retried step already took effect, by reading the payment's status
+func (uc *PaymentSagaCase) reconcile(ctx context.Context, saga *entity.PaymentSaga, now time.Time) error {
+	current, err := uc.payments.GetPayment(ctx, saga.PaymentID)
+	if stdErrors.Is(err, payment.ErrRejected) {
+		saga.Compensate(entity.SagaReasonPaymentRejected, now)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case current.Status == payment.StatusCaptured:
+		saga.MoveTo(entity.SagaPaymentCaptured, now)
+	case current.Status == payment.StatusAuthorized && saga.State == entity.SagaPaymentCreated:
+		saga.MoveTo(entity.SagaPaymentAuthorized, now)
+	case current.Status == payment.StatusDeclined:
+		saga.Compensate(entity.SagaReasonDeclined, now)
+	default:
+		saga.Compensate(entity.SagaReasonPaymentRejected, now)
+	}
+	return nil
+}
+
+// moveOrder transitions the order to status, treating an order that is already
+// there as success so that a retried step is harmless. The saga acts as the
+// system principal, whoever placed the order.
+func (uc *PaymentSagaCase) moveOrder(ctx context.Context, orderID string, status entity.OrderStatus) error {
+	ctx = policy.WithPrincipal(ctx, policy.System)
+	_, err := uc.updateStatus.Execute(ctx, orderID, status)
+	if stdErrors.Is(err, errors.ErrOrderInvalidTransition) {
+		if order, findErr := uc.orders.FindByID(ctx, orderID); findErr == nil && order.Status == status {
+			return nil
+		}
+	}
+	return err
+}
+
+// backoff returns the delay before retrying a step that has already failed attempts times
+func (uc *PaymentSagaCase) backoff(attempts int) time.Duration {
+	delay := uc.config.RetryBackoff
+	for i := 0; i < attempts && delay < uc.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > uc.config.MaxBackoff {
+		delay = uc.config.MaxBackoff
+	}
+	return delay
+}
+
+// save persists the saga moved on from state from under lease, reporting
+// whether it succeeded. An unsaved step is repeated once the saga's lease
+// expires, so every step tolerates being retried.
+func (uc *PaymentSagaCase) save(ctx context.Context, logEntry *logrus.Entry, saga *entity.PaymentSaga, from entity.SagaState, lease time.Time) bool {
+	err := uc.sagas.Update(ctx, saga, from, lease)
+	if stdErrors.Is(err, sql.ErrNoRows) {
+		logEntry.WithField("state", from).Warning("Payment saga lease lost to another runner; leaving the saga to it")
+		return false
+	}
+	if err != nil {
+		logEntry.WithError(err).WithField("state", saga.State).Error("Failed to persist payment saga")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,345 @@
+package usecase_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/payment"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+// fakePayments mimics the payment service's payment intent lifecycle
+type fakePayments struct {
+	payments map[string]*payment.Payment
+	// captureErr fails captures; with captureApplies the capture still takes effect, as if its response was lost
+	captureErr     error
+	captureApplies bool
+	mutex          sync.Mutex
+}
+
+func newFakePayments() *fakePayments {
+	return &fakePayments{payments: make(map[string]*payment.Payment)}
+}
+
+func (f *fakePayments) CreatePayment(ctx context.Context, orderID string, amount money.Money) (*payment.Payment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	// Like the payment service, an order has a single intent
+	for _, existing := range f.payments {
+		if existing.OrderID == orderID {
+			copied := *existing
+			return &copied, nil
+		}
+	}
+	created := &payment.Payment{ID: fmt.Sprintf("payment-%d", len(f.payments)+1), OrderID: orderID, Amount: amount, Status: payment.StatusPending}
+	f.payments[created.ID] = created
+	copied := *created
+	return &copied, nil
+}
+
+func (f *fakePayments) GetPayment(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.payments[paymentID]
+	if !ok {
+		return nil, payment.ErrRejected
+	}
+	copied := *current
+	return &copied, nil
+}
+
+func (f *fakePayments) Authorize(ctx context.Context, paymentID, cardToken string) (*payment.Payment, error) {
+	return f.transition(paymentID, payment.StatusPending, func(current *payment.Payment) error {
+		if cardToken == "tok_declined" {
+			current.Status = payment.StatusDeclined
+			return payment.ErrDeclined
+		}
+		current.Status = payment.StatusAuthorized
+		return nil
+	})
+}
+
+func (f *fakePayments) Capture(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	return f.transition(paymentID, payment.StatusAuthorized, func(current *payment.Payment) error {
+		if f.captureErr != nil && !f.captureApplies {
+			return f.captureErr
+		}
+		current.Status = payment.StatusCaptured
+		return f.captureErr
+	})
+}
+
+func (f *fakePayments) Void(ctx context.Context, paymentID string) (*payment.Payment, error) {
+	f.mutex.Lock()
+	status := f.payments[paymentID].Status
+	f.mutex.Unlock()
+	if status == payment.StatusPending {
+		return f.transition(paymentID, payment.StatusPending, voidPayment)
+	}
+	return f.transition(paymentID, payment.StatusAuthorized, voidPayment)
+}
+
+func voidPayment(current *payment.Payment) error {
+	current.Status = payment.StatusVoided
+	return nil
+}
+
+func (f *fakePayments) transition(paymentID, from string, apply func(*payment.Payment) error) (*payment.Payment, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	current, ok := f.payments[paymentID]
+	if !ok || current.Status != from {
+		return nil, payment.ErrRejected
+	}
+	if err := apply(current); err != nil {
+		return nil, err
+	}
+	copied := *current
+	return &copied, nil
+}
+
+func (f *fakePayments) count() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return len(f.payments)
+}
+
+// stolenSagaRepository lets another runner claim every saga as soon as it is
+// handed out, as when a slow runner outlives its lease
+type stolenSagaRepository struct {
+	*memory.PaymentSagaMemoryRepository
+}
+
+func (r *stolenSagaRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*entity.PaymentSaga, error) {
+	claimed, err := r.PaymentSagaMemoryRepository.ClaimDue(ctx, now, leaseUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	r.PaymentSagaMemoryRepository.ClaimDue(ctx, leaseUntil, leaseUntil.Add(time.Minute), limit)
+	return claimed, nil
+}
+
+type sagaFixture struct {
+	orders   *memory.OrderMemoryRepository
+	sagas    *memory.PaymentSagaMemoryRepository
+	payments *fakePayments
+	uc       *usecase.PaymentSagaCase
+	status   entity.OrderStatus
+}
+
+func newSagaFixture(t *testing.T, status entity.OrderStatus) *sagaFixture {
+	t.Helper()
+	orders := memory.NewOrderMemoryRepository()
+	f := &sagaFixture{
+		orders:   orders,
+		sagas:    orders.Sagas(),
+		payments: newFakePayments(),
+		status:   status,
+	}
+	f.restart()
+	return f
+}
+
+// restart replaces the use case as a new process would, keeping stored state
+func (f *sagaFixture) restart() {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	f.uc = usecase.NewPaymentSagaCase(f.sagas, f.orders, f.payments, usecase.PaymentSagaConfig{
+		Timeout: time.Minute,
+	}, testLogger)
+}
+
+// start saves order-1 in the fixture's status together with a saga charging it with token
+func (f *sagaFixture) start(t *testing.T, token string) {
+	t.Helper()
+	now := time.Now()
+	createdAt := now.Add(-time.Minute)
+	order := &entity.Order{
+		ID:        "order-1",
+		UserID:    "user-1",
+		Items:     []entity.OrderItem{{ProductID: "product-1", Quantity: 2, Price: money.Money{Amount: 2999, Currency: "USD"}}},
+		Status:    f.status,
+		Total:     money.Money{Amount: 5998, Currency: "USD"},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+	saga := entity.NewPaymentSaga(order.ID, token, order.Total, now.Add(time.Minute), now)
+	if err := f.orders.Save(context.Background(), order, saga); err != nil {
+		t.Fatalf("Expected no error saving the order, got %v", err)
+	}
+}
+
+func (f *sagaFixture) resume(t *testing.T) {
+	t.Helper()
+	if _, err := f.uc.ResumeDue(context.Background()); err != nil {
+		t.Fatalf("Expected no error resuming sagas, got %v", err)
+	}
+}
+
+func (f *sagaFixture) expect(t *testing.T, sagaState entity.SagaState, orderStatus entity.OrderStatus, paymentStatus string) *entity.PaymentSaga {
+	t.Helper()
+	saga, err := f.sagas.FindByOrderID(context.Background(), "order-1")
+	if err != nil {
+		t.Fatalf("Expected a stored saga, got %v", err)
+	}
+	if saga.State != sagaState {
+		t.Errorf("Expected saga state %s, got %s (last error %q)", sagaState, saga.State, saga.LastError)
+	}
+	order, _ := f.orders.FindByID(context.Background(), "order-1")
+	if order.Status != orderStatus {
+		t.Errorf("Expected order status %s, got %s", orderStatus, order.Status)
+	}
+	if paymentStatus != "" {
+		current, err := f.payments.GetPayment(context.Background(), saga.PaymentID)
+		if err != nil || current.Status != paymentStatus {
+			t.Errorf("Expected payment status %s, got %+v (%v)", paymentStatus, current, err)
+		}
+	}
+	return saga
+}
+
+// expire moves the saga's deadline into the past
+func (f *sagaFixture) expire(t *testing.T) {
+	t.Helper()
+	saga, _ := f.sagas.FindByOrderID(context.Background(), "order-1")
+	saga.Deadline = time.Now().Add(-time.Second)
+	if err := f.sagas.Update(context.Background(), saga, saga.State, saga.NextAttemptAt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestPaymentSagaCase(t *testing.T) {
+	t.Run("should charge the order and mark it paid", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.start(t, "tok_visa")
+		f.resume(t)
+
+		saga := f.expect(t, entity.SagaCompleted, entity.Paid, payment.StatusCaptured)
+		if saga.FailureReason != "" {
+			t.Errorf("Expected no failure reason, got %q", saga.FailureReason)
+		}
+		if saga.PaymentToken != "" {
+			t.Errorf("Expected the payment token to be dropped, got %q", saga.PaymentToken)
+		}
+
+		pending, _ := f.orders.Outbox().Pending(context.Background(), 10)
+		if len(pending) != 2 {
+			t.Fatalf("Expected confirmed and paid events, got %d events", len(pending))
+		}
+	})
+
+	t.Run("should cancel the order when the card is declined", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.start(t, "tok_declined")
+		f.resume(t)
+
+		saga := f.expect(t, entity.SagaCompensated, entity.Cancelled, payment.StatusDeclined)
+		if saga.FailureReason != entity.SagaReasonDeclined {
+			t.Errorf("Expected failure reason %s, got %q", entity.SagaReasonDeclined, saga.FailureReason)
+		}
+	})
+
+	t.Run("should resume a failed step after a restart", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.payments.captureErr = stdErrors.New("payment service returned 503")
+		f.start(t, "tok_visa")
+		f.resume(t)
+
+		saga := f.expect(t, entity.SagaPaymentAuthorized, entity.Confirmed, payment.StatusAuthorized)
+		if saga.Attempts != 1 || saga.LastError == "" {
+			t.Errorf("Expected one recorded failed attempt, got %d (%q)", saga.Attempts, saga.LastError)
+		}
+
+		f.payments.captureErr = nil
+		f.restart()
+		f.resume(t)
+
+		f.expect(t, entity.SagaCompleted, entity.Paid, payment.StatusCaptured)
+		if f.payments.count() != 1 {
+			t.Errorf("Expected a single payment intent, got %d", f.payments.count())
+		}
+	})
+
+	t.Run("should void the authorization and cancel the order on timeout", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.payments.captureErr = stdErrors.New("payment service returned 503")
+		f.start(t, "tok_visa")
+		f.resume(t)
+
+		f.expire(t)
+		f.resume(t)
+
+		saga := f.expect(t, entity.SagaCompensated, entity.Cancelled, payment.StatusVoided)
+		if saga.FailureReason != entity.SagaReasonTimeout {
+			t.Errorf("Expected failure reason %s, got %q", entity.SagaReasonTimeout, saga.FailureReason)
+		}
+	})
+
+	t.Run("should complete the order when a timed out capture went through", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.payments.captureErr = stdErrors.New("context deadline exceeded")
+		f.payments.captureApplies = true
+		f.start(t, "tok_visa")
+		f.resume(t)
+
+		f.expire(t)
+		f.resume(t)
+
+		f.expect(t, entity.SagaCompleted, entity.Paid, payment.StatusCaptured)
+	})
+
+	t.Run("should not charge an order that was cancelled first", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Cancelled)
+		f.start(t, "tok_visa")
+		f.resume(t)
+
+		saga := f.expect(t, entity.SagaCompensated, entity.Cancelled, "")
+		if saga.FailureReason != entity.SagaReasonOrderNotPayable || saga.PaymentID != "" {
+			t.Errorf("Expected an uncharged saga for an unpayable order, got %+v", saga)
+		}
+		if f.payments.count() != 0 {
+			t.Errorf("Expected no payment intents, got %d", f.payments.count())
+		}
+	})
+
+	t.Run("should charge once when resumed again", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.start(t, "tok_visa")
+		f.resume(t)
+		f.resume(t)
+
+		f.expect(t, entity.SagaCompleted, entity.Paid, payment.StatusCaptured)
+		if f.payments.count() != 1 {
+			t.Errorf("Expected a single payment intent, got %d", f.payments.count())
+		}
+	})
+
+	t.Run("should stop advancing a saga another runner took over", func(t *testing.T) {
+		f := newSagaFixture(t, entity.Pending)
+		f.start(t, "tok_visa")
+		testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+		uc := usecase.NewPaymentSagaCase(&stolenSagaRepository{f.sagas}, f.orders, f.payments, usecase.PaymentSagaConfig{
+			Timeout: time.Minute,
+		}, testLogger)
+
+		if _, err := uc.ResumeDue(context.Background()); err != nil {
+			t.Fatalf("Expected no error resuming sagas, got %v", err)
+		}
+
+		// The first step reached the order, but its saga was left to the new runner
+		saga := f.expect(t, entity.SagaStarted, entity.Confirmed, "")
+		if saga.PaymentToken == "" {
+			t.Error("Expected the saga to keep its payment token")
+		}
+		if f.payments.count() != 0 {
+			t.Errorf("Expected no payment intents, got %d", f.payments.count())
+		}
+	})
+}
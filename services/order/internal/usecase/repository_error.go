@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"context"
+	stdErrors "errors"
+
+	"github.com/robrt95x/godops/services/order/internal/errors"
+)
+
+// repositoryError maps a failed repository call to a catalog error. A call cut
+// short by the request deadline is reported as a timeout rather than a database fault.
+func repositoryError(ctx context.Context, err error) error {
+	if stdErrors.Is(err, context.DeadlineExceeded) || stdErrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.ErrSystemTimeout
+	}
+	return errors.ErrDatabaseQuery
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/policy"
+	"github.com/robrt95x/godops/services/order/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type UpdateOrderStatusCase struct {
+	repository repository.OrderRepository
+	logger     *logrus.Logger
+}
+
+func NewUpdateOrderStatusCase(repository repository.OrderRepository, logger *logrus.Logger) *UpdateOrderStatusCase {
+	return &UpdateOrderStatusCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *UpdateOrderStatusCase) Execute(ctx context.Context, id string, status entity.OrderStatus) (*entity.Order, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":      "UpdateOrderStatus",
+		"order_id":      id,
+		"target_status": status,
+	})
+
+	logEntry.Debug("Starting update order status use case")
+
+	if id == "" {
+		logEntry.Warning("Invalid order ID: empty string provided")
+		return nil, errors.ErrOrderInvalidID
+	}
+
+	order, err := uc.repository.FindByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Info("Order not found")
+			return nil, errors.ErrOrderNotFound
+		}
+		logEntry.WithError(err).Error("Failed to retrieve order from repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	logEntry = logEntry.WithField("current_status", order.Status)
+
+	// Customers may cancel their own orders; every other move is the back office's
+	action := policy.ActionChangeStatus
+	if status == entity.Cancelled {
+		action = policy.ActionCancelOrder
+	}
+	if err := policy.Authorize(ctx, action, order.UserID); err != nil {
+		logEntry.WithError(err).Warning("Update order status denied")
+		return nil, err
+	}
+
+	previous := order.Status
+	if err := order.TransitionTo(status, time.Now()); err != nil {
+		logEntry.Warning("Update order status failed: transition not allowed")
+		return nil, err
+	}
+
+	changedEvent, err := orderStatusChangedEvent(order, previous)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to build order status changed event")
+		return nil, errors.ErrSystemInternal
+	}
+
+	if err := uc.repository.Update(ctx, order, previous, changedEvent); err != nil {
+		if err == sql.ErrNoRows {
+			// Another request moved the order since it was read
+			logEntry.Warning("Update order status failed: order changed concurrently")
+			return nil, errors.ErrOrderInvalidTransition
+		}
+		logEntry.WithError(err).Error("Failed to update order in repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	logEntry.Info("Order status updated successfully")
+	return order, nil
+}
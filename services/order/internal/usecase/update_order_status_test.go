@@ -0,0 +1,165 @@
+package usecase_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/robrt95x/godops/pkg/events"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/order/internal/entity"
+	"github.com/robrt95x/godops/services/order/internal/errors"
+	"github.com/robrt95x/godops/services/order/internal/infra/memory"
+	"github.com/robrt95x/godops/services/order/internal/usecase"
+)
+
+// staleOrderRepository reads every order as still pending, as a request that
+// raced another transition of the same order does
+type staleOrderRepository struct {
+	*memory.OrderMemoryRepository
+}
+
+func (r *staleOrderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
+	order, err := r.OrderMemoryRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Status = entity.Pending
+	return order, nil
+}
+
+func TestUpdateOrderStatusCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	tests := []struct {
+		name        string
+		current     entity.OrderStatus
+		target      entity.OrderStatus
+		expectedErr error
+	}{
+		{"pending to confirmed", entity.Pending, entity.Confirmed, nil},
+		{"pending to cancelled", entity.Pending, entity.Cancelled, nil},
+		{"confirmed to paid", entity.Confirmed, entity.Paid, nil},
+		{"paid to shipped", entity.Paid, entity.Shipped, nil},
+		{"shipped to delivered", entity.Shipped, entity.Delivered, nil},
+		{"delivered to refunded", entity.Delivered, entity.Refunded, nil},
+		{"pending to shipped", entity.Pending, entity.Shipped, errors.ErrOrderInvalidTransition},
+		{"shipped to cancelled", entity.Shipped, entity.Cancelled, errors.ErrOrderInvalidTransition},
+		{"cancelled to confirmed", entity.Cancelled, entity.Confirmed, errors.ErrOrderInvalidTransition},
+		{"completed to refunded", entity.Completed, entity.Refunded, errors.ErrOrderInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewOrderMemoryRepository()
+			uc := usecase.NewUpdateOrderStatusCase(repo, testLogger)
+
+			createdAt := time.Now().Add(-time.Hour)
+			repo.Save(context.Background(), &entity.Order{
+				ID:        "order-1",
+				UserID:    "user-1",
+				Items:     []entity.OrderItem{{ProductID: "product-1", Quantity: 1, Price: money.Money{Amount: 1000, Currency: "USD"}}},
+				Status:    tt.current,
+				Total:     money.Money{Amount: 1000, Currency: "USD"},
+				CreatedAt: createdAt,
+				UpdatedAt: createdAt,
+			}, nil)
+
+			result, err := uc.Execute(adminContext(), "order-1", tt.target)
+			if !stdErrors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			stored, _ := repo.FindByID(context.Background(), "order-1")
+			if tt.expectedErr != nil {
+				if result != nil {
+					t.Errorf("Expected nil result, got %v", result)
+				}
+				if stored.Status != tt.current {
+					t.Errorf("Expected stored status %s, got %s", tt.current, stored.Status)
+				}
+				if len(repo.Outbox().All()) != 0 {
+					t.Errorf("Expected no events, got %d", len(repo.Outbox().All()))
+				}
+				return
+			}
+
+			if result.Status != tt.target {
+				t.Errorf("Expected status %s, got %s", tt.target, result.Status)
+			}
+			if stored.Status != tt.target {
+				t.Errorf("Expected stored status %s, got %s", tt.target, stored.Status)
+			}
+			if !stored.UpdatedAt.After(createdAt) {
+				t.Errorf("Expected UpdatedAt to be refreshed")
+			}
+
+			pending, _ := repo.Outbox().Pending(context.Background(), 10)
+			if len(pending) != 1 || pending[0].Type != events.OrderStatusChangedEvent {
+				t.Fatalf("Expected one %s event, got %v", events.OrderStatusChangedEvent, pending)
+			}
+			var changed events.OrderStatusChanged
+			if err := pending[0].Decode(&changed); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if changed.PreviousStatus != string(tt.current) || changed.Status != string(tt.target) {
+				t.Errorf("Expected %s to %s, got %s to %s", tt.current, tt.target, changed.PreviousStatus, changed.Status)
+			}
+		})
+	}
+
+	t.Run("should name both statuses of a rejected transition", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		uc := usecase.NewUpdateOrderStatusCase(repo, testLogger)
+		repo.Save(context.Background(), &entity.Order{ID: "order-1", UserID: "user-1", Status: entity.Pending}, nil)
+
+		_, err := uc.Execute(adminContext(), "order-1", entity.Shipped)
+
+		info := errors.GetErrorInfo(fmt.Errorf("handler: %w", err))
+		if info.Code != errors.OrderInvalidTransition || info.Message != "Order cannot move from PENDING to SHIPPED" {
+			t.Errorf("Expected the transition in the error response, got %s %q", info.Code, info.Message)
+		}
+	})
+
+	t.Run("should reject a transition from a status the order has left", func(t *testing.T) {
+		repo := memory.NewOrderMemoryRepository()
+		uc := usecase.NewUpdateOrderStatusCase(&staleOrderRepository{repo}, testLogger)
+		repo.Save(context.Background(), &entity.Order{ID: "order-1", UserID: "user-1", Status: entity.Cancelled}, nil)
+
+		if _, err := uc.Execute(adminContext(), "order-1", entity.Confirmed); err != errors.ErrOrderInvalidTransition {
+			t.Fatalf("Expected ErrOrderInvalidTransition, got %v", err)
+		}
+
+		stored, _ := repo.FindByID(context.Background(), "order-1")
+		if stored.Status != entity.Cancelled || len(repo.Outbox().All()) != 0 {
+			t.Errorf("Expected the cancelled order to stay cancelled without events, got %s and %d events", stored.Status, len(repo.Outbox().All()))
+		}
+	})
+
+	t.Run("should return error when order not found", func(t *testing.T) {
+		uc := usecase.NewUpdateOrderStatusCase(memory.NewOrderMemoryRepository(), testLogger)
+
+		result, err := uc.Execute(adminContext(), "non-existent-order", entity.Cancelled)
+		if err != errors.ErrOrderNotFound {
+			t.Errorf("Expected ErrOrderNotFound, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected nil result, got %v", result)
+		}
+	})
+
+	t.Run("should return error for invalid order ID", func(t *testing.T) {
+		uc := usecase.NewUpdateOrderStatusCase(memory.NewOrderMemoryRepository(), testLogger)
+
+		result, err := uc.Execute(adminContext(), "", entity.Cancelled)
+		if err != errors.ErrOrderInvalidID {
+			t.Errorf("Expected ErrOrderInvalidID, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected nil result, got %v", result)
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package user
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrNotFound means the user service has no user with the requested ID
+	ErrNotFound = errors.New("user not found")
+	// ErrUnavailable means the user service could not answer, including while
+	// the client's circuit breaker is open
+	ErrUnavailable = errors.New("user service unavailable")
+	// ErrUnauthorized means the user service refused the credentials the
+	// lookup was made with
+	ErrUnauthorized = errors.New("user service refused the lookup's credentials")
+)
+
+// UserDirectory is the port to the user service
+type UserDirectory interface {
+	// FindByID returns the user, ErrNotFound, ErrUnauthorized or ErrUnavailable
+	FindByID(ctx context.Context, id string) (*User, error)
+}
+
+// User is the user service's view of a user
+type User struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
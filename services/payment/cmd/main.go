@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/robrt95x/godops/pkg/db"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/payment/internal/config"
+	httpDelivery "github.com/robrt95x/godops/services/payment/internal/delivery/http"
+	serviceErrors "github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/infra"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+)
+
+func main() {
+	// Load configuration
+	cfg := config.Load()
+
+	// Setup logger
+	loggerConfig := pkgLogger.Config{
+		Level:       cfg.LogLevel,
+		Format:      cfg.LogFormat,
+		Output:      cfg.LogOutput,
+		FilePath:    cfg.LogFilePath,
+		MaxSize:     cfg.LogMaxSize,
+		MaxBackups:  cfg.LogMaxBackups,
+		MaxAge:      cfg.LogMaxAge,
+		Compress:    cfg.LogCompress,
+		ServiceName: "payment-service",
+	}
+	appLogger := pkgLogger.Setup(loggerConfig)
+
+	// Fail fast on an error catalog entry without a status, gRPC code or log level
+	if err := serviceErrors.Catalog.Validate(); err != nil {
+		appLogger.WithError(err).Fatal("Invalid error catalog")
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrator, err := infra.NewRepositoryFactory(cfg).CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		os.Exit(db.RunMigrateCommand(os.Args[2:], migrator, "payment-service"))
+	}
+
+	appLogger.WithField("storage_type", cfg.StorageType).Info("Starting payment service")
+
+	// Create repository and gateway using factory
+	factory := infra.NewRepositoryFactory(cfg)
+	if cfg.IsPostgresStorage() && cfg.DBAutoMigrate {
+		migrator, err := factory.CreateMigrator()
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to create migrator")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			appLogger.WithError(err).Fatal("Failed to apply database migrations")
+		}
+		appLogger.WithField("applied", len(applied)).Info("Database schema is up to date")
+	}
+
+	repo, err := factory.CreatePaymentRepository()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to create repository")
+	}
+	gateway := factory.CreateGateway()
+
+	// Create use cases
+	createUC := usecase.NewCreatePaymentCase(repo, appLogger)
+	getUC := usecase.NewGetPaymentCase(repo, appLogger)
+	authorizeUC := usecase.NewAuthorizePaymentCase(repo, gateway, appLogger)
+	captureUC := usecase.NewCapturePaymentCase(repo, gateway, appLogger)
+	voidUC := usecase.NewVoidPaymentCase(repo, gateway, appLogger)
+	handler := httpDelivery.NewPaymentHandler(createUC, getUC, authorizeUC, captureUC, voidUC, appLogger)
+
+	// Setup router with middleware
+	r := chi.NewRouter()
+
+	// Add custom middleware
+	r.Use(pkgMiddleware.RequestID)
+	r.Use(pkgMiddleware.Logging(appLogger))
+	r.Use(pkgMiddleware.ErrorLogging(appLogger))
+	r.Use(pkgMiddleware.Timeout(cfg.RequestTimeout))
+	r.Use(middleware.Recoverer)
+
+	r.Route("/payments", func(r chi.Router) {
+		r.Post("/", handler.CreatePayment)
+		r.Get("/{id}", handler.GetPayment)
+		r.Post("/{id}/authorize", handler.AuthorizePayment)
+		r.Post("/{id}/capture", handler.CapturePayment)
+		r.Post("/{id}/void", handler.VoidPayment)
+	})
+
+	appLogger.WithField("port", cfg.ServerPort).Info("Starting HTTP server")
+	if err := http.ListenAndServe(":"+cfg.ServerPort, r); err != nil {
+		appLogger.WithError(err).Fatal("HTTP server failed")
+	}
+}
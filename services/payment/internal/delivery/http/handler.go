@@ -0,0 +1,163 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+type PaymentHandler struct {
+	CreateUC     *usecase.CreatePaymentCase
+	GetUC        *usecase.GetPaymentCase
+	AuthorizeUC  *usecase.AuthorizePaymentCase
+	CaptureUC    *usecase.CapturePaymentCase
+	VoidUC       *usecase.VoidPaymentCase
+	ErrorHandler *pkgErrors.HTTPErrorHandler
+	Logger       *logrus.Logger
+}
+
+func NewPaymentHandler(createUC *usecase.CreatePaymentCase, getUC *usecase.GetPaymentCase, authorizeUC *usecase.AuthorizePaymentCase, captureUC *usecase.CapturePaymentCase, voidUC *usecase.VoidPaymentCase, logger *logrus.Logger) *PaymentHandler {
+	errorCatalog := errors.NewPaymentErrorCatalog()
+	return &PaymentHandler{
+		CreateUC:     createUC,
+		GetUC:        getUC,
+		AuthorizeUC:  authorizeUC,
+		CaptureUC:    captureUC,
+		VoidUC:       voidUC,
+		ErrorHandler: pkgErrors.NewHTTPErrorHandler(logger, errorCatalog),
+		Logger:       logger,
+	}
+}
+
+type CreatePaymentRequest struct {
+	OrderID string      `json:"order_id"`
+	Amount  money.Money `json:"amount"`
+}
+
+type AuthorizePaymentRequest struct {
+	CardToken string `json:"card_token"`
+}
+
+func (h *PaymentHandler) CreatePayment(w http.ResponseWriter, r *http.Request) {
+	requestID := r.Header.Get("X-Request-ID")
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "CreatePayment",
+		"request_id": requestID,
+	})
+
+	logEntry.Debug("Processing create payment request")
+
+	var req CreatePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logEntry.WithError(err).Warning("Failed to decode request body")
+		h.ErrorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+
+	payment, err := h.CreateUC.Execute(r.Context(), req.OrderID, req.Amount)
+	if err != nil {
+		logEntry.WithError(err).Warning("Create payment use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.WithField("payment_id", payment.ID).Info("Payment created successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := r.Header.Get("X-Request-ID")
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "GetPayment",
+		"request_id": requestID,
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Processing get payment request")
+
+	payment, err := h.GetUC.Execute(r.Context(), id)
+	if err != nil {
+		logEntry.WithError(err).Warning("Get payment use case failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.Info("Payment retrieved successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
+
+func (h *PaymentHandler) AuthorizePayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	requestID := r.Header.Get("X-Request-ID")
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    "AuthorizePayment",
+		"request_id": requestID,
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Processing authorize payment request")
+
+	var req AuthorizePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logEntry.WithError(err).Warning("Failed to decode request body")
+		h.ErrorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+
+	payment, err := h.AuthorizeUC.Execute(r.Context(), id, req.CardToken)
+	h.respond(w, r, logEntry, payment, err)
+}
+
+func (h *PaymentHandler) CapturePayment(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "CapturePayment", h.CaptureUC.Execute)
+}
+
+func (h *PaymentHandler) VoidPayment(w http.ResponseWriter, r *http.Request) {
+	h.transition(w, r, "VoidPayment", h.VoidUC.Execute)
+}
+
+// transition is shared by the capture and void endpoints
+func (h *PaymentHandler) transition(w http.ResponseWriter, r *http.Request, handlerName string, execute func(ctx context.Context, id string) (*entity.PaymentIntent, error)) {
+	id := chi.URLParam(r, "id")
+	requestID := r.Header.Get("X-Request-ID")
+
+	logEntry := h.Logger.WithFields(logrus.Fields{
+		"handler":    handlerName,
+		"request_id": requestID,
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Processing payment transition request")
+
+	payment, err := execute(r.Context(), id)
+	h.respond(w, r, logEntry, payment, err)
+}
+
+func (h *PaymentHandler) respond(w http.ResponseWriter, r *http.Request, logEntry *logrus.Entry, payment *entity.PaymentIntent, err error) {
+	if err != nil {
+		logEntry.WithError(err).Warning("Payment transition failed")
+		h.ErrorHandler.HandleError(w, r, err)
+		return
+	}
+
+	logEntry.WithField("status", payment.Status).Info("Payment transition completed successfully")
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payment)
+}
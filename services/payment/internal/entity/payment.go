@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+)
+
+type PaymentStatus string
+
+const (
+	Pending    PaymentStatus = "PENDING"
+	Authorized PaymentStatus = "AUTHORIZED"
+	Captured   PaymentStatus = "CAPTURED"
+	Voided     PaymentStatus = "VOIDED"
+	Declined   PaymentStatus = "DECLINED"
+)
+
+// paymentTransitions lists the statuses each status may move to
+var paymentTransitions = map[PaymentStatus][]PaymentStatus{
+	Pending:    {Authorized, Declined, Voided},
+	Authorized: {Captured, Voided},
+}
+
+// IsTerminal reports whether no further transitions are allowed from s
+func (s PaymentStatus) IsTerminal() bool {
+	return len(paymentTransitions[s]) == 0
+}
+
+// CanTransitionTo reports whether the lifecycle allows moving from s to next
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	for _, allowed := range paymentTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentIntent tracks collecting Amount for an order: it is authorized against
+// a card, then either captured or voided
+type PaymentIntent struct {
+	ID              string        `json:"id"`
+	OrderID         string        `json:"order_id"`
+	Amount          money.Money   `json:"amount"`
+	Status          PaymentStatus `json:"status"`
+	AuthorizationID string        `json:"authorization_id,omitempty"`
+	DeclineReason   string        `json:"decline_reason,omitempty"`
+	CreatedAt       time.Time     `json:"created_at"`
+	UpdatedAt       time.Time     `json:"updated_at"`
+}
+
+// TransitionTo moves the intent to next, rejecting moves the lifecycle does not allow
+func (p *PaymentIntent) TransitionTo(next PaymentStatus, at time.Time) error {
+	if !p.Status.CanTransitionTo(next) {
+		return errors.ErrPaymentInvalidTransition
+	}
+	p.Status = next
+	p.UpdatedAt = at
+	return nil
+}
@@ -0,0 +1,111 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+// Error codes for standardized API responses
+const (
+	// Payment related errors
+	PaymentNotFound           = "PAYMENT_NOT_FOUND"
+	PaymentAlreadyExists      = "PAYMENT_ALREADY_EXISTS"
+	PaymentInvalidID          = "PAYMENT_INVALID_ID"
+	PaymentInvalidTransition  = "PAYMENT_INVALID_TRANSITION"
+	PaymentDeclined           = "PAYMENT_DECLINED"
+	PaymentGatewayUnavailable = "PAYMENT_GATEWAY_UNAVAILABLE"
+
+	// Validation errors
+	ValidationMissingOrderID   = "VALIDATION_MISSING_ORDER_ID"
+	ValidationInvalidAmount    = "VALIDATION_INVALID_AMOUNT"
+	ValidationInvalidCurrency  = "VALIDATION_INVALID_CURRENCY"
+	ValidationMissingCardToken = "VALIDATION_MISSING_CARD_TOKEN"
+	ValidationInvalidRequest   = "VALIDATION_INVALID_REQUEST"
+
+	// Database errors
+	DatabaseConnectionError  = "DATABASE_CONNECTION_ERROR"
+	DatabaseQueryError       = "DATABASE_QUERY_ERROR"
+	DatabaseTransactionError = "DATABASE_TRANSACTION_ERROR"
+
+	// System errors
+	SystemInternalError      = "SYSTEM_INTERNAL_ERROR"
+	SystemServiceUnavailable = "SYSTEM_SERVICE_UNAVAILABLE"
+	SystemTimeout            = "SYSTEM_TIMEOUT"
+)
+
+// Domain errors that map to error codes
+var (
+	ErrPaymentNotFound           = errors.New("payment not found")
+	ErrPaymentAlreadyExists      = errors.New("order already has a payment for another amount")
+	ErrPaymentInvalidID          = errors.New("invalid payment ID")
+	ErrPaymentInvalidTransition  = errors.New("payment status transition not allowed")
+	ErrPaymentDeclined           = errors.New("payment declined by the gateway")
+	ErrPaymentGatewayUnavailable = errors.New("payment gateway unavailable")
+
+	ErrValidationMissingOrderID   = errors.New("order ID is required")
+	ErrValidationInvalidAmount    = errors.New("payment amount must be greater than zero")
+	ErrValidationInvalidCurrency  = errors.New("payment currency is not a supported ISO 4217 code")
+	ErrValidationMissingCardToken = errors.New("card token is required")
+	ErrValidationInvalidRequest   = errors.New("invalid request format")
+
+	ErrDatabaseConnection  = errors.New("database connection failed")
+	ErrDatabaseQuery       = errors.New("database query failed")
+	ErrDatabaseTransaction = errors.New("database transaction failed")
+
+	ErrSystemInternal           = errors.New("internal system error")
+	ErrSystemServiceUnavailable = errors.New("service temporarily unavailable")
+	ErrSystemTimeout            = errors.New("request timeout")
+)
+
+// Catalog resolves domain errors to API error responses; errors wrapped with
+// %w resolve like the error they wrap
+var Catalog = pkgErrors.NewRegistry(
+	pkgErrors.Entry{Code: SystemInternalError, Message: "An unexpected error occurred", Meta: pkgErrors.Internal},
+
+	pkgErrors.Entry{Err: ErrPaymentNotFound, Code: PaymentNotFound, Message: "The requested payment could not be found", Meta: pkgErrors.NotFound},
+	pkgErrors.Entry{Err: ErrPaymentAlreadyExists, Code: PaymentAlreadyExists, Message: "The order already has a payment for a different amount", Meta: pkgErrors.Conflict},
+	pkgErrors.Entry{Err: ErrPaymentInvalidID, Code: PaymentInvalidID, Message: "Invalid payment ID format", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrPaymentInvalidTransition, Code: PaymentInvalidTransition, Message: "Payment cannot perform this operation in its current status", Meta: pkgErrors.FailedPrecondition},
+	pkgErrors.Entry{Err: ErrPaymentDeclined, Code: PaymentDeclined, Message: "The payment was declined", Meta: pkgErrors.Meta{
+		Status: http.StatusPaymentRequired, GRPC: pkgErrors.GRPCFailedPrecondition, LogLevel: pkgErrors.LogInfo,
+	}},
+	pkgErrors.Entry{Err: ErrPaymentGatewayUnavailable, Code: PaymentGatewayUnavailable, Message: "The payment gateway could not process the request", Meta: pkgErrors.Meta{
+		Status: http.StatusBadGateway, GRPC: pkgErrors.GRPCUnavailable, Retryable: true, LogLevel: pkgErrors.LogError,
+	}},
+
+	pkgErrors.Entry{Err: ErrValidationMissingOrderID, Code: ValidationMissingOrderID, Message: "Order ID is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidAmount, Code: ValidationInvalidAmount, Message: "Payment amount must be greater than zero", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidCurrency, Code: ValidationInvalidCurrency, Message: "Payment currency must be a supported ISO 4217 code", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingCardToken, Code: ValidationMissingCardToken, Message: "Card token is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRequest, Code: ValidationInvalidRequest, Message: "Invalid request format", Meta: pkgErrors.BadRequest},
+
+	pkgErrors.Entry{Err: ErrDatabaseConnection, Code: DatabaseConnectionError, Message: "Database connection failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseQuery, Code: DatabaseQueryError, Message: "Database query failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseTransaction, Code: DatabaseTransactionError, Message: "Database transaction failed", Meta: pkgErrors.DatabaseFailure},
+
+	pkgErrors.Entry{Err: ErrSystemInternal, Code: SystemInternalError, Message: "An internal error occurred", Meta: pkgErrors.Internal},
+	pkgErrors.Entry{Err: ErrSystemServiceUnavailable, Code: SystemServiceUnavailable, Message: "Service is temporarily unavailable", Meta: pkgErrors.Unavailable},
+	pkgErrors.Entry{Err: ErrSystemTimeout, Code: SystemTimeout, Message: "Request timeout", Meta: pkgErrors.Timeout},
+)
+
+// GetErrorInfo returns the ErrorInfo for a given error
+func GetErrorInfo(err error) pkgErrors.ErrorInfo {
+	return Catalog.GetErrorInfo(err)
+}
+
+// IsValidationError checks if the error is a validation error
+func IsValidationError(err error) bool {
+	return Catalog.IsValidationError(err)
+}
+
+// IsDatabaseError checks if the error is a database error
+func IsDatabaseError(err error) bool {
+	return Catalog.IsDatabaseError(err)
+}
+
+// NewPaymentErrorCatalog returns the catalog the HTTP error handler reports errors with
+func NewPaymentErrorCatalog() pkgErrors.ErrorCatalog {
+	return Catalog
+}
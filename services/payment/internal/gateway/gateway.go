@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/pkg/money"
+)
+
+// Gateway is the port to the payment processor. Errors mean the processor could
+// not be reached or rejected the call; a declined card is not an error but an
+// Authorization with Approved false.
+type Gateway interface {
+	Authorize(ctx context.Context, request AuthorizeRequest) (*Authorization, error)
+	Capture(ctx context.Context, authorizationID string, amount money.Money) error
+	Void(ctx context.Context, authorizationID string) error
+}
+
+type AuthorizeRequest struct {
+	// PaymentID identifies the intent; gateways use it as an idempotency key
+	PaymentID string
+	CardToken string
+	Amount    money.Money
+}
+
+type Authorization struct {
+	ID            string
+	Approved      bool
+	DeclineReason string
+}
@@ -0,0 +1,93 @@
+package infra
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/lib/pq"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/services/payment/internal/config"
+	"github.com/robrt95x/godops/services/payment/internal/gateway"
+	"github.com/robrt95x/godops/services/payment/internal/infra/memory"
+	"github.com/robrt95x/godops/services/payment/internal/infra/mock"
+	"github.com/robrt95x/godops/services/payment/internal/infra/postgres"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+)
+
+type RepositoryFactory struct {
+	config *config.Config
+	db     *sql.DB
+}
+
+func NewRepositoryFactory(config *config.Config) *RepositoryFactory {
+	return &RepositoryFactory{config: config}
+}
+
+func (f *RepositoryFactory) CreatePaymentRepository() (repository.PaymentRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for payments")
+		return memory.NewPaymentMemoryRepository(), nil
+
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for payments")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return postgres.NewPaymentPostgresRepository(db), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+// CreateGateway returns the payment processor adapter; only the mock exists so far
+func (f *RepositoryFactory) CreateGateway() gateway.Gateway {
+	log.Printf("Using mock payment gateway declining %d card tokens", len(f.config.MockDeclinedTokens))
+	return mock.NewGateway(f.config.MockDeclinedTokens)
+}
+
+// CreateMigrator returns the schema migrator; only postgres storage has a schema
+func (f *RepositoryFactory) CreateMigrator() (*db.Migrator, error) {
+	if !f.config.IsPostgresStorage() {
+		return nil, fmt.Errorf("migrations require postgres storage, got: %s", f.config.StorageType)
+	}
+
+	conn, err := f.postgresConnection()
+	if err != nil {
+		return nil, err
+	}
+	return postgres.NewMigrator(conn)
+}
+
+// postgresConnection opens the shared connection pool on first use
+func (f *RepositoryFactory) postgresConnection() (*sql.DB, error) {
+	if f.db != nil {
+		return f.db, nil
+	}
+
+	db, err := f.createPostgresConnection()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection: %w", err)
+	}
+	f.db = db
+	return db, nil
+}
+
+func (f *RepositoryFactory) createPostgresConnection() (*sql.DB, error) {
+	db, err := sql.Open("postgres", f.config.GetDatabaseURL())
+	if err != nil {
+		return nil, err
+	}
+
+	// Test the connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	log.Printf("Connected to PostgreSQL at %s:%s", f.config.DBHost, f.config.DBPort)
+	return db, nil
+}
@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+)
+
+type PaymentMemoryRepository struct {
+	payments map[string]*entity.PaymentIntent
+	claims   map[string]time.Time // payment ID to the end of its claim
+	mutex    sync.RWMutex
+}
+
+func NewPaymentMemoryRepository() *PaymentMemoryRepository {
+	return &PaymentMemoryRepository{
+		payments: make(map[string]*entity.PaymentIntent),
+		claims:   make(map[string]time.Time),
+	}
+}
+
+func (r *PaymentMemoryRepository) Save(ctx context.Context, payment *entity.PaymentIntent) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for _, stored := range r.payments {
+		if stored.OrderID == payment.OrderID {
+			return false, nil
+		}
+	}
+
+	paymentCopy := *payment
+	r.payments[payment.ID] = &paymentCopy
+	delete(r.claims, payment.ID)
+	return true, nil
+}
+
+func (r *PaymentMemoryRepository) FindByID(ctx context.Context, id string) (*entity.PaymentIntent, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	payment, exists := r.payments[id]
+	if !exists {
+		return nil, sql.ErrNoRows
+	}
+
+	paymentCopy := *payment
+	return &paymentCopy, nil
+}
+
+func (r *PaymentMemoryRepository) FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentIntent, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, payment := range r.payments {
+		if payment.OrderID == orderID {
+			paymentCopy := *payment
+			return &paymentCopy, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (r *PaymentMemoryRepository) Claim(ctx context.Context, id string, status entity.PaymentStatus, now, until time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.payments[id]
+	if !exists || stored.Status != status {
+		return false, nil
+	}
+	if claimedUntil, claimed := r.claims[id]; claimed && claimedUntil.After(now) {
+		return false, nil
+	}
+
+	r.claims[id] = until
+	return true, nil
+}
+
+func (r *PaymentMemoryRepository) Release(ctx context.Context, id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.claims, id)
+	return nil
+}
+
+func (r *PaymentMemoryRepository) Update(ctx context.Context, payment *entity.PaymentIntent, from entity.PaymentStatus) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	stored, exists := r.payments[payment.ID]
+	if !exists || stored.Status != from {
+		return sql.ErrNoRows
+	}
+
+	paymentCopy := *payment
+	paymentCopy.OrderID = stored.OrderID
+	paymentCopy.Amount = stored.Amount
+	r.payments[payment.ID] = &paymentCopy
+	delete(r.claims, payment.ID)
+	return nil
+}
@@ -0,0 +1,112 @@
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/gateway"
+)
+
+// DefaultDeclineReason is reported for declined tokens configured without a reason
+const DefaultDeclineReason = "card_declined"
+
+var (
+	ErrUnknownAuthorization = errors.New("unknown authorization")
+	ErrAuthorizationClosed  = errors.New("authorization already captured or voided")
+	ErrAmountExceeded       = errors.New("capture amount exceeds the authorized amount")
+)
+
+type authorizationState string
+
+const (
+	authorizationOpen     authorizationState = "open"
+	authorizationCaptured authorizationState = "captured"
+	authorizationVoided   authorizationState = "voided"
+)
+
+type authorization struct {
+	amount money.Money
+	state  authorizationState
+}
+
+// Gateway is a deterministic in-process payment processor. It approves every
+// card token except the configured ones, and derives authorization IDs from the
+// payment ID, so repeating an authorization returns the same result.
+type Gateway struct {
+	declinedTokens map[string]string
+	authorizations map[string]*authorization
+	mutex          sync.Mutex
+}
+
+// NewGateway returns a gateway declining the tokens in declinedTokens with the
+// mapped reason
+func NewGateway(declinedTokens map[string]string) *Gateway {
+	declined := make(map[string]string, len(declinedTokens))
+	for token, reason := range declinedTokens {
+		if reason == "" {
+			reason = DefaultDeclineReason
+		}
+		declined[token] = reason
+	}
+
+	return &Gateway{
+		declinedTokens: declined,
+		authorizations: make(map[string]*authorization),
+	}
+}
+
+func (g *Gateway) Authorize(ctx context.Context, request gateway.AuthorizeRequest) (*gateway.Authorization, error) {
+	if reason, declined := g.declinedTokens[request.CardToken]; declined {
+		return &gateway.Authorization{DeclineReason: reason}, nil
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	id := "auth_" + request.PaymentID
+	if _, exists := g.authorizations[id]; !exists {
+		g.authorizations[id] = &authorization{amount: request.Amount, state: authorizationOpen}
+	}
+	return &gateway.Authorization{ID: id, Approved: true}, nil
+}
+
+func (g *Gateway) Capture(ctx context.Context, authorizationID string, amount money.Money) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	auth, err := g.openAuthorization(authorizationID)
+	if err != nil {
+		return err
+	}
+	if !amount.SameCurrency(auth.amount) || amount.Amount > auth.amount.Amount {
+		return fmt.Errorf("%w: %s of %s", ErrAmountExceeded, amount, auth.amount)
+	}
+	auth.state = authorizationCaptured
+	return nil
+}
+
+func (g *Gateway) Void(ctx context.Context, authorizationID string) error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	auth, err := g.openAuthorization(authorizationID)
+	if err != nil {
+		return err
+	}
+	auth.state = authorizationVoided
+	return nil
+}
+
+func (g *Gateway) openAuthorization(id string) (*authorization, error) {
+	auth, exists := g.authorizations[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownAuthorization, id)
+	}
+	if auth.state != authorizationOpen {
+		return nil, fmt.Errorf("%w: %s", ErrAuthorizationClosed, id)
+	}
+	return auth, nil
+}
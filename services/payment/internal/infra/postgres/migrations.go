@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/robrt95x/godops/pkg/db"
+)
+
+// migrationLockID is the advisory lock key for the payment schema
+const migrationLockID int64 = 7_281_002
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// NewMigrator returns a migrator for the payment service schema
+func NewMigrator(conn *sql.DB) (*db.Migrator, error) {
+	return db.NewMigratorFS(conn, migrationFiles, "migrations", db.MigratorConfig{
+		TableName: "payment_schema_migrations",
+		LockID:    migrationLockID,
+	})
+}
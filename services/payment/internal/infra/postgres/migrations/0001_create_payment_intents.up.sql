@@ -0,0 +1,13 @@
+CREATE TABLE payment_intents (
+    id TEXT PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    amount BIGINT NOT NULL,
+    currency TEXT NOT NULL,
+    status TEXT NOT NULL,
+    authorization_id TEXT NOT NULL DEFAULT '',
+    decline_reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX payment_intents_order_id_idx ON payment_intents (order_id);
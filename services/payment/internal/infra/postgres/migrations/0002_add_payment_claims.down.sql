@@ -0,0 +1 @@
+ALTER TABLE payment_intents DROP COLUMN claimed_until;
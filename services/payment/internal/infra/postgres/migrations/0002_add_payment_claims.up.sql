@@ -0,0 +1,3 @@
+-- A request moving a payment holds it until claimed_until, so concurrent
+-- requests never both call the gateway
+ALTER TABLE payment_intents ADD COLUMN claimed_until TIMESTAMPTZ;
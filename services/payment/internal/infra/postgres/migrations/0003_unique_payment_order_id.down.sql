@@ -0,0 +1,2 @@
+DROP INDEX payment_intents_order_id_idx;
+CREATE INDEX payment_intents_order_id_idx ON payment_intents (order_id);
@@ -0,0 +1,4 @@
+-- An order has a single payment intent, so a retried create returns the
+-- intent made by the first attempt instead of adding another
+DROP INDEX payment_intents_order_id_idx;
+CREATE UNIQUE INDEX payment_intents_order_id_idx ON payment_intents (order_id);
@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+)
+
+const paymentColumns = `id, order_id, amount, currency, status, authorization_id, decline_reason, created_at, updated_at`
+
+type PaymentPostgresRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentPostgresRepository(db *sql.DB) *PaymentPostgresRepository {
+	return &PaymentPostgresRepository{db: db}
+}
+
+func (r *PaymentPostgresRepository) Save(ctx context.Context, payment *entity.PaymentIntent) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO payment_intents (`+paymentColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (order_id) DO NOTHING`,
+		payment.ID,
+		payment.OrderID,
+		payment.Amount.Amount,
+		payment.Amount.Currency,
+		payment.Status,
+		payment.AuthorizationID,
+		payment.DeclineReason,
+		payment.CreatedAt,
+		payment.UpdatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *PaymentPostgresRepository) FindByID(ctx context.Context, id string) (*entity.PaymentIntent, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+paymentColumns+` FROM payment_intents WHERE id = $1`, id)
+	return scanPayment(row)
+}
+
+func (r *PaymentPostgresRepository) FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentIntent, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT `+paymentColumns+` FROM payment_intents WHERE order_id = $1`, orderID)
+	return scanPayment(row)
+}
+
+func scanPayment(row *sql.Row) (*entity.PaymentIntent, error) {
+	var payment entity.PaymentIntent
+
+	err := row.Scan(
+		&payment.ID,
+		&payment.OrderID,
+		&payment.Amount.Amount,
+		&payment.Amount.Currency,
+		&payment.Status,
+		&payment.AuthorizationID,
+		&payment.DeclineReason,
+		&payment.CreatedAt,
+		&payment.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *PaymentPostgresRepository) Claim(ctx context.Context, id string, status entity.PaymentStatus, now, until time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE payment_intents SET claimed_until = $4
+		WHERE id = $1 AND status = $2 AND (claimed_until IS NULL OR claimed_until <= $3)`,
+		id,
+		status,
+		now,
+		until,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+func (r *PaymentPostgresRepository) Release(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE payment_intents SET claimed_until = NULL WHERE id = $1`, id)
+	return err
+}
+
+func (r *PaymentPostgresRepository) Update(ctx context.Context, payment *entity.PaymentIntent, from entity.PaymentStatus) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE payment_intents SET status = $2, authorization_id = $3, decline_reason = $4, updated_at = $5, claimed_until = NULL
+		WHERE id = $1 AND status = $6`,
+		payment.ID,
+		payment.Status,
+		payment.AuthorizationID,
+		payment.DeclineReason,
+		payment.UpdatedAt,
+		from,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+)
+
+type PaymentRepository interface {
+	// Save stores payment unless one already exists for its order; it reports whether it was stored
+	Save(ctx context.Context, payment *entity.PaymentIntent) (bool, error)
+	FindByID(ctx context.Context, id string) (*entity.PaymentIntent, error)
+	FindByOrderID(ctx context.Context, orderID string) (*entity.PaymentIntent, error)
+	// Claim reserves the payment until until for a gateway call moving it out of
+	// status. It reports false when the payment has left status or another
+	// unexpired claim holds it, so concurrent requests never both reach the gateway.
+	Claim(ctx context.Context, id string, status entity.PaymentStatus, now, until time.Time) (bool, error)
+	// Release drops the claim on a payment whose gateway call failed
+	Release(ctx context.Context, id string) error
+	// Update persists status and gateway details and drops the claim; order ID
+	// and amount are immutable. It only applies while the stored payment is
+	// still in status from and returns sql.ErrNoRows otherwise.
+	Update(ctx context.Context, payment *entity.PaymentIntent, from entity.PaymentStatus) error
+}
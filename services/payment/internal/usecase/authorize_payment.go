@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/gateway"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type AuthorizePaymentCase struct {
+	repository repository.PaymentRepository
+	gateway    gateway.Gateway
+	logger     *logrus.Logger
+}
+
+func NewAuthorizePaymentCase(repository repository.PaymentRepository, gateway gateway.Gateway, logger *logrus.Logger) *AuthorizePaymentCase {
+	return &AuthorizePaymentCase{
+		repository: repository,
+		gateway:    gateway,
+		logger:     logger,
+	}
+}
+
+// Execute authorizes the payment amount against cardToken. A declined card
+// leaves the payment DECLINED and returns ErrPaymentDeclined.
+func (uc *AuthorizePaymentCase) Execute(ctx context.Context, id string, cardToken string) (*entity.PaymentIntent, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "AuthorizePayment",
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Starting authorize payment use case")
+
+	if cardToken == "" {
+		logEntry.Warning("Authorize payment failed: missing card token")
+		return nil, errors.ErrValidationMissingCardToken
+	}
+
+	payment, err := findPayment(ctx, uc.repository, id, logEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry = logEntry.WithField("current_status", payment.Status)
+
+	if !payment.Status.CanTransitionTo(entity.Authorized) {
+		logEntry.Warning("Authorize payment failed: transition not allowed")
+		return nil, errors.ErrPaymentInvalidTransition
+	}
+
+	if err := claimPayment(ctx, uc.repository, payment, logEntry); err != nil {
+		return nil, err
+	}
+
+	authorization, err := uc.gateway.Authorize(ctx, gateway.AuthorizeRequest{
+		PaymentID: payment.ID,
+		CardToken: cardToken,
+		Amount:    payment.Amount,
+	})
+	if err != nil {
+		logEntry.WithError(err).Error("Payment gateway authorization failed")
+		releasePayment(ctx, uc.repository, payment, logEntry)
+		return nil, gatewayError(ctx, err)
+	}
+
+	previous := payment.Status
+	if !authorization.Approved {
+		payment.TransitionTo(entity.Declined, time.Now())
+		payment.DeclineReason = authorization.DeclineReason
+		if err := updatePayment(ctx, uc.repository, payment, previous, logEntry); err != nil {
+			return nil, err
+		}
+		logEntry.WithField("decline_reason", authorization.DeclineReason).Info("Payment declined")
+		return nil, errors.ErrPaymentDeclined
+	}
+
+	payment.TransitionTo(entity.Authorized, time.Now())
+	payment.AuthorizationID = authorization.ID
+	if err := updatePayment(ctx, uc.repository, payment, previous, logEntry); err != nil {
+		return nil, err
+	}
+
+	logEntry.WithField("authorization_id", authorization.ID).Info("Payment authorized successfully")
+	return payment, nil
+}
@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/infra/memory"
+	"github.com/robrt95x/godops/services/payment/internal/infra/mock"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+)
+
+// savePayment stores a payment for order-1 in status and returns its ID
+func savePayment(t *testing.T, repo *memory.PaymentMemoryRepository, status entity.PaymentStatus, authorizationID string) string {
+	t.Helper()
+	createdAt := time.Now().Add(-time.Hour)
+	payment := &entity.PaymentIntent{
+		ID:              "payment-1",
+		OrderID:         "order-1",
+		Amount:          money.Money{Amount: 2500, Currency: "USD"},
+		Status:          status,
+		AuthorizationID: authorizationID,
+		CreatedAt:       createdAt,
+		UpdatedAt:       createdAt,
+	}
+	if _, err := repo.Save(context.Background(), payment); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return payment.ID
+}
+
+func TestAuthorizePaymentCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+	declined := map[string]string{"tok_declined": "", "tok_no_funds": "insufficient_funds"}
+
+	tests := []struct {
+		name           string
+		current        entity.PaymentStatus
+		cardToken      string
+		expectedErr    error
+		expectedStatus entity.PaymentStatus
+		expectedReason string
+	}{
+		{"approved card", entity.Pending, "tok_visa", nil, entity.Authorized, ""},
+		{"declined card", entity.Pending, "tok_declined", errors.ErrPaymentDeclined, entity.Declined, mock.DefaultDeclineReason},
+		{"declined with reason", entity.Pending, "tok_no_funds", errors.ErrPaymentDeclined, entity.Declined, "insufficient_funds"},
+		{"missing card token", entity.Pending, "", errors.ErrValidationMissingCardToken, entity.Pending, ""},
+		{"already authorized", entity.Authorized, "tok_visa", errors.ErrPaymentInvalidTransition, entity.Authorized, ""},
+		{"voided", entity.Voided, "tok_visa", errors.ErrPaymentInvalidTransition, entity.Voided, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewPaymentMemoryRepository()
+			uc := usecase.NewAuthorizePaymentCase(repo, mock.NewGateway(declined), testLogger)
+			id := savePayment(t, repo, tt.current, "")
+
+			payment, err := uc.Execute(context.Background(), id, tt.cardToken)
+			if err != tt.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			stored, _ := repo.FindByID(context.Background(), id)
+			if stored.Status != tt.expectedStatus {
+				t.Errorf("Expected stored status %s, got %s", tt.expectedStatus, stored.Status)
+			}
+			if stored.DeclineReason != tt.expectedReason {
+				t.Errorf("Expected decline reason %q, got %q", tt.expectedReason, stored.DeclineReason)
+			}
+			if tt.expectedErr == nil && (payment.AuthorizationID == "" || stored.AuthorizationID != payment.AuthorizationID) {
+				t.Errorf("Expected the authorization ID to be stored, got %q", stored.AuthorizationID)
+			}
+		})
+	}
+
+	t.Run("payment not found", func(t *testing.T) {
+		uc := usecase.NewAuthorizePaymentCase(memory.NewPaymentMemoryRepository(), mock.NewGateway(nil), testLogger)
+		if _, err := uc.Execute(context.Background(), "missing", "tok_visa"); err != errors.ErrPaymentNotFound {
+			t.Errorf("Expected %v, got %v", errors.ErrPaymentNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/gateway"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type CapturePaymentCase struct {
+	repository repository.PaymentRepository
+	gateway    gateway.Gateway
+	logger     *logrus.Logger
+}
+
+func NewCapturePaymentCase(repository repository.PaymentRepository, gateway gateway.Gateway, logger *logrus.Logger) *CapturePaymentCase {
+	return &CapturePaymentCase{
+		repository: repository,
+		gateway:    gateway,
+		logger:     logger,
+	}
+}
+
+// Execute captures the full authorized amount
+func (uc *CapturePaymentCase) Execute(ctx context.Context, id string) (*entity.PaymentIntent, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "CapturePayment",
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Starting capture payment use case")
+
+	payment, err := findPayment(ctx, uc.repository, id, logEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry = logEntry.WithField("current_status", payment.Status)
+
+	if !payment.Status.CanTransitionTo(entity.Captured) {
+		logEntry.Warning("Capture payment failed: transition not allowed")
+		return nil, errors.ErrPaymentInvalidTransition
+	}
+
+	if err := claimPayment(ctx, uc.repository, payment, logEntry); err != nil {
+		return nil, err
+	}
+
+	if err := uc.gateway.Capture(ctx, payment.AuthorizationID, payment.Amount); err != nil {
+		logEntry.WithError(err).Error("Payment gateway capture failed")
+		releasePayment(ctx, uc.repository, payment, logEntry)
+		return nil, gatewayError(ctx, err)
+	}
+
+	previous := payment.Status
+	payment.TransitionTo(entity.Captured, time.Now())
+	if err := updatePayment(ctx, uc.repository, payment, previous, logEntry); err != nil {
+		return nil, err
+	}
+
+	logEntry.Info("Payment captured successfully")
+	return payment, nil
+}
@@ -0,0 +1,163 @@
+package usecase_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/infra/memory"
+	"github.com/robrt95x/godops/services/payment/internal/infra/mock"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+)
+
+// countingGateway counts captures and holds each one briefly, so concurrent
+// requests overlap at the gateway
+type countingGateway struct {
+	*mock.Gateway
+	captures atomic.Int32
+}
+
+func (g *countingGateway) Capture(ctx context.Context, authorizationID string, amount money.Money) error {
+	g.captures.Add(1)
+	time.Sleep(10 * time.Millisecond)
+	return g.Gateway.Capture(ctx, authorizationID, amount)
+}
+
+func TestCaptureAndVoidPayment(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	tests := []struct {
+		name           string
+		authorize      bool
+		operation      string
+		expectedErr    error
+		expectedStatus entity.PaymentStatus
+	}{
+		{"capture authorized payment", true, "capture", nil, entity.Captured},
+		{"capture pending payment", false, "capture", errors.ErrPaymentInvalidTransition, entity.Pending},
+		{"void authorized payment", true, "void", nil, entity.Voided},
+		{"void pending payment", false, "void", nil, entity.Voided},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := memory.NewPaymentMemoryRepository()
+			gateway := mock.NewGateway(nil)
+			id := savePayment(t, repo, entity.Pending, "")
+
+			if tt.authorize {
+				if _, err := usecase.NewAuthorizePaymentCase(repo, gateway, testLogger).Execute(ctx, id, "tok_visa"); err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+			}
+
+			var err error
+			switch tt.operation {
+			case "capture":
+				_, err = usecase.NewCapturePaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+			case "void":
+				_, err = usecase.NewVoidPaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+			}
+			if err != tt.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+
+			stored, _ := repo.FindByID(ctx, id)
+			if stored.Status != tt.expectedStatus {
+				t.Errorf("Expected stored status %s, got %s", tt.expectedStatus, stored.Status)
+			}
+		})
+	}
+
+	t.Run("void after capture is rejected", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.NewPaymentMemoryRepository()
+		gateway := mock.NewGateway(nil)
+		id := savePayment(t, repo, entity.Pending, "")
+
+		usecase.NewAuthorizePaymentCase(repo, gateway, testLogger).Execute(ctx, id, "tok_visa")
+		usecase.NewCapturePaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+
+		if _, err := usecase.NewVoidPaymentCase(repo, gateway, testLogger).Execute(ctx, id); err != errors.ErrPaymentInvalidTransition {
+			t.Errorf("Expected %v, got %v", errors.ErrPaymentInvalidTransition, err)
+		}
+	})
+
+	t.Run("concurrent captures reach the gateway once", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.NewPaymentMemoryRepository()
+		gateway := &countingGateway{Gateway: mock.NewGateway(nil)}
+		id := savePayment(t, repo, entity.Pending, "")
+		usecase.NewAuthorizePaymentCase(repo, gateway, testLogger).Execute(ctx, id, "tok_visa")
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = usecase.NewCapturePaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+			}(i)
+		}
+		wg.Wait()
+
+		captured := 0
+		for _, err := range errs {
+			switch err {
+			case nil:
+				captured++
+			case errors.ErrPaymentInvalidTransition:
+			default:
+				t.Errorf("Expected %v, got %v", errors.ErrPaymentInvalidTransition, err)
+			}
+		}
+		if captured != 1 || gateway.captures.Load() != 1 {
+			t.Errorf("Expected one capture at the gateway, got %d succeeded and %d gateway calls", captured, gateway.captures.Load())
+		}
+	})
+
+	t.Run("gateway failure leaves the payment unchanged", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.NewPaymentMemoryRepository()
+		// The gateway has no record of this authorization, so capturing it fails
+		id := savePayment(t, repo, entity.Authorized, "auth_unknown")
+
+		_, err := usecase.NewCapturePaymentCase(repo, mock.NewGateway(nil), testLogger).Execute(ctx, id)
+		if err != errors.ErrPaymentGatewayUnavailable {
+			t.Fatalf("Expected %v, got %v", errors.ErrPaymentGatewayUnavailable, err)
+		}
+
+		stored, _ := repo.FindByID(ctx, id)
+		if stored.Status != entity.Authorized {
+			t.Errorf("Expected stored status %s, got %s", entity.Authorized, stored.Status)
+		}
+
+		// The failed request released its claim, so a retry reaches the gateway again
+		if _, err := usecase.NewCapturePaymentCase(repo, mock.NewGateway(nil), testLogger).Execute(ctx, id); err != errors.ErrPaymentGatewayUnavailable {
+			t.Errorf("Expected the retry to reach the gateway, got %v", err)
+		}
+	})
+
+	t.Run("an expired claim no longer holds the payment", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.NewPaymentMemoryRepository()
+		id := savePayment(t, repo, entity.Pending, "")
+
+		// A request that died mid-call leaves its claim behind until it expires
+		now := time.Now()
+		if claimed, _ := repo.Claim(ctx, id, entity.Pending, now.Add(-time.Minute), now.Add(-time.Second)); !claimed {
+			t.Fatal("Expected the payment to be claimed")
+		}
+
+		if _, err := usecase.NewVoidPaymentCase(repo, mock.NewGateway(nil), testLogger).Execute(ctx, id); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
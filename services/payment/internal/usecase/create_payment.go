@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type CreatePaymentCase struct {
+	repository repository.PaymentRepository
+	logger     *logrus.Logger
+}
+
+func NewCreatePaymentCase(repository repository.PaymentRepository, logger *logrus.Logger) *CreatePaymentCase {
+	return &CreatePaymentCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+// Execute creates a pending payment intent for the order. An order has a single
+// intent, so a retried request gets the one the first attempt created, and a
+// request for another amount is rejected.
+func (uc *CreatePaymentCase) Execute(ctx context.Context, orderID string, amount money.Money) (*entity.PaymentIntent, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case": "CreatePayment",
+		"order_id": orderID,
+		"amount":   amount.String(),
+	})
+
+	logEntry.Debug("Starting create payment use case")
+
+	if orderID == "" {
+		logEntry.Warning("Create payment failed: missing order ID")
+		return nil, errors.ErrValidationMissingOrderID
+	}
+	if !amount.IsPositive() {
+		logEntry.Warning("Create payment failed: invalid amount")
+		return nil, errors.ErrValidationInvalidAmount
+	}
+	if err := amount.Validate(); err != nil {
+		logEntry.WithError(err).Warning("Create payment failed: invalid currency")
+		return nil, errors.ErrValidationInvalidCurrency
+	}
+
+	now := time.Now()
+	payment := &entity.PaymentIntent{
+		ID:        uuid.New().String(),
+		OrderID:   orderID,
+		Amount:    amount,
+		Status:    entity.Pending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	stored, err := uc.repository.Save(ctx, payment)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to save payment to repository")
+		return nil, repositoryError(ctx, err)
+	}
+	if !stored {
+		return uc.existing(ctx, logEntry, orderID, amount)
+	}
+
+	logEntry.WithField("payment_id", payment.ID).Info("Payment created successfully")
+	return payment, nil
+}
+
+// existing returns the payment intent already created for the order, provided it is for amount
+func (uc *CreatePaymentCase) existing(ctx context.Context, logEntry *logrus.Entry, orderID string, amount money.Money) (*entity.PaymentIntent, error) {
+	payment, err := uc.repository.FindByOrderID(ctx, orderID)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to find the order's existing payment")
+		return nil, repositoryError(ctx, err)
+	}
+	if payment.Amount != amount {
+		logEntry.WithField("payment_id", payment.ID).Warning("Create payment failed: order already has a payment for another amount")
+		return nil, errors.ErrPaymentAlreadyExists
+	}
+
+	logEntry.WithField("payment_id", payment.ID).Info("Payment already exists for order; returning it")
+	return payment, nil
+}
@@ -0,0 +1,86 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/infra/memory"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+)
+
+func TestCreatePaymentCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	tests := []struct {
+		name        string
+		orderID     string
+		amount      money.Money
+		expectedErr error
+	}{
+		{"valid payment", "order-1", money.Money{Amount: 2500, Currency: "USD"}, nil},
+		{"missing order ID", "", money.Money{Amount: 2500, Currency: "USD"}, errors.ErrValidationMissingOrderID},
+		{"zero amount", "order-1", money.Money{Amount: 0, Currency: "USD"}, errors.ErrValidationInvalidAmount},
+		{"negative amount", "order-1", money.Money{Amount: -100, Currency: "USD"}, errors.ErrValidationInvalidAmount},
+		{"unknown currency", "order-1", money.Money{Amount: 2500, Currency: "XXX"}, errors.ErrValidationInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewPaymentMemoryRepository()
+			uc := usecase.NewCreatePaymentCase(repo, testLogger)
+
+			payment, err := uc.Execute(context.Background(), tt.orderID, tt.amount)
+			if err != tt.expectedErr {
+				t.Fatalf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if tt.expectedErr != nil {
+				return
+			}
+
+			if payment.ID == "" {
+				t.Error("Expected a payment ID")
+			}
+			if payment.Status != entity.Pending {
+				t.Errorf("Expected status %s, got %s", entity.Pending, payment.Status)
+			}
+
+			stored, err := repo.FindByID(context.Background(), payment.ID)
+			if err != nil {
+				t.Fatalf("Expected stored payment, got %v", err)
+			}
+			if stored.Amount != tt.amount || stored.OrderID != tt.orderID {
+				t.Errorf("Expected %s for %s, got %s for %s", tt.amount, tt.orderID, stored.Amount, stored.OrderID)
+			}
+		})
+	}
+
+	t.Run("retry returns the order's existing payment", func(t *testing.T) {
+		uc := usecase.NewCreatePaymentCase(memory.NewPaymentMemoryRepository(), testLogger)
+		amount := money.Money{Amount: 2500, Currency: "USD"}
+
+		first, err := uc.Execute(context.Background(), "order-1", amount)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		retried, err := uc.Execute(context.Background(), "order-1", amount)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if retried.ID != first.ID {
+			t.Errorf("Expected payment %s, got %s", first.ID, retried.ID)
+		}
+	})
+
+	t.Run("another amount for the same order is rejected", func(t *testing.T) {
+		uc := usecase.NewCreatePaymentCase(memory.NewPaymentMemoryRepository(), testLogger)
+
+		uc.Execute(context.Background(), "order-1", money.Money{Amount: 2500, Currency: "USD"})
+		if _, err := uc.Execute(context.Background(), "order-1", money.Money{Amount: 3000, Currency: "USD"}); err != errors.ErrPaymentAlreadyExists {
+			t.Errorf("Expected %v, got %v", errors.ErrPaymentAlreadyExists, err)
+		}
+	})
+}
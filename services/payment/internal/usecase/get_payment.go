@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type GetPaymentCase struct {
+	repository repository.PaymentRepository
+	logger     *logrus.Logger
+}
+
+func NewGetPaymentCase(repository repository.PaymentRepository, logger *logrus.Logger) *GetPaymentCase {
+	return &GetPaymentCase{
+		repository: repository,
+		logger:     logger,
+	}
+}
+
+func (uc *GetPaymentCase) Execute(ctx context.Context, id string) (*entity.PaymentIntent, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "GetPayment",
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Starting get payment use case")
+
+	payment, err := findPayment(ctx, uc.repository, id, logEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry.Info("Payment retrieved successfully")
+	return payment, nil
+}
+
+// findPayment loads a payment and maps repository failures to catalog errors
+func findPayment(ctx context.Context, repository repository.PaymentRepository, id string, logEntry *logrus.Entry) (*entity.PaymentIntent, error) {
+	if id == "" {
+		logEntry.Warning("Invalid payment ID: empty string provided")
+		return nil, errors.ErrPaymentInvalidID
+	}
+
+	payment, err := repository.FindByID(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			logEntry.Info("Payment not found")
+			return nil, errors.ErrPaymentNotFound
+		}
+		logEntry.WithError(err).Error("Failed to retrieve payment from repository")
+		return nil, repositoryError(ctx, err)
+	}
+
+	return payment, nil
+}
+
+// defaultClaimLease bounds a claim taken for a request without a deadline
+const defaultClaimLease = time.Minute
+
+// claimPayment reserves payment for the gateway call that moves it out of its
+// current status. The claim lasts until the request's deadline, so one left by
+// a request that died mid-call expires with it. A payment claimed by another
+// request, or one that has left the status, is an invalid transition.
+func claimPayment(ctx context.Context, repository repository.PaymentRepository, payment *entity.PaymentIntent, logEntry *logrus.Entry) error {
+	now := time.Now()
+	until := now.Add(defaultClaimLease)
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(until) {
+		until = deadline
+	}
+
+	claimed, err := repository.Claim(ctx, payment.ID, payment.Status, now, until)
+	if err != nil {
+		logEntry.WithError(err).Error("Failed to claim payment in repository")
+		return repositoryError(ctx, err)
+	}
+	if !claimed {
+		logEntry.Warning("Payment is being processed by another request")
+		return errors.ErrPaymentInvalidTransition
+	}
+	return nil
+}
+
+// releasePayment drops the claim on a payment whose gateway call failed, so it
+// can be retried right away; the claim expires on its own if this fails too
+func releasePayment(ctx context.Context, repository repository.PaymentRepository, payment *entity.PaymentIntent, logEntry *logrus.Entry) {
+	if err := repository.Release(context.WithoutCancel(ctx), payment.ID); err != nil {
+		logEntry.WithError(err).Warning("Failed to release payment claim")
+	}
+}
+
+// updatePayment persists a payment moved out of status from and maps
+// repository failures to catalog errors
+func updatePayment(ctx context.Context, repository repository.PaymentRepository, payment *entity.PaymentIntent, from entity.PaymentStatus, logEntry *logrus.Entry) error {
+	if err := repository.Update(ctx, payment, from); err != nil {
+		if err == sql.ErrNoRows {
+			// The payment was found before, so another request moved it since
+			logEntry.Warning("Payment changed concurrently")
+			return errors.ErrPaymentInvalidTransition
+		}
+		logEntry.WithError(err).Error("Failed to update payment in repository")
+		return repositoryError(ctx, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/gateway"
+	"github.com/robrt95x/godops/services/payment/internal/repository"
+	"github.com/sirupsen/logrus"
+)
+
+type VoidPaymentCase struct {
+	repository repository.PaymentRepository
+	gateway    gateway.Gateway
+	logger     *logrus.Logger
+}
+
+func NewVoidPaymentCase(repository repository.PaymentRepository, gateway gateway.Gateway, logger *logrus.Logger) *VoidPaymentCase {
+	return &VoidPaymentCase{
+		repository: repository,
+		gateway:    gateway,
+		logger:     logger,
+	}
+}
+
+// Execute cancels a payment that has not been captured, releasing the
+// authorization at the gateway if there is one
+func (uc *VoidPaymentCase) Execute(ctx context.Context, id string) (*entity.PaymentIntent, error) {
+	logEntry := uc.logger.WithFields(logrus.Fields{
+		"use_case":   "VoidPayment",
+		"payment_id": id,
+	})
+
+	logEntry.Debug("Starting void payment use case")
+
+	payment, err := findPayment(ctx, uc.repository, id, logEntry)
+	if err != nil {
+		return nil, err
+	}
+
+	logEntry = logEntry.WithField("current_status", payment.Status)
+
+	if !payment.Status.CanTransitionTo(entity.Voided) {
+		logEntry.Warning("Void payment failed: transition not allowed")
+		return nil, errors.ErrPaymentInvalidTransition
+	}
+
+	if err := claimPayment(ctx, uc.repository, payment, logEntry); err != nil {
+		return nil, err
+	}
+
+	if payment.Status == entity.Authorized {
+		if err := uc.gateway.Void(ctx, payment.AuthorizationID); err != nil {
+			logEntry.WithError(err).Error("Payment gateway void failed")
+			releasePayment(ctx, uc.repository, payment, logEntry)
+			return nil, gatewayError(ctx, err)
+		}
+	}
+
+	previous := payment.Status
+	payment.TransitionTo(entity.Voided, time.Now())
+	if err := updatePayment(ctx, uc.repository, payment, previous, logEntry); err != nil {
+		return nil, err
+	}
+
+	logEntry.Info("Payment voided successfully")
+	return payment, nil
+}
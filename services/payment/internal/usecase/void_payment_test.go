@@ -0,0 +1,99 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/money"
+	"github.com/robrt95x/godops/services/payment/internal/entity"
+	"github.com/robrt95x/godops/services/payment/internal/errors"
+	"github.com/robrt95x/godops/services/payment/internal/infra/memory"
+	"github.com/robrt95x/godops/services/payment/internal/infra/mock"
+	"github.com/robrt95x/godops/services/payment/internal/usecase"
+)
+
+// voidRecordingGateway records the authorizations voided through it
+type voidRecordingGateway struct {
+	*mock.Gateway
+	voided []string
+}
+
+func (g *voidRecordingGateway) Void(ctx context.Context, authorizationID string) error {
+	g.voided = append(g.voided, authorizationID)
+	return g.Gateway.Void(ctx, authorizationID)
+}
+
+func TestVoidPaymentCase_Execute(t *testing.T) {
+	testLogger := pkgLogger.Setup(pkgLogger.NewDefaultConfig())
+
+	tests := []struct {
+		name           string
+		authorize      bool
+		expectedVoided bool
+	}{
+		{"pending payment is voided without the gateway", false, false},
+		{"authorized payment releases the authorization", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := memory.NewPaymentMemoryRepository()
+			gateway := &voidRecordingGateway{Gateway: mock.NewGateway(nil)}
+			id := savePayment(t, repo, entity.Pending, "")
+
+			var authorizationID string
+			if tt.authorize {
+				authorized, err := usecase.NewAuthorizePaymentCase(repo, gateway, testLogger).Execute(ctx, id, "tok_visa")
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				authorizationID = authorized.AuthorizationID
+			}
+
+			payment, err := usecase.NewVoidPaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if payment.Status != entity.Voided {
+				t.Errorf("Expected status %s, got %s", entity.Voided, payment.Status)
+			}
+
+			if !tt.expectedVoided {
+				if len(gateway.voided) != 0 {
+					t.Errorf("Expected no gateway void, got %v", gateway.voided)
+				}
+				return
+			}
+			if len(gateway.voided) != 1 || gateway.voided[0] != authorizationID {
+				t.Fatalf("Expected a gateway void of %s, got %v", authorizationID, gateway.voided)
+			}
+			// The released authorization can no longer be captured
+			if err := gateway.Capture(ctx, authorizationID, money.Money{Amount: 2500, Currency: "USD"}); err == nil {
+				t.Error("Expected capturing a voided authorization to fail")
+			}
+		})
+	}
+
+	t.Run("gateway failure leaves the payment authorized", func(t *testing.T) {
+		ctx := context.Background()
+		repo := memory.NewPaymentMemoryRepository()
+		gateway := &voidRecordingGateway{Gateway: mock.NewGateway(nil)}
+		// The gateway has no record of this authorization, so voiding it fails
+		id := savePayment(t, repo, entity.Authorized, "auth_unknown")
+
+		_, err := usecase.NewVoidPaymentCase(repo, gateway, testLogger).Execute(ctx, id)
+		if err != errors.ErrPaymentGatewayUnavailable {
+			t.Fatalf("Expected %v, got %v", errors.ErrPaymentGatewayUnavailable, err)
+		}
+		if len(gateway.voided) != 1 {
+			t.Errorf("Expected one gateway void, got %v", gateway.voided)
+		}
+
+		stored, _ := repo.FindByID(ctx, id)
+		if stored.Status != entity.Authorized {
+			t.Errorf("Expected stored status %s, got %s", entity.Authorized, stored.Status)
+		}
+	})
+}
@@ -1,37 +1,112 @@
 package main
 
 import (
+	"context"
+	"crypto/rsa"
+	"fmt"
 	"net/http"
+	"os"
 
 	"github.com/gorilla/mux"
-	"github.com/robrt95x/godops/pkg/logger"
+	"github.com/robrt95x/godops/pkg/db"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
 	"github.com/robrt95x/godops/pkg/middleware"
-	"github.com/robrt95x/godops/services/user/internal/adapter/repository"
+	"github.com/robrt95x/godops/services/user/internal/adapter/auth"
 	userHttp "github.com/robrt95x/godops/services/user/internal/adapter/http"
+	"github.com/robrt95x/godops/services/user/internal/adapter/repository"
 	"github.com/robrt95x/godops/services/user/internal/application/usecase"
 	"github.com/robrt95x/godops/services/user/internal/config"
 	"github.com/robrt95x/godops/services/user/internal/domain/service"
+	serviceErrors "github.com/robrt95x/godops/services/user/internal/errors"
 )
 
 func main() {
+	// Load configuration
+	cfg := config.Load()
+	
 	// Initialize logger
-	log := logger.New()
+	loggerConfig := pkgLogger.Config{
+		Level:       cfg.LogLevel,
+		Format:      cfg.LogFormat,
+		Output:      cfg.LogOutput,
+		FilePath:    cfg.LogFilePath,
+		MaxSize:     cfg.LogMaxSize,
+		MaxBackups:  cfg.LogMaxBackups,
+		MaxAge:      cfg.LogMaxAge,
+		Compress:    cfg.LogCompress,
+		ServiceName: "user-service",
+	}
+	log := pkgLogger.Setup(loggerConfig)
 	
-	// Initialize config
-	cfg := config.New()
+	// Fail fast on an error catalog entry without a status, gRPC code or log level
+	if err := serviceErrors.Catalog.Validate(); err != nil {
+		log.WithError(err).Fatal("Invalid error catalog")
+	}
+	
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrator, err := repository.NewRepositoryFactory(cfg).CreateMigrator()
+		if err != nil {
+			log.WithError(err).Fatal("Failed to create migrator")
+		}
+		os.Exit(db.RunMigrateCommand(os.Args[2:], migrator, "user-service"))
+	}
+	
+	log.WithField("storage_type", cfg.StorageType).Info("Starting user service")
 	
 	// Initialize repository
-	userRepo := repository.NewMemoryUserRepository()
+	factory := repository.NewRepositoryFactory(cfg)
+	if cfg.IsPostgresStorage() && cfg.DBAutoMigrate {
+		migrator, err := factory.CreateMigrator()
+		if err != nil {
+			log.WithError(err).Fatal("Failed to create migrator")
+		}
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			log.WithError(err).Fatal("Failed to apply database migrations")
+		}
+		log.WithField("applied", len(applied)).Info("Database schema is up to date")
+	}
+	
+	userRepo, err := factory.CreateUserRepository()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create repository")
+	}
 	
-	// Initialize domain service
-	userService := service.NewUserService(userRepo)
+	refreshTokenRepo, err := factory.CreateRefreshTokenRepository()
+	if err != nil {
+		log.WithError(err).Fatal("Failed to create refresh token repository")
+	}
+	
+	// Initialize authentication
+	signingKey, err := loadSigningKey(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("Failed to load JWT signing key")
+	}
+	if cfg.JWTPrivateKeyFile == "" {
+		log.Warn("JWT_PRIVATE_KEY_FILE is not set; using a generated signing key, tokens will not survive a restart")
+	}
+	issuer := auth.NewJWTIssuer(signingKey, auth.JWTConfig{
+		Issuer:         cfg.JWTIssuer,
+		Audience:       cfg.JWTAudience,
+		AccessTokenTTL: cfg.JWTAccessTokenTTL,
+	})
+	hasher := auth.NewBcryptHasher(cfg.BcryptCost)
+	
+	// Initialize domain services
+	userService := service.NewUserService(userRepo, hasher)
+	authService := service.NewAuthService(userRepo, refreshTokenRepo, hasher, issuer, cfg.JWTRefreshTokenTTL)
 	
 	// Initialize use cases
 	createUserUseCase := usecase.NewCreateUserUseCase(userService)
 	getUserUseCase := usecase.NewGetUserUseCase(userService)
+	updateUserUseCase := usecase.NewUpdateUserUseCase(userService)
+	deleteUserUseCase := usecase.NewDeleteUserUseCase(userService)
+	loginUseCase := usecase.NewLoginUseCase(authService)
+	refreshTokenUseCase := usecase.NewRefreshTokenUseCase(authService)
 	
-	// Initialize HTTP handler
-	userHandler := userHttp.NewUserHandler(createUserUseCase, getUserUseCase)
+	// Initialize HTTP handlers
+	userHandler := userHttp.NewUserHandler(createUserUseCase, getUserUseCase, updateUserUseCase, deleteUserUseCase, log)
+	authHandler := userHttp.NewAuthHandler(loginUseCase, refreshTokenUseCase, issuer, log)
 	
 	// Setup routes
 	r := mux.NewRouter()
@@ -39,11 +114,27 @@ func main() {
 	// Apply middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Logging(log))
+	r.Use(middleware.Timeout(cfg.RequestTimeout))
+	
+	// Tokens are verified with the service's own signing key; registration,
+	// login and the key set stay anonymous
+	authenticate := middleware.Authenticate(middleware.AuthConfig{
+		Keys:     middleware.StaticKey(&signingKey.PublicKey),
+		Issuer:   cfg.JWTIssuer,
+		Audience: cfg.JWTAudience,
+	}, log)
 	
 	// User routes
 	r.HandleFunc("/users", userHandler.CreateUser).Methods("POST")
-	r.HandleFunc("/users/{id}", userHandler.GetUser).Methods("GET")
-	r.HandleFunc("/users", userHandler.GetAllUsers).Methods("GET")
+	r.Handle("/users/{id}", authenticate(http.HandlerFunc(userHandler.GetUser))).Methods("GET")
+	r.Handle("/users/{id}", authenticate(http.HandlerFunc(userHandler.UpdateUser))).Methods("PATCH")
+	r.Handle("/users/{id}", authenticate(http.HandlerFunc(userHandler.DeleteUser))).Methods("DELETE")
+	r.Handle("/users", authenticate(http.HandlerFunc(userHandler.GetAllUsers))).Methods("GET")
+	
+	// Authentication routes
+	r.HandleFunc("/auth/login", authHandler.Login).Methods("POST")
+	r.HandleFunc("/auth/refresh", authHandler.Refresh).Methods("POST")
+	r.HandleFunc("/.well-known/jwks.json", authHandler.JWKS).Methods("GET")
 	
 	// Health check
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -51,8 +142,20 @@ func main() {
 		w.Write([]byte("OK"))
 	}).Methods("GET")
 	
-	log.Infof("User service starting on port %s", cfg.Port)
-	if err := http.ListenAndServe(":"+cfg.Port, r); err != nil {
+	log.Infof("User service starting on port %s", cfg.ServerPort)
+	if err := http.ListenAndServe(":"+cfg.ServerPort, r); err != nil {
 		log.Errorf("Failed to start server: %v", err)
 	}
 }
+
+// loadSigningKey reads the configured JWT signing key. Only outside production
+// may it be omitted, in which case a key is generated for this process.
+func loadSigningKey(cfg *config.Config) (*rsa.PrivateKey, error) {
+	if cfg.JWTPrivateKeyFile != "" {
+		return auth.LoadRSAPrivateKey(cfg.JWTPrivateKeyFile)
+	}
+	if cfg.IsProduction() {
+		return nil, fmt.Errorf("JWT_PRIVATE_KEY_FILE is required when APP_ENV=production")
+	}
+	return auth.GenerateRSAPrivateKey()
+}
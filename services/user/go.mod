@@ -1,17 +1,24 @@
 module github.com/robrt95x/godops/services/user
 
-go 1.21
+go 1.24.0
 
 require (
 	github.com/google/uuid v1.6.0
 	github.com/gorilla/mux v1.8.1
-	github.com/robrt95x/godops/pkg/errors v0.0.0-00010101000000-000000000000
-	github.com/robrt95x/godops/pkg/logger v0.0.0-00010101000000-000000000000
-	github.com/robrt95x/godops/pkg/middleware v0.0.0-00010101000000-000000000000
+	github.com/joho/godotenv v1.5.1
+	github.com/lib/pq v1.10.9
+	github.com/robrt95x/godops/pkg v0.0.0-00010101000000-000000000000
+	github.com/robrt95x/godops/pkg/db v0.0.0-00010101000000-000000000000
+	github.com/sirupsen/logrus v1.9.3
 )
 
-replace github.com/robrt95x/godops/pkg/errors => ../../pkg/errors
+require (
+	github.com/golang-jwt/jwt/v5 v5.2.2 // indirect
+	golang.org/x/crypto v0.39.0 // indirect
+	golang.org/x/sys v0.33.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
 
-replace github.com/robrt95x/godops/pkg/logger => ../../pkg/logger
+replace github.com/robrt95x/godops/pkg => ../../pkg
 
-replace github.com/robrt95x/godops/pkg/middleware => ../../pkg/middleware
+replace github.com/robrt95x/godops/pkg/db => ../../pkg/db
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	stdErrors "errors"
+
+	"github.com/robrt95x/godops/services/user/internal/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHasher implements port.PasswordHasher with bcrypt
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher returns a hasher using cost, or bcrypt.DefaultCost when cost is out of range
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Compare(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if stdErrors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return errors.ErrAuthInvalidCredentials
+	}
+	return err
+}
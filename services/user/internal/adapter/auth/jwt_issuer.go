@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+)
+
+// JWTConfig describes the access tokens a JWTIssuer signs
+type JWTConfig struct {
+	Issuer         string
+	Audience       string
+	AccessTokenTTL time.Duration
+}
+
+// AccessClaims are the claims of an access token; the subject is the user ID
+type AccessClaims struct {
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// JWTIssuer implements port.AccessTokenIssuer with RS256 signed JWTs and
+// publishes the matching public key as a JWK set
+type JWTIssuer struct {
+	key    *rsa.PrivateKey
+	jwk    JWK
+	config JWTConfig
+}
+
+func NewJWTIssuer(key *rsa.PrivateKey, config JWTConfig) *JWTIssuer {
+	return &JWTIssuer{
+		key:    key,
+		jwk:    newJWK(&key.PublicKey),
+		config: config,
+	}
+}
+
+func (i *JWTIssuer) Issue(user *entity.User, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(i.config.AccessTokenTTL)
+	claims := AccessClaims{
+		Email: user.Email,
+		Roles: []string{string(user.Role)},
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			Issuer:    i.config.Issuer,
+			Subject:   user.ID,
+			Audience:  jwt.ClaimStrings{i.config.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.jwk.Kid
+
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// JWKS returns the key set other services use to verify access tokens offline
+func (i *JWTIssuer) JWKS() JWKSet {
+	return JWKSet{Keys: []JWK{i.jwk}}
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	pkgLogger "github.com/robrt95x/godops/pkg/logger"
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+)
+
+func newTestIssuer(t *testing.T) (*JWTIssuer, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := GenerateRSAPrivateKey()
+	if err != nil {
+		t.Fatalf("Failed to generate signing key: %v", err)
+	}
+	return NewJWTIssuer(key, JWTConfig{Issuer: "user-service", Audience: "godops", AccessTokenTTL: 15 * time.Minute}), key
+}
+
+// publicKey decodes the RSA public key a JWK describes
+func publicKey(t *testing.T, jwk JWK) *rsa.PublicKey {
+	t.Helper()
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		t.Fatalf("Expected n to be unpadded base64url, got %q: %v", jwk.N, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		t.Fatalf("Expected e to be unpadded base64url, got %q: %v", jwk.E, err)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+}
+
+func TestJWTIssuer_JWKS(t *testing.T) {
+	issuer, key := newTestIssuer(t)
+
+	set := issuer.JWKS()
+	if len(set.Keys) != 1 {
+		t.Fatalf("Expected one key, got %d", len(set.Keys))
+	}
+	jwk := set.Keys[0]
+	if jwk.Kty != "RSA" || jwk.Use != "sig" || jwk.Alg != "RS256" {
+		t.Errorf("Expected an RS256 signing key, got %+v", jwk)
+	}
+	if jwk.E != "AQAB" {
+		t.Errorf("Expected exponent AQAB, got %s", jwk.E)
+	}
+	if !publicKey(t, jwk).Equal(&key.PublicKey) {
+		t.Error("Expected the key set to hold the signing key's public key")
+	}
+
+	t.Run("kid is the stable thumbprint of the key", func(t *testing.T) {
+		// RFC 7638: the required members in lexicographic order, without whitespace
+		canonical, _ := json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+		sum := sha256.Sum256(canonical)
+		if expected := base64.RawURLEncoding.EncodeToString(sum[:]); jwk.Kid != expected {
+			t.Errorf("Expected kid %s, got %s", expected, jwk.Kid)
+		}
+
+		again := NewJWTIssuer(key, JWTConfig{})
+		other, _ := newTestIssuer(t)
+
+		if again.JWKS().Keys[0].Kid != jwk.Kid {
+			t.Errorf("Expected the same key to keep kid %q, got %q", jwk.Kid, again.JWKS().Keys[0].Kid)
+		}
+		if other.JWKS().Keys[0].Kid == jwk.Kid {
+			t.Error("Expected another key to get another kid")
+		}
+	})
+
+	t.Run("serializes as a JWK set", func(t *testing.T) {
+		body, _ := json.Marshal(set)
+		var decoded map[string][]map[string]string
+		if err := json.Unmarshal(body, &decoded); err != nil {
+			t.Fatalf("Failed to decode key set: %v", err)
+		}
+		for _, member := range []string{"kty", "use", "alg", "kid", "n", "e"} {
+			if decoded["keys"][0][member] == "" {
+				t.Errorf("Expected member %s in %s", member, body)
+			}
+		}
+	})
+}
+
+func TestJWTIssuer_Issue(t *testing.T) {
+	issuer, _ := newTestIssuer(t)
+	user := &entity.User{ID: "user-1", Email: "jane@example.com", Role: entity.RoleAdmin}
+	now := time.Now()
+
+	signed, expiresAt, err := issuer.Issue(user, now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !expiresAt.Equal(now.Add(15 * time.Minute)) {
+		t.Errorf("Expected expiry %v, got %v", now.Add(15*time.Minute), expiresAt)
+	}
+
+	t.Run("names its key and carries the user", func(t *testing.T) {
+		jwk := issuer.JWKS().Keys[0]
+		var claims AccessClaims
+		token, err := jwt.ParseWithClaims(signed, &claims, func(token *jwt.Token) (any, error) {
+			if token.Header["kid"] != jwk.Kid {
+				t.Errorf("Expected kid %s, got %v", jwk.Kid, token.Header["kid"])
+			}
+			return publicKey(t, jwk), nil
+		}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("user-service"), jwt.WithAudience("godops"))
+		if err != nil || !token.Valid {
+			t.Fatalf("Expected a valid token, got %v", err)
+		}
+		if claims.Subject != "user-1" || claims.Email != "jane@example.com" || len(claims.Roles) != 1 || claims.Roles[0] != "admin" || claims.ID == "" {
+			t.Errorf("Expected the user's claims, got %+v", claims)
+		}
+	})
+
+	t.Run("is accepted by services verifying against the key set", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(issuer.JWKS())
+		}))
+		defer server.Close()
+
+		var subject string
+		var roles []string
+		authenticate := pkgMiddleware.Authenticate(pkgMiddleware.AuthConfig{
+			Keys:     pkgMiddleware.NewJWKSKeySource(server.URL, pkgMiddleware.JWKSConfig{}),
+			Issuer:   "user-service",
+			Audience: "godops",
+		}, pkgLogger.Setup(pkgLogger.NewDefaultConfig()))
+		handler := authenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			subject, roles = pkgMiddleware.GetSubject(r), pkgMiddleware.GetRoles(r)
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set("Authorization", "Bearer "+signed)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK || subject != "user-1" || len(roles) != 1 || roles[0] != "admin" {
+			t.Errorf("Expected user-1 as admin to be authenticated, got %d, %q and %v", rec.Code, subject, roles)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/user/internal/adapter/auth"
+	"github.com/robrt95x/godops/services/user/internal/application/usecase"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type AuthHandler struct {
+	loginUseCase        *usecase.LoginUseCase
+	refreshTokenUseCase *usecase.RefreshTokenUseCase
+	issuer              *auth.JWTIssuer
+	errorHandler        *pkgErrors.HTTPErrorHandler
+}
+
+func NewAuthHandler(loginUseCase *usecase.LoginUseCase, refreshTokenUseCase *usecase.RefreshTokenUseCase, issuer *auth.JWTIssuer, logger *logrus.Logger) *AuthHandler {
+	return &AuthHandler{
+		loginUseCase:        loginUseCase,
+		refreshTokenUseCase: refreshTokenUseCase,
+		issuer:              issuer,
+		errorHandler:        pkgErrors.NewHTTPErrorHandler(logger, errors.NewUserErrorCatalog()),
+	}
+}
+
+type LoginRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+	
+	tokens, err := h.loginUseCase.Execute(r.Context(), req.Email, req.Password)
+	if err != nil {
+		h.errorHandler.HandleError(w, r, err)
+		return
+	}
+	
+	writeTokens(w, tokens)
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	var req RefreshTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+	
+	tokens, err := h.refreshTokenUseCase.Execute(r.Context(), req.RefreshToken)
+	if err != nil {
+		h.errorHandler.HandleError(w, r, err)
+		return
+	}
+	
+	writeTokens(w, tokens)
+}
+
+// JWKS serves the public signing keys; verifiers may cache them briefly
+func (h *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	json.NewEncoder(w).Encode(h.issuer.JWKS())
+}
+
+// writeTokens sends a token pair, which must never be cached (RFC 6749 section 5.1)
+func writeTokens(w http.ResponseWriter, tokens any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(tokens)
+}
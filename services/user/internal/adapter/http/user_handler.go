@@ -5,37 +5,52 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
-	"github.com/robrt95x/godops/pkg/errors"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
 	"github.com/robrt95x/godops/services/user/internal/application/usecase"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+	"github.com/sirupsen/logrus"
 )
 
 type UserHandler struct {
 	createUserUseCase *usecase.CreateUserUseCase
 	getUserUseCase    *usecase.GetUserUseCase
+	updateUserUseCase *usecase.UpdateUserUseCase
+	deleteUserUseCase *usecase.DeleteUserUseCase
+	errorHandler      *pkgErrors.HTTPErrorHandler
 }
 
-func NewUserHandler(createUserUseCase *usecase.CreateUserUseCase, getUserUseCase *usecase.GetUserUseCase) *UserHandler {
+func NewUserHandler(createUserUseCase *usecase.CreateUserUseCase, getUserUseCase *usecase.GetUserUseCase, updateUserUseCase *usecase.UpdateUserUseCase, deleteUserUseCase *usecase.DeleteUserUseCase, logger *logrus.Logger) *UserHandler {
 	return &UserHandler{
 		createUserUseCase: createUserUseCase,
 		getUserUseCase:    getUserUseCase,
+		updateUserUseCase: updateUserUseCase,
+		deleteUserUseCase: deleteUserUseCase,
+		errorHandler:      pkgErrors.NewHTTPErrorHandler(logger, errors.NewUserErrorCatalog()),
 	}
 }
 
 type CreateUserRequest struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// UpdateUserRequest is a partial update; omitted fields keep their value
+type UpdateUserRequest struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
 }
 
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	var req CreateUserRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		errors.BadRequest(w, err)
+		h.errorHandler.HandleValidationError(w, r, "Invalid request body format")
 		return
 	}
 	
-	user, err := h.createUserUseCase.Execute(req.Name, req.Email)
+	user, err := h.createUserUseCase.Execute(r.Context(), req.Name, req.Email, req.Password)
 	if err != nil {
-		errors.BadRequest(w, err)
+		h.errorHandler.HandleError(w, r, err)
 		return
 	}
 	
@@ -48,9 +63,9 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 	
-	user, err := h.getUserUseCase.Execute(id)
+	user, err := h.getUserUseCase.Execute(r.Context(), id)
 	if err != nil {
-		errors.NotFound(w, err)
+		h.errorHandler.HandleError(w, r, err)
 		return
 	}
 	
@@ -59,12 +74,44 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *UserHandler) GetAllUsers(w http.ResponseWriter, r *http.Request) {
-	users, err := h.getUserUseCase.ExecuteGetAll()
+	users, err := h.getUserUseCase.ExecuteGetAll(r.Context())
 	if err != nil {
-		errors.InternalServerError(w, err)
+		h.errorHandler.HandleError(w, r, err)
 		return
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(users)
 }
+
+func (h *UserHandler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	var req UpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.errorHandler.HandleValidationError(w, r, "Invalid request body format")
+		return
+	}
+	
+	user, err := h.updateUserUseCase.Execute(r.Context(), id, req.Name, req.Email)
+	if err != nil {
+		h.errorHandler.HandleError(w, r, err)
+		return
+	}
+	
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *UserHandler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+	
+	if err := h.deleteUserUseCase.Execute(r.Context(), id); err != nil {
+		h.errorHandler.HandleError(w, r, err)
+		return
+	}
+	
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	_ "github.com/lib/pq"
+	"github.com/robrt95x/godops/pkg/db"
+	"github.com/robrt95x/godops/services/user/internal/config"
+	"github.com/robrt95x/godops/services/user/internal/domain/port"
+)
+
+type RepositoryFactory struct {
+	config *config.Config
+	db     *sql.DB
+}
+
+func NewRepositoryFactory(config *config.Config) *RepositoryFactory {
+	return &RepositoryFactory{config: config}
+}
+
+func (f *RepositoryFactory) CreateUserRepository() (port.UserRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		log.Println("Using in-memory storage for users")
+		return NewMemoryUserRepository(), nil
+
+	case f.config.IsPostgresStorage():
+		log.Println("Using PostgreSQL storage for users")
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgresUserRepository(db), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+func (f *RepositoryFactory) CreateRefreshTokenRepository() (port.RefreshTokenRepository, error) {
+	switch {
+	case f.config.IsMemoryStorage():
+		return NewMemoryRefreshTokenRepository(), nil
+
+	case f.config.IsPostgresStorage():
+		db, err := f.postgresConnection()
+		if err != nil {
+			return nil, err
+		}
+		return NewPostgresRefreshTokenRepository(db), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported storage type: %s", f.config.StorageType)
+	}
+}
+
+// CreateMigrator returns the schema migrator; only postgres storage has a schema
+func (f *RepositoryFactory) CreateMigrator() (*db.Migrator, error) {
+	if !f.config.IsPostgresStorage() {
+		return nil, fmt.Errorf("migrations require postgres storage, got: %s", f.config.StorageType)
+	}
+
+	conn, err := f.postgresConnection()
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(conn)
+}
+
+// postgresConnection opens the shared connection pool on first use
+func (f *RepositoryFactory) postgresConnection() (*sql.DB, error) {
+	if f.db != nil {
+		return f.db, nil
+	}
+
+	db, err := sql.Open("postgres", f.config.GetDatabaseURL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres connection: %w", err)
+	}
+
+	// Test the connection
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create postgres connection: failed to ping database: %w", err)
+	}
+
+	log.Printf("Connected to PostgreSQL at %s:%s", f.config.DBHost, f.config.DBPort)
+	f.db = db
+	return db, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+type MemoryRefreshTokenRepository struct {
+	tokens map[string]*entity.RefreshToken
+	mutex  sync.RWMutex
+}
+
+func NewMemoryRefreshTokenRepository() *MemoryRefreshTokenRepository {
+	return &MemoryRefreshTokenRepository{
+		tokens: make(map[string]*entity.RefreshToken),
+	}
+}
+
+func (r *MemoryRefreshTokenRepository) Save(ctx context.Context, token *entity.RefreshToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	tokenCopy := *token
+	r.tokens[token.ID] = &tokenCopy
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			tokenCopy := *token
+			return &tokenCopy, nil
+		}
+	}
+	
+	return nil, nil
+}
+
+func (r *MemoryRefreshTokenRepository) Rotate(ctx context.Context, currentID string, revokedAt time.Time, next *entity.RefreshToken) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	current, exists := r.tokens[currentID]
+	if !exists || current.IsRevoked() {
+		return errors.ErrAuthInvalidRefreshToken
+	}
+	
+	current.RevokedAt = &revokedAt
+	nextCopy := *next
+	r.tokens[next.ID] = &nextCopy
+	return nil
+}
+
+func (r *MemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && !token.IsRevoked() {
+			token.RevokedAt = &revokedAt
+		}
+	}
+	return nil
+}
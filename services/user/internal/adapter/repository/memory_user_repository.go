@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/errors"
 )
 
 type MemoryUserRepository struct {
@@ -17,47 +20,98 @@ func NewMemoryUserRepository() *MemoryUserRepository {
 	}
 }
 
-func (r *MemoryUserRepository) Save(user *entity.User) error {
+// Save stores user; an email another user already has fails with errors.ErrUserAlreadyExists
+func (r *MemoryUserRepository) Save(ctx context.Context, user *entity.User) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 	
-	r.users[user.ID] = user
+	if r.emailTaken(user) {
+		return errors.ErrUserAlreadyExists
+	}
+	
+	userCopy := *user
+	r.users[user.ID] = &userCopy
 	return nil
 }
 
-func (r *MemoryUserRepository) GetByID(id string) (*entity.User, error) {
+func (r *MemoryUserRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
 	user, exists := r.users[id]
-	if !exists {
+	if !exists || user.IsDeleted() {
 		return nil, nil
 	}
 	
-	return user, nil
+	userCopy := *user
+	return &userCopy, nil
 }
 
-func (r *MemoryUserRepository) GetByEmail(email string) (*entity.User, error) {
+func (r *MemoryUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
 	for _, user := range r.users {
-		if user.Email == email {
-			return user, nil
+		if user.Email == email && !user.IsDeleted() {
+			userCopy := *user
+			return &userCopy, nil
 		}
 	}
 	
 	return nil, nil
 }
 
-func (r *MemoryUserRepository) GetAll() ([]*entity.User, error) {
+func (r *MemoryUserRepository) GetAll(ctx context.Context) ([]*entity.User, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
 	
 	users := make([]*entity.User, 0, len(r.users))
 	for _, user := range r.users {
-		users = append(users, user)
+		if !user.IsDeleted() {
+			userCopy := *user
+			users = append(users, &userCopy)
+		}
 	}
 	
 	return users, nil
 }
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	stored, exists := r.users[user.ID]
+	if !exists || stored.IsDeleted() {
+		return errors.ErrUserNotFound
+	}
+	if r.emailTaken(user) {
+		return errors.ErrUserAlreadyExists
+	}
+	
+	stored.Name = user.Name
+	stored.Email = user.Email
+	return nil
+}
+
+func (r *MemoryUserRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	
+	stored, exists := r.users[id]
+	if !exists || stored.IsDeleted() {
+		return errors.ErrUserNotFound
+	}
+	
+	stored.DeletedAt = &deletedAt
+	return nil
+}
+
+// emailTaken reports whether another active user has user's email; callers hold the lock
+func (r *MemoryUserRepository) emailTaken(user *entity.User) bool {
+	for _, existing := range r.users {
+		if existing.Email == user.Email && existing.ID != user.ID && !existing.IsDeleted() {
+			return true
+		}
+	}
+	return false
+}
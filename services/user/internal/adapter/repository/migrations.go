@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"database/sql"
+	"embed"
+
+	"github.com/robrt95x/godops/pkg/db"
+)
+
+// migrationLockID is the advisory lock key for the user schema
+const migrationLockID int64 = 7_281_004
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// NewMigrator returns a migrator for the user service schema
+func NewMigrator(conn *sql.DB) (*db.Migrator, error) {
+	return db.NewMigratorFS(conn, migrationFiles, "migrations", db.MigratorConfig{
+		TableName: "user_schema_migrations",
+		LockID:    migrationLockID,
+	})
+}
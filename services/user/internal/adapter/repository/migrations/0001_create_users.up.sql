@@ -0,0 +1,9 @@
+CREATE TABLE users (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Emails are stored lower-cased, so a plain unique index rejects duplicates in any case
+CREATE UNIQUE INDEX users_email_idx ON users (email);
@@ -0,0 +1,6 @@
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX users_email_idx;
+CREATE UNIQUE INDEX users_email_idx ON users (email);
+
+ALTER TABLE users DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- A deleted user's email may be registered again
+DROP INDEX users_email_idx;
+CREATE UNIQUE INDEX users_email_idx ON users (email) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+-- Staff roles are granted by hand, e.g. UPDATE users SET role = 'admin' WHERE email = '...'
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'customer'
+    CHECK (role IN ('customer', 'support', 'admin'));
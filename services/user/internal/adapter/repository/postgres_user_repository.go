@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	stdErrors "errors"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+// uniqueViolation is the PostgreSQL error code for a unique index conflict
+const uniqueViolation = "23505"
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+// Save inserts user; an email another active user has fails with errors.ErrUserAlreadyExists
+func (r *PostgresUserRepository) Save(ctx context.Context, user *entity.User) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO users (id, name, email, password_hash, role) VALUES ($1, $2, $3, $4, $5)`,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.Role)
+
+	return uniqueEmailError(err)
+}
+
+func (r *PostgresUserRepository) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	return r.getOne(ctx, `SELECT id, name, email, password_hash, role FROM users WHERE id = $1 AND deleted_at IS NULL`, id)
+}
+
+func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return r.getOne(ctx, `SELECT id, name, email, password_hash, role FROM users WHERE email = $1 AND deleted_at IS NULL`, email)
+}
+
+func (r *PostgresUserRepository) GetAll(ctx context.Context) ([]*entity.User, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id, name, email, password_hash, role FROM users WHERE deleted_at IS NULL ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]*entity.User, 0)
+	for rows.Next() {
+		var user entity.User
+		if err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role); err != nil {
+			return nil, err
+		}
+		users = append(users, &user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) Update(ctx context.Context, user *entity.User) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET name = $2, email = $3 WHERE id = $1 AND deleted_at IS NULL`,
+		user.ID, user.Name, user.Email)
+	if err != nil {
+		return uniqueEmailError(err)
+	}
+	return affectedOne(result)
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, id string, deletedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE users SET deleted_at = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		id, deletedAt)
+	if err != nil {
+		return err
+	}
+	return affectedOne(result)
+}
+
+// uniqueEmailError maps a violation of the unique email index to errors.ErrUserAlreadyExists
+func uniqueEmailError(err error) error {
+	var pqErr *pq.Error
+	if stdErrors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return errors.ErrUserAlreadyExists
+	}
+	return err
+}
+
+// affectedOne reports errors.ErrUserNotFound when a write matched no active user
+func affectedOne(result sql.Result) error {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// getOne returns the single user query selects, or nil when there is none
+func (r *PostgresUserRepository) getOne(ctx context.Context, query string, arg string) (*entity.User, error) {
+	var user entity.User
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&user.ID, &user.Name, &user.Email, &user.PasswordHash, &user.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	stdErrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+func TestUniqueEmailError(t *testing.T) {
+	uniqueErr := &pq.Error{Code: uniqueViolation, Constraint: "users_email_idx"}
+	otherErr := &pq.Error{Code: "23502"}
+	plainErr := stdErrors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{"unique violation", uniqueErr, errors.ErrUserAlreadyExists},
+		{"wrapped unique violation", fmt.Errorf("insert user: %w", uniqueErr), errors.ErrUserAlreadyExists},
+		{"other constraint violation", otherErr, otherErr},
+		{"driver error", plainErr, plainErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := uniqueEmailError(tt.err); err != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+// authorizeUser lets the user with id, or an admin, act on that user. The
+// caller is the one pkgMiddleware.Authenticate stored in ctx; a context without
+// one is rejected, so the use cases fail closed when called without a caller.
+func authorizeUser(ctx context.Context, id string) error {
+	subject := pkgMiddleware.SubjectFromContext(ctx)
+	if subject == "" {
+		return errors.ErrAuthUnauthenticated
+	}
+	if subject == id || pkgMiddleware.HasRole(ctx, string(entity.RoleAdmin)) {
+		return nil
+	}
+	return errors.ErrAuthForbidden
+}
+
+// authorizeStaff lets support and admin callers act on any user
+func authorizeStaff(ctx context.Context) error {
+	if pkgMiddleware.SubjectFromContext(ctx) == "" {
+		return errors.ErrAuthUnauthenticated
+	}
+	if pkgMiddleware.HasRole(ctx, string(entity.RoleSupport)) || pkgMiddleware.HasRole(ctx, string(entity.RoleAdmin)) {
+		return nil
+	}
+	return errors.ErrAuthForbidden
+}
@@ -0,0 +1,100 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	pkgMiddleware "github.com/robrt95x/godops/pkg/middleware"
+	"github.com/robrt95x/godops/services/user/internal/adapter/auth"
+	"github.com/robrt95x/godops/services/user/internal/adapter/repository"
+	"github.com/robrt95x/godops/services/user/internal/application/usecase"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// callerContext carries an authenticated caller as pkgMiddleware.Authenticate stores it
+func callerContext(subject string, roles ...entity.Role) context.Context {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	return pkgMiddleware.WithIdentity(context.Background(), subject, names)
+}
+
+// newUserService returns a user service over a memory repository holding user-1
+func newUserService(t *testing.T) (*service.UserService, *repository.MemoryUserRepository) {
+	t.Helper()
+	repo := repository.NewMemoryUserRepository()
+	if err := repo.Save(context.Background(), &entity.User{ID: "user-1", Name: "Jane Doe", Email: "jane@example.com", Role: entity.RoleCustomer}); err != nil {
+		t.Fatalf("Failed to save user: %v", err)
+	}
+	return service.NewUserService(repo, auth.NewBcryptHasher(bcrypt.MinCost)), repo
+}
+
+func TestUserAuthorization(t *testing.T) {
+	forbidden := errors.ErrAuthForbidden
+	unauthenticated := errors.ErrAuthUnauthenticated
+
+	// Every case acts on user-1 in a fresh repository
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		get    error
+		update error
+		delete error
+		list   error
+	}{
+		{"the user themselves", callerContext("user-1", entity.RoleCustomer), nil, nil, nil, forbidden},
+		{"other customer", callerContext("user-2", entity.RoleCustomer), forbidden, forbidden, forbidden, forbidden},
+		{"support", callerContext("support-1", entity.RoleSupport), forbidden, forbidden, forbidden, nil},
+		{"admin", callerContext("admin-1", entity.RoleAdmin), nil, nil, nil, nil},
+		{"no caller", context.Background(), unauthenticated, unauthenticated, unauthenticated, unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := "Jane Roe"
+
+			userService, _ := newUserService(t)
+			if _, err := usecase.NewGetUserUseCase(userService).Execute(tt.ctx, "user-1"); err != tt.get {
+				t.Errorf("get: expected %v, got %v", tt.get, err)
+			}
+			if _, err := usecase.NewGetUserUseCase(userService).ExecuteGetAll(tt.ctx); err != tt.list {
+				t.Errorf("list: expected %v, got %v", tt.list, err)
+			}
+			if _, err := usecase.NewUpdateUserUseCase(userService).Execute(tt.ctx, "user-1", &name, nil); err != tt.update {
+				t.Errorf("update: expected %v, got %v", tt.update, err)
+			}
+			if err := usecase.NewDeleteUserUseCase(userService).Execute(tt.ctx, "user-1"); err != tt.delete {
+				t.Errorf("delete: expected %v, got %v", tt.delete, err)
+			}
+		})
+	}
+
+	t.Run("forbidden callers leave the user unchanged", func(t *testing.T) {
+		userService, repo := newUserService(t)
+		ctx := callerContext("user-2", entity.RoleCustomer)
+		name := "Mallory"
+
+		usecase.NewUpdateUserUseCase(userService).Execute(ctx, "user-1", &name, nil)
+		usecase.NewDeleteUserUseCase(userService).Execute(ctx, "user-1")
+
+		stored, _ := repo.GetByID(context.Background(), "user-1")
+		if stored == nil || stored.Name != "Jane Doe" {
+			t.Errorf("Expected user-1 to be unchanged, got %+v", stored)
+		}
+	})
+
+	t.Run("unknown users are forbidden rather than missing for other customers", func(t *testing.T) {
+		userService, _ := newUserService(t)
+
+		if _, err := usecase.NewGetUserUseCase(userService).Execute(callerContext("user-2", entity.RoleCustomer), "user-3"); err != forbidden {
+			t.Errorf("Expected %v, got %v", forbidden, err)
+		}
+		if _, err := usecase.NewGetUserUseCase(userService).Execute(callerContext("admin-1", entity.RoleAdmin), "user-3"); err != errors.ErrUserNotFound {
+			t.Errorf("Expected %v for an admin, got %v", errors.ErrUserNotFound, err)
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/robrt95x/godops/services/user/internal/domain/entity"
 	"github.com/robrt95x/godops/services/user/internal/domain/service"
 )
@@ -15,6 +17,6 @@ func NewCreateUserUseCase(userService *service.UserService) *CreateUserUseCase {
 	}
 }
 
-func (uc *CreateUserUseCase) Execute(name, email string) (*entity.User, error) {
-	return uc.userService.CreateUser(name, email)
+func (uc *CreateUserUseCase) Execute(ctx context.Context, name, email, password string) (*entity.User, error) {
+	return uc.userService.CreateUser(ctx, name, email, password)
 }
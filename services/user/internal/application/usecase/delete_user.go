@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+)
+
+type DeleteUserUseCase struct {
+	userService *service.UserService
+}
+
+func NewDeleteUserUseCase(userService *service.UserService) *DeleteUserUseCase {
+	return &DeleteUserUseCase{
+		userService: userService,
+	}
+}
+
+// Execute soft deletes the user for the user themselves or an admin
+func (uc *DeleteUserUseCase) Execute(ctx context.Context, id string) error {
+	if err := authorizeUser(ctx, id); err != nil {
+		return err
+	}
+	return uc.userService.DeleteUser(ctx, id)
+}
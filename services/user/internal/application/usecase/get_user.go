@@ -1,6 +1,8 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/robrt95x/godops/services/user/internal/domain/entity"
 	"github.com/robrt95x/godops/services/user/internal/domain/service"
 )
@@ -15,10 +17,18 @@ func NewGetUserUseCase(userService *service.UserService) *GetUserUseCase {
 	}
 }
 
-func (uc *GetUserUseCase) Execute(id string) (*entity.User, error) {
-	return uc.userService.GetUserByID(id)
+// Execute returns the user to the user themselves or an admin
+func (uc *GetUserUseCase) Execute(ctx context.Context, id string) (*entity.User, error) {
+	if err := authorizeUser(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.userService.GetUserByID(ctx, id)
 }
 
-func (uc *GetUserUseCase) ExecuteGetAll() ([]*entity.User, error) {
-	return uc.userService.GetAllUsers()
+// ExecuteGetAll lists every user to support and admin callers
+func (uc *GetUserUseCase) ExecuteGetAll(ctx context.Context) ([]*entity.User, error) {
+	if err := authorizeStaff(ctx); err != nil {
+		return nil, err
+	}
+	return uc.userService.GetAllUsers(ctx)
 }
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+)
+
+type LoginUseCase struct {
+	authService *service.AuthService
+}
+
+func NewLoginUseCase(authService *service.AuthService) *LoginUseCase {
+	return &LoginUseCase{
+		authService: authService,
+	}
+}
+
+func (uc *LoginUseCase) Execute(ctx context.Context, email, password string) (*entity.TokenPair, error) {
+	return uc.authService.Login(ctx, email, password)
+}
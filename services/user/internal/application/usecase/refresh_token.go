@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+)
+
+type RefreshTokenUseCase struct {
+	authService *service.AuthService
+}
+
+func NewRefreshTokenUseCase(authService *service.AuthService) *RefreshTokenUseCase {
+	return &RefreshTokenUseCase{
+		authService: authService,
+	}
+}
+
+func (uc *RefreshTokenUseCase) Execute(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	return uc.authService.Refresh(ctx, refreshToken)
+}
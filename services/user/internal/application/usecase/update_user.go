@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+)
+
+type UpdateUserUseCase struct {
+	userService *service.UserService
+}
+
+func NewUpdateUserUseCase(userService *service.UserService) *UpdateUserUseCase {
+	return &UpdateUserUseCase{
+		userService: userService,
+	}
+}
+
+// Execute changes only the fields that are non-nil; only the user themselves
+// or an admin may update a user
+func (uc *UpdateUserUseCase) Execute(ctx context.Context, id string, name, email *string) (*entity.User, error) {
+	if err := authorizeUser(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.userService.UpdateUser(ctx, id, name, email)
+}
@@ -1,20 +1,139 @@
 package config
 
 import (
+	"log"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Port string
+	// Storage Configuration
+	StorageType string `env:"STORAGE_TYPE" default:"postgres"`
+
+	// Database Configuration
+	DBHost        string `env:"DB_HOST" default:"localhost"`
+	DBPort        string `env:"DB_PORT" default:"5432"`
+	DBUser        string `env:"DB_USER" default:"user"`
+	DBPassword    string `env:"DB_PASSWORD" default:"pass"`
+	DBName        string `env:"DB_NAME" default:"godops"`
+	DBSSLMode     string `env:"DB_SSLMODE" default:"disable"`
+	DBAutoMigrate bool   `env:"DB_AUTO_MIGRATE" default:"true"`
+
+	// Server Configuration
+	ServerPort string `env:"SERVER_PORT" default:"8081"`
+	// RequestTimeout bounds each HTTP request, including its repository calls
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" default:"10s"`
+
+	// Authentication Configuration
+	// JWTPrivateKeyFile is a PEM RSA key; without one a key is generated on startup
+	JWTPrivateKeyFile  string        `env:"JWT_PRIVATE_KEY_FILE" default:""`
+	JWTIssuer          string        `env:"JWT_ISSUER" default:"godops-user-service"`
+	JWTAudience        string        `env:"JWT_AUDIENCE" default:"godops"`
+	JWTAccessTokenTTL  time.Duration `env:"JWT_ACCESS_TOKEN_TTL" default:"15m"`
+	JWTRefreshTokenTTL time.Duration `env:"JWT_REFRESH_TOKEN_TTL" default:"720h"`
+	BcryptCost         int           `env:"BCRYPT_COST" default:"12"`
+
+	// Logging Configuration
+	LogLevel      string `env:"LOG_LEVEL" default:"info"`
+	LogFormat     string `env:"LOG_FORMAT" default:"json"`
+	LogOutput     string `env:"LOG_OUTPUT" default:"console"`
+	LogFilePath   string `env:"LOG_FILE_PATH" default:"logs/user-service.log"`
+	LogMaxSize    int    `env:"LOG_MAX_SIZE" default:"100"`
+	LogMaxBackups int    `env:"LOG_MAX_BACKUPS" default:"5"`
+	LogMaxAge     int    `env:"LOG_MAX_AGE" default:"30"`
+	LogCompress   bool   `env:"LOG_COMPRESS" default:"true"`
+
+	// Environment
+	AppEnv string `env:"APP_ENV" default:"development"`
+}
+
+func Load() *Config {
+	// Try to load .env file (ignore error if file doesn't exist)
+	if err := godotenv.Load(); err != nil {
+		log.Printf("No .env file found, using environment variables and defaults")
+	}
+
+	config := &Config{
+		StorageType:   getEnv("STORAGE_TYPE", "postgres"),
+		DBHost:        getEnv("DB_HOST", "localhost"),
+		DBPort:        getEnv("DB_PORT", "5432"),
+		DBUser:        getEnv("DB_USER", "user"),
+		DBPassword:    getEnv("DB_PASSWORD", "pass"),
+		DBName:        getEnv("DB_NAME", "godops"),
+		DBSSLMode:     getEnv("DB_SSLMODE", "disable"),
+		DBAutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
+		// PORT is still honoured for deployments configured before SERVER_PORT
+		ServerPort:         getEnv("SERVER_PORT", getEnv("PORT", "8081")),
+		RequestTimeout:     getEnvDuration("REQUEST_TIMEOUT", 10*time.Second),
+		JWTPrivateKeyFile:  getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JWTIssuer:          getEnv("JWT_ISSUER", "godops-user-service"),
+		JWTAudience:        getEnv("JWT_AUDIENCE", "godops"),
+		JWTAccessTokenTTL:  getEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+		JWTRefreshTokenTTL: getEnvDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		BcryptCost:         getEnvInt("BCRYPT_COST", 12),
+		LogLevel:           getEnv("LOG_LEVEL", "info"),
+		LogFormat:          getEnv("LOG_FORMAT", "json"),
+		LogOutput:          getEnv("LOG_OUTPUT", "console"),
+		LogFilePath:        getEnv("LOG_FILE_PATH", "logs/user-service.log"),
+		LogMaxSize:         getEnvInt("LOG_MAX_SIZE", 100),
+		LogMaxBackups:      getEnvInt("LOG_MAX_BACKUPS", 5),
+		LogMaxAge:          getEnvInt("LOG_MAX_AGE", 30),
+		LogCompress:        getEnvBool("LOG_COMPRESS", true),
+		AppEnv:             getEnv("APP_ENV", "development"),
+	}
+
+	return config
+}
+
+func (c *Config) GetDatabaseURL() string {
+	return "postgres://" + c.DBUser + ":" + c.DBPassword + "@" + c.DBHost + ":" + c.DBPort + "/" + c.DBName + "?sslmode=" + c.DBSSLMode
 }
 
-func New() *Config {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+func (c *Config) IsProduction() bool {
+	return c.AppEnv == "production"
+}
+
+func (c *Config) IsMemoryStorage() bool {
+	return c.StorageType == "memory"
+}
+
+func (c *Config) IsPostgresStorage() bool {
+	return c.StorageType == "postgres"
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
 	}
-	
-	return &Config{
-		Port: port,
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationValue, err := time.ParseDuration(value); err == nil {
+			return durationValue
+		}
 	}
+	return defaultValue
 }
@@ -0,0 +1,22 @@
+package entity
+
+import "github.com/robrt95x/godops/services/user/internal/errors"
+
+// Password length bounds in bytes; bcrypt ignores everything past 72 bytes
+const (
+	MinPasswordLength = 8
+	MaxPasswordLength = 72
+)
+
+// ValidatePassword checks a plain-text password before it is hashed
+func ValidatePassword(password string) error {
+	if password == "" {
+		return errors.ErrValidationMissingPassword
+	}
+	
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return errors.ErrValidationInvalidPassword
+	}
+	
+	return nil
+}
@@ -1,21 +1,40 @@
 package entity
 
 import (
-	"errors"
 	"regexp"
 	"strings"
+	"time"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+// Role is the user's permission level, carried in their access tokens
+type Role string
+
+const (
+	RoleCustomer Role = "customer"
+	RoleSupport  Role = "support"
+	RoleAdmin    Role = "admin"
 )
 
 type User struct {
 	ID    string `json:"id"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
+	// PasswordHash is the hasher's encoding of the password; it is never serialized
+	PasswordHash string `json:"-"`
+	// Role is customer for everyone who registers; staff roles are granted in the database
+	Role Role `json:"role"`
+	// DeletedAt is set when the user is soft deleted; deleted users are no longer returned
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 func NewUser(name, email string) (*User, error) {
 	user := &User{
 		Name:  strings.TrimSpace(name),
 		Email: strings.TrimSpace(strings.ToLower(email)),
+		Role:  RoleCustomer,
 	}
 	
 	if err := user.Validate(); err != nil {
@@ -25,20 +44,37 @@ func NewUser(name, email string) (*User, error) {
 	return user, nil
 }
 
+// Update applies the non-nil fields, normalized as in NewUser, and re-validates the user
+func (u *User) Update(name, email *string) error {
+	if name != nil {
+		u.Name = strings.TrimSpace(*name)
+	}
+	if email != nil {
+		u.Email = strings.TrimSpace(strings.ToLower(*email))
+	}
+	
+	return u.Validate()
+}
+
+// IsDeleted reports whether the user has been soft deleted
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// Validate reports every invalid field of the user as a *ValidationErrors
 func (u *User) Validate() error {
+	var violations pkgErrors.ValidationErrors
 	if u.Name == "" {
-		return errors.New("name is required")
+		violations.Add("/name", errors.ErrValidationMissingName)
 	}
 	
 	if u.Email == "" {
-		return errors.New("email is required")
-	}
-	
-	if !isValidEmail(u.Email) {
-		return errors.New("invalid email format")
+		violations.Add("/email", errors.ErrValidationMissingEmail)
+	} else if !isValidEmail(u.Email) {
+		violations.Add("/email", errors.ErrValidationInvalidEmail)
 	}
 	
-	return nil
+	return violations.Err()
 }
 
 func isValidEmail(email string) bool {
@@ -0,0 +1,8 @@
+package port
+
+// PasswordHasher hashes passwords for storage and checks them at login
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Compare fails with errors.ErrAuthInvalidCredentials when password does not match hash
+	Compare(hash, password string) error
+}
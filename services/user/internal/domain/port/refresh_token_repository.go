@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+)
+
+// RefreshTokenRepository stores refresh tokens by the hash of their value
+type RefreshTokenRepository interface {
+	Save(ctx context.Context, token *entity.RefreshToken) error
+	// GetByHash returns nil when no token has tokenHash
+	GetByHash(ctx context.Context, tokenHash string) (*entity.RefreshToken, error)
+	// Rotate revokes the current token and saves next in one step; it fails with
+	// errors.ErrAuthInvalidRefreshToken when current was already revoked, so
+	// concurrent refreshes with the same token cannot both succeed
+	Rotate(ctx context.Context, currentID string, revokedAt time.Time, next *entity.RefreshToken) error
+	// RevokeFamily revokes every active token descended from the same login
+	RevokeFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+}
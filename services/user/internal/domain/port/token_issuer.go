@@ -0,0 +1,13 @@
+package port
+
+import (
+	"time"
+
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+)
+
+// AccessTokenIssuer signs the short-lived access tokens other services verify
+type AccessTokenIssuer interface {
+	// Issue returns a signed access token for user and the time it expires
+	Issue(user *entity.User, now time.Time) (string, time.Time, error)
+}
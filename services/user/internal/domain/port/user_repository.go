@@ -1,10 +1,23 @@
 package port
 
-import "github.com/robrt95x/godops/services/user/internal/domain/entity"
+import (
+	"context"
+	"time"
 
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+)
+
+// UserRepository stores users. Getters return a nil user when there is no
+// match; soft-deleted users are never returned and do not hold on to their email.
 type UserRepository interface {
-	Save(user *entity.User) error
-	GetByID(id string) (*entity.User, error)
-	GetByEmail(email string) (*entity.User, error)
-	GetAll() ([]*entity.User, error)
+	// Save stores a new user; an email another user has fails with errors.ErrUserAlreadyExists
+	Save(ctx context.Context, user *entity.User) error
+	GetByID(ctx context.Context, id string) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetAll(ctx context.Context) ([]*entity.User, error)
+	// Update persists a user's name and email, failing with errors.ErrUserNotFound
+	// or errors.ErrUserAlreadyExists like Save
+	Update(ctx context.Context, user *entity.User) error
+	// Delete soft deletes the user at deletedAt, failing with errors.ErrUserNotFound
+	Delete(ctx context.Context, id string, deletedAt time.Time) error
 }
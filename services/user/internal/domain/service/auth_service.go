@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	stdErrors "errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/port"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+// refreshTokenBytes is the entropy of a refresh token before encoding
+const refreshTokenBytes = 32
+
+// AuthService logs users in with their password and rotates refresh tokens
+type AuthService struct {
+	users      port.UserRepository
+	tokens     port.RefreshTokenRepository
+	hasher     port.PasswordHasher
+	issuer     port.AccessTokenIssuer
+	refreshTTL time.Duration
+	// dummyHash is compared against for unknown emails, so they take as long
+	// to reject as a wrong password and do not reveal which emails exist
+	dummyHash string
+}
+
+func NewAuthService(users port.UserRepository, tokens port.RefreshTokenRepository, hasher port.PasswordHasher, issuer port.AccessTokenIssuer, refreshTTL time.Duration) *AuthService {
+	dummyHash, _ := hasher.Hash(uuid.New().String())
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		hasher:     hasher,
+		issuer:     issuer,
+		refreshTTL: refreshTTL,
+		dummyHash:  dummyHash,
+	}
+}
+
+// Login checks the user's password and starts a new refresh token family
+func (s *AuthService) Login(ctx context.Context, email, password string) (*entity.TokenPair, error) {
+	email = strings.TrimSpace(strings.ToLower(email))
+	var violations pkgErrors.ValidationErrors
+	if email == "" {
+		violations.Add("/email", errors.ErrValidationMissingEmail)
+	}
+	if password == "" {
+		violations.Add("/password", errors.ErrValidationMissingPassword)
+	}
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+	
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	if user == nil || user.PasswordHash == "" {
+		s.hasher.Compare(s.dummyHash, password)
+		return nil, errors.ErrAuthInvalidCredentials
+	}
+	if err := s.hasher.Compare(user.PasswordHash, password); err != nil {
+		return nil, errors.ErrAuthInvalidCredentials
+	}
+	
+	now := time.Now().UTC()
+	refreshToken, value, err := s.newRefreshToken(user.ID, uuid.New().String(), now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.Save(ctx, refreshToken); err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	
+	return s.tokenPair(user, value, refreshToken, now)
+}
+
+// Refresh exchanges a refresh token for a new token pair, revoking it. A
+// revoked token that is presented again has leaked, so its whole family is
+// revoked and the user has to log in again.
+func (s *AuthService) Refresh(ctx context.Context, value string) (*entity.TokenPair, error) {
+	if value == "" {
+		return nil, errors.ErrAuthInvalidRefreshToken
+	}
+	
+	current, err := s.tokens.GetByHash(ctx, hashRefreshToken(value))
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	if current == nil {
+		return nil, errors.ErrAuthInvalidRefreshToken
+	}
+	
+	now := time.Now().UTC()
+	if current.IsRevoked() {
+		return nil, s.revokeFamily(ctx, current, now)
+	}
+	if current.IsExpired(now) {
+		return nil, errors.ErrAuthInvalidRefreshToken
+	}
+	
+	// Deleted users keep their tokens in storage but can no longer use them
+	user, err := s.users.GetByID(ctx, current.UserID)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	if user == nil {
+		return nil, errors.ErrAuthInvalidRefreshToken
+	}
+	
+	next, nextValue, err := s.newRefreshToken(user.ID, current.FamilyID, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.tokens.Rotate(ctx, current.ID, now, next); err != nil {
+		if stdErrors.Is(err, errors.ErrAuthInvalidRefreshToken) {
+			// Another request rotated the token first
+			return nil, s.revokeFamily(ctx, current, now)
+		}
+		return nil, repositoryError(ctx, err)
+	}
+	
+	return s.tokenPair(user, nextValue, next, now)
+}
+
+// revokeFamily ends every session descended from token's login
+func (s *AuthService) revokeFamily(ctx context.Context, token *entity.RefreshToken, now time.Time) error {
+	if err := s.tokens.RevokeFamily(ctx, token.FamilyID, now); err != nil {
+		return repositoryError(ctx, err)
+	}
+	return errors.ErrAuthInvalidRefreshToken
+}
+
+// newRefreshToken returns a token to store and the value handed to the client
+func (s *AuthService) newRefreshToken(userID, familyID string, now time.Time) (*entity.RefreshToken, string, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", errors.ErrSystemInternal
+	}
+	value := base64.RawURLEncoding.EncodeToString(raw)
+	
+	return &entity.RefreshToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(value),
+		ExpiresAt: now.Add(s.refreshTTL),
+		CreatedAt: now,
+	}, value, nil
+}
+
+func (s *AuthService) tokenPair(user *entity.User, refreshValue string, refreshToken *entity.RefreshToken, now time.Time) (*entity.TokenPair, error) {
+	accessToken, expiresAt, err := s.issuer.Issue(user, now)
+	if err != nil {
+		return nil, errors.ErrSystemInternal
+	}
+	
+	return &entity.TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(expiresAt.Sub(now).Seconds()),
+		RefreshToken:     refreshValue,
+		RefreshExpiresIn: int64(refreshToken.ExpiresAt.Sub(now).Seconds()),
+	}, nil
+}
+
+// hashRefreshToken is how refresh tokens are stored; the tokens are random,
+// so a fast unsalted hash is enough to keep a database leak from exposing them
+func hashRefreshToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
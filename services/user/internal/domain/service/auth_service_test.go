@@ -0,0 +1,299 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	stdErrors "errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/robrt95x/godops/services/user/internal/adapter/auth"
+	"github.com/robrt95x/godops/services/user/internal/adapter/repository"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+)
+
+var (
+	issuerOnce sync.Once
+	issuer     *auth.JWTIssuer
+)
+
+// testIssuer shares one issuer across tests, generating an RSA key is slow
+func testIssuer(t *testing.T) *auth.JWTIssuer {
+	t.Helper()
+	issuerOnce.Do(func() {
+		key, err := auth.GenerateRSAPrivateKey()
+		if err != nil {
+			t.Fatalf("Failed to generate signing key: %v", err)
+		}
+		issuer = auth.NewJWTIssuer(key, auth.JWTConfig{Issuer: "user-service", Audience: "godops", AccessTokenTTL: 15 * time.Minute})
+	})
+	return issuer
+}
+
+// recordingHasher records the hashes passwords are compared against
+type recordingHasher struct {
+	*auth.BcryptHasher
+	compared []string
+}
+
+func (h *recordingHasher) Compare(hash, password string) error {
+	h.compared = append(h.compared, hash)
+	return h.BcryptHasher.Compare(hash, password)
+}
+
+// losingTokenRepository rotates every token on behalf of another request just
+// before the service does, so the service always loses the rotation
+type losingTokenRepository struct {
+	*repository.MemoryRefreshTokenRepository
+	winner *entity.RefreshToken
+}
+
+func (r *losingTokenRepository) Rotate(ctx context.Context, currentID string, revokedAt time.Time, next *entity.RefreshToken) error {
+	winner := *next
+	winner.ID, winner.TokenHash = "winner", hashToken("winner")
+	if err := r.MemoryRefreshTokenRepository.Rotate(ctx, currentID, revokedAt, &winner); err != nil {
+		return err
+	}
+	r.winner = &winner
+	return r.MemoryRefreshTokenRepository.Rotate(ctx, currentID, revokedAt, next)
+}
+
+func hashToken(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+type authFixture struct {
+	users  *repository.MemoryUserRepository
+	tokens *repository.MemoryRefreshTokenRepository
+	hasher *recordingHasher
+	user   *entity.User
+}
+
+// newAuthFixture stores a user with testPassword in memory repositories
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+	f := &authFixture{
+		users:  repository.NewMemoryUserRepository(),
+		tokens: repository.NewMemoryRefreshTokenRepository(),
+		hasher: &recordingHasher{BcryptHasher: newTestHasher()},
+	}
+	f.user = createUser(t, service.NewUserService(f.users, f.hasher), "Jane Doe", "jane@example.com")
+	return f
+}
+
+func (f *authFixture) service(t *testing.T, refreshTTL time.Duration) *service.AuthService {
+	return service.NewAuthService(f.users, f.tokens, f.hasher, testIssuer(t), refreshTTL)
+}
+
+func (f *authFixture) login(t *testing.T, authService *service.AuthService) *entity.TokenPair {
+	t.Helper()
+	pair, err := authService.Login(context.Background(), f.user.Email, testPassword)
+	if err != nil {
+		t.Fatalf("Failed to log in: %v", err)
+	}
+	return pair
+}
+
+func TestAuthService_Login(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("returns a token pair", func(t *testing.T) {
+		f := newAuthFixture(t)
+
+		pair, err := f.service(t, time.Hour).Login(ctx, " JANE@example.com ", testPassword)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if pair.AccessToken == "" || pair.RefreshToken == "" || pair.TokenType != "Bearer" {
+			t.Errorf("Expected a bearer token pair, got %+v", pair)
+		}
+		if pair.ExpiresIn != int64((15*time.Minute).Seconds()) || pair.RefreshExpiresIn != int64(time.Hour.Seconds()) {
+			t.Errorf("Expected lifetimes of 900s and 3600s, got %ds and %ds", pair.ExpiresIn, pair.RefreshExpiresIn)
+		}
+
+		stored, _ := f.tokens.GetByHash(ctx, hashToken(pair.RefreshToken))
+		if stored == nil || stored.UserID != f.user.ID || stored.IsRevoked() {
+			t.Errorf("Expected only the hash of an active refresh token of %s to be stored, got %+v", f.user.ID, stored)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		email       string
+		password    string
+		expectedErr error
+		// usesDummyHash is whether the password is compared against the dummy hash
+		usesDummyHash bool
+	}{
+		{"wrong password", "jane@example.com", "wrong horse", errors.ErrAuthInvalidCredentials, false},
+		{"unknown email", "john@example.com", testPassword, errors.ErrAuthInvalidCredentials, true},
+		{"missing email", " ", testPassword, errors.ErrValidationMissingEmail, false},
+		{"missing password", "jane@example.com", "", errors.ErrValidationMissingPassword, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t)
+			authService := f.service(t, time.Hour)
+			f.hasher.compared = nil
+
+			pair, err := authService.Login(ctx, tt.email, tt.password)
+			if !stdErrors.Is(err, tt.expectedErr) || pair != nil {
+				t.Fatalf("Expected %v, got %v and %+v", tt.expectedErr, err, pair)
+			}
+
+			if tt.expectedErr != errors.ErrAuthInvalidCredentials {
+				if len(f.hasher.compared) != 0 {
+					t.Errorf("Expected no password comparison, got %d", len(f.hasher.compared))
+				}
+				return
+			}
+			// Unknown emails cost a comparison too, so timing does not reveal which emails exist
+			if len(f.hasher.compared) != 1 {
+				t.Fatalf("Expected one password comparison, got %d", len(f.hasher.compared))
+			}
+			if usedDummy := f.hasher.compared[0] != f.user.PasswordHash; usedDummy != tt.usesDummyHash {
+				t.Errorf("Expected dummy hash use to be %v", tt.usesDummyHash)
+			}
+			if f.hasher.compared[0] == "" {
+				t.Error("Expected a real hash to be compared against")
+			}
+		})
+	}
+
+	t.Run("deleted user", func(t *testing.T) {
+		f := newAuthFixture(t)
+		f.users.Delete(ctx, f.user.ID, time.Now())
+
+		if _, err := f.service(t, time.Hour).Login(ctx, f.user.Email, testPassword); err != errors.ErrAuthInvalidCredentials {
+			t.Errorf("Expected %v, got %v", errors.ErrAuthInvalidCredentials, err)
+		}
+	})
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		f := newAuthFixture(t)
+		authService := f.service(t, time.Hour)
+		first := f.login(t, authService)
+
+		second, err := authService.Refresh(ctx, first.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if second.RefreshToken == first.RefreshToken || second.AccessToken == "" {
+			t.Errorf("Expected a new token pair, got %+v", second)
+		}
+
+		previous, _ := f.tokens.GetByHash(ctx, hashToken(first.RefreshToken))
+		next, _ := f.tokens.GetByHash(ctx, hashToken(second.RefreshToken))
+		if !previous.IsRevoked() || next.IsRevoked() {
+			t.Errorf("Expected only the presented token to be revoked")
+		}
+		if next.FamilyID != previous.FamilyID {
+			t.Errorf("Expected the successor in family %s, got %s", previous.FamilyID, next.FamilyID)
+		}
+	})
+
+	t.Run("reuse of a rotated token revokes the family", func(t *testing.T) {
+		f := newAuthFixture(t)
+		authService := f.service(t, time.Hour)
+		first := f.login(t, authService)
+		second, err := authService.Refresh(ctx, first.RefreshToken)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		other := f.login(t, authService)
+
+		if _, err := authService.Refresh(ctx, first.RefreshToken); err != errors.ErrAuthInvalidRefreshToken {
+			t.Fatalf("Expected %v, got %v", errors.ErrAuthInvalidRefreshToken, err)
+		}
+		if _, err := authService.Refresh(ctx, second.RefreshToken); err != errors.ErrAuthInvalidRefreshToken {
+			t.Errorf("Expected the successor to be revoked, got %v", err)
+		}
+		// Other logins of the user are separate families
+		if _, err := authService.Refresh(ctx, other.RefreshToken); err != nil {
+			t.Errorf("Expected another family to be unaffected, got %v", err)
+		}
+	})
+
+	t.Run("losing a concurrent rotation revokes the family", func(t *testing.T) {
+		f := newAuthFixture(t)
+		losing := &losingTokenRepository{MemoryRefreshTokenRepository: f.tokens}
+		authService := service.NewAuthService(f.users, losing, f.hasher, testIssuer(t), time.Hour)
+		first := f.login(t, authService)
+
+		if _, err := authService.Refresh(ctx, first.RefreshToken); err != errors.ErrAuthInvalidRefreshToken {
+			t.Fatalf("Expected %v, got %v", errors.ErrAuthInvalidRefreshToken, err)
+		}
+		winner, _ := f.tokens.GetByHash(ctx, losing.winner.TokenHash)
+		if winner == nil || !winner.IsRevoked() {
+			t.Errorf("Expected the winning request's token to be revoked, got %+v", winner)
+		}
+	})
+
+	t.Run("concurrent refreshes with one token leave no usable successor", func(t *testing.T) {
+		f := newAuthFixture(t)
+		authService := f.service(t, time.Hour)
+		first := f.login(t, authService)
+
+		var wg sync.WaitGroup
+		pairs := make([]*entity.TokenPair, 5)
+		for i := range pairs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				pairs[i], _ = authService.Refresh(ctx, first.RefreshToken)
+			}(i)
+		}
+		wg.Wait()
+
+		for _, pair := range pairs {
+			if pair == nil {
+				continue
+			}
+			if _, err := authService.Refresh(ctx, pair.RefreshToken); err != errors.ErrAuthInvalidRefreshToken {
+				t.Errorf("Expected the successor to be revoked, got %v", err)
+			}
+		}
+	})
+
+	tests := []struct {
+		name       string
+		refreshTTL time.Duration
+		prepare    func(f *authFixture)
+	}{
+		{"expired token", time.Millisecond, func(f *authFixture) { time.Sleep(5 * time.Millisecond) }},
+		{"deleted user", time.Hour, func(f *authFixture) { f.users.Delete(context.Background(), f.user.ID, time.Now()) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newAuthFixture(t)
+			authService := f.service(t, tt.refreshTTL)
+			pair := f.login(t, authService)
+			tt.prepare(f)
+
+			if _, err := authService.Refresh(ctx, pair.RefreshToken); err != errors.ErrAuthInvalidRefreshToken {
+				t.Errorf("Expected %v, got %v", errors.ErrAuthInvalidRefreshToken, err)
+			}
+		})
+	}
+
+	for name, value := range map[string]string{"empty token": "", "unknown token": "not-a-token"} {
+		t.Run(name, func(t *testing.T) {
+			f := newAuthFixture(t)
+
+			if _, err := f.service(t, time.Hour).Refresh(ctx, value); err != errors.ErrAuthInvalidRefreshToken {
+				t.Errorf("Expected %v, got %v", errors.ErrAuthInvalidRefreshToken, err)
+			}
+		})
+	}
+}
@@ -1,63 +1,147 @@
 package service
 
 import (
-	"errors"
+	"context"
+	stdErrors "errors"
+	"time"
 
 	"github.com/google/uuid"
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
 	"github.com/robrt95x/godops/services/user/internal/domain/entity"
 	"github.com/robrt95x/godops/services/user/internal/domain/port"
+	"github.com/robrt95x/godops/services/user/internal/errors"
 )
 
 type UserService struct {
-	repo port.UserRepository
+	repo   port.UserRepository
+	hasher port.PasswordHasher
 }
 
-func NewUserService(repo port.UserRepository) *UserService {
+func NewUserService(repo port.UserRepository, hasher port.PasswordHasher) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:   repo,
+		hasher: hasher,
 	}
 }
 
-func (s *UserService) CreateUser(name, email string) (*entity.User, error) {
-	// Check if user already exists
-	if existingUser, _ := s.repo.GetByEmail(email); existingUser != nil {
-		return nil, errors.New("user with this email already exists")
+func (s *UserService) CreateUser(ctx context.Context, name, email, password string) (*entity.User, error) {
+	// Create new user, reporting an invalid password along with the other fields
+	var violations pkgErrors.ValidationErrors
+	user, err := entity.NewUser(name, email)
+	violations.Merge("", err)
+	violations.Add("/password", entity.ValidatePassword(password))
+	if err := violations.Err(); err != nil {
+		return nil, err
 	}
 	
-	// Create new user
-	user, err := entity.NewUser(name, email)
+	// Check if user already exists; the repository's unique email constraint
+	// still rejects a concurrent request that passes this check
+	existingUser, err := s.repo.GetByEmail(ctx, user.Email)
 	if err != nil {
-		return nil, err
+		return nil, repositoryError(ctx, err)
+	}
+	if existingUser != nil {
+		return nil, errors.ErrUserAlreadyExists
+	}
+	
+	// Hash the password only once the request is known to be valid, it is deliberately slow
+	user.PasswordHash, err = s.hasher.Hash(password)
+	if err != nil {
+		return nil, errors.ErrSystemInternal
 	}
 	
 	// Generate ID
 	user.ID = uuid.New().String()
 	
 	// Save user
-	if err := s.repo.Save(user); err != nil {
-		return nil, err
+	if err := s.repo.Save(ctx, user); err != nil {
+		if stdErrors.Is(err, errors.ErrUserAlreadyExists) {
+			return nil, errors.ErrUserAlreadyExists
+		}
+		return nil, repositoryError(ctx, err)
 	}
 	
 	return user, nil
 }
 
-func (s *UserService) GetUserByID(id string) (*entity.User, error) {
+func (s *UserService) GetUserByID(ctx context.Context, id string) (*entity.User, error) {
 	if id == "" {
-		return nil, errors.New("user ID is required")
+		return nil, errors.ErrValidationMissingUserID
 	}
 	
-	user, err := s.repo.GetByID(id)
+	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
-		return nil, err
+		return nil, repositoryError(ctx, err)
 	}
 	
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, errors.ErrUserNotFound
 	}
 	
 	return user, nil
 }
 
-func (s *UserService) GetAllUsers() ([]*entity.User, error) {
-	return s.repo.GetAll()
+func (s *UserService) GetAllUsers(ctx context.Context) ([]*entity.User, error) {
+	users, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, repositoryError(ctx, err)
+	}
+	return users, nil
+}
+
+// UpdateUser changes the fields that are set, re-validating the user and
+// keeping its email unique among active users
+func (s *UserService) UpdateUser(ctx context.Context, id string, name, email *string) (*entity.User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	
+	previousEmail := user.Email
+	if err := user.Update(name, email); err != nil {
+		return nil, err
+	}
+	
+	if user.Email != previousEmail {
+		existingUser, err := s.repo.GetByEmail(ctx, user.Email)
+		if err != nil {
+			return nil, repositoryError(ctx, err)
+		}
+		if existingUser != nil && existingUser.ID != user.ID {
+			return nil, errors.ErrUserAlreadyExists
+		}
+	}
+	
+	if err := s.repo.Update(ctx, user); err != nil {
+		if stdErrors.Is(err, errors.ErrUserNotFound) || stdErrors.Is(err, errors.ErrUserAlreadyExists) {
+			return nil, err
+		}
+		return nil, repositoryError(ctx, err)
+	}
+	
+	return user, nil
+}
+
+// DeleteUser soft deletes the user, which frees its email for a new registration
+func (s *UserService) DeleteUser(ctx context.Context, id string) error {
+	if id == "" {
+		return errors.ErrValidationMissingUserID
+	}
+	
+	if err := s.repo.Delete(ctx, id, time.Now().UTC()); err != nil {
+		if stdErrors.Is(err, errors.ErrUserNotFound) {
+			return err
+		}
+		return repositoryError(ctx, err)
+	}
+	return nil
+}
+
+// repositoryError maps a failed repository call to a catalog error,
+// reporting an exceeded request deadline as a timeout
+func repositoryError(ctx context.Context, err error) error {
+	if stdErrors.Is(err, context.DeadlineExceeded) || stdErrors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return errors.ErrSystemTimeout
+	}
+	return errors.ErrDatabaseQuery
 }
@@ -0,0 +1,220 @@
+package service_test
+
+import (
+	"context"
+	stdErrors "errors"
+	"sync"
+	"testing"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+	"github.com/robrt95x/godops/services/user/internal/adapter/auth"
+	"github.com/robrt95x/godops/services/user/internal/adapter/repository"
+	"github.com/robrt95x/godops/services/user/internal/domain/entity"
+	"github.com/robrt95x/godops/services/user/internal/domain/service"
+	"github.com/robrt95x/godops/services/user/internal/errors"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct horse"
+
+func newTestHasher() *auth.BcryptHasher {
+	return auth.NewBcryptHasher(bcrypt.MinCost)
+}
+
+// racingUserRepository misses the existing user on lookup, as a request that
+// races another registration of the same email does
+type racingUserRepository struct {
+	*repository.MemoryUserRepository
+}
+
+func (r *racingUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	return nil, nil
+}
+
+func createUser(t *testing.T, userService *service.UserService, name, email string) *entity.User {
+	t.Helper()
+	user, err := userService.CreateUser(context.Background(), name, email, testPassword)
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	return user
+}
+
+func TestUserService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("normalizes the user and hashes the password", func(t *testing.T) {
+		repo := repository.NewMemoryUserRepository()
+		hasher := newTestHasher()
+		user := createUser(t, service.NewUserService(repo, hasher), "  Jane Doe ", " Jane@Example.COM")
+
+		stored, _ := repo.GetByID(ctx, user.ID)
+		if stored == nil || stored.Name != "Jane Doe" || stored.Email != "jane@example.com" || stored.Role != entity.RoleCustomer {
+			t.Fatalf("Expected a normalized customer, got %+v", stored)
+		}
+		if stored.PasswordHash == testPassword || hasher.Compare(stored.PasswordHash, testPassword) != nil {
+			t.Error("Expected the password to be stored as a matching hash")
+		}
+	})
+
+	tests := []struct {
+		name        string
+		racing      bool
+		email       string
+		expectedErr error
+	}{
+		{"taken email", false, "jane@example.com", errors.ErrUserAlreadyExists},
+		{"taken email in another case", false, "JANE@example.com", errors.ErrUserAlreadyExists},
+		{"email taken by a racing registration", true, "jane@example.com", errors.ErrUserAlreadyExists},
+		{"free email", false, "john@example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryUserRepository()
+			createUser(t, service.NewUserService(repo, newTestHasher()), "Jane Doe", "jane@example.com")
+
+			userService := service.NewUserService(repo, newTestHasher())
+			if tt.racing {
+				userService = service.NewUserService(&racingUserRepository{repo}, newTestHasher())
+			}
+
+			if _, err := userService.CreateUser(ctx, "John Doe", tt.email, testPassword); err != tt.expectedErr {
+				t.Errorf("Expected %v, got %v", tt.expectedErr, err)
+			}
+		})
+	}
+
+	t.Run("lets only one of concurrent registrations of an email succeed", func(t *testing.T) {
+		userService := service.NewUserService(&racingUserRepository{repository.NewMemoryUserRepository()}, newTestHasher())
+
+		var wg sync.WaitGroup
+		errs := make([]error, 5)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = userService.CreateUser(ctx, "Jane Doe", "jane@example.com", testPassword)
+			}(i)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			switch err {
+			case nil:
+				created++
+			case errors.ErrUserAlreadyExists:
+			default:
+				t.Errorf("Expected %v, got %v", errors.ErrUserAlreadyExists, err)
+			}
+		}
+		if created != 1 {
+			t.Errorf("Expected exactly one user to be created, got %d", created)
+		}
+	})
+}
+
+func TestUserService_CreateUser_FieldErrors(t *testing.T) {
+	repo := repository.NewMemoryUserRepository()
+	userService := service.NewUserService(repo, newTestHasher())
+
+	_, err := userService.CreateUser(context.Background(), " ", "not-an-email", "short")
+
+	var violations *pkgErrors.ValidationErrors
+	if !stdErrors.As(err, &violations) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+
+	expected := []pkgErrors.FieldError{
+		{Pointer: "/name", Err: errors.ErrValidationMissingName},
+		{Pointer: "/email", Err: errors.ErrValidationInvalidEmail},
+		{Pointer: "/password", Err: errors.ErrValidationInvalidPassword},
+	}
+	if len(violations.Fields) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %v", len(expected), violations.Fields)
+	}
+	for i, field := range expected {
+		if violations.Fields[i] != field {
+			t.Errorf("Expected %s: %v, got %s: %v", field.Pointer, field.Err, violations.Fields[i].Pointer, violations.Fields[i].Err)
+		}
+	}
+	if users, _ := repo.GetAll(context.Background()); len(users) != 0 {
+		t.Errorf("Expected nothing stored, got %d users", len(users))
+	}
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name          string
+		newName       *string
+		newEmail      *string
+		expectedErr   error
+		expectedName  string
+		expectedEmail string
+	}{
+		{"name only", str(" Jane Roe "), nil, nil, "Jane Roe", "jane@example.com"},
+		{"email is normalized", nil, str(" Jane.Roe@Example.com"), nil, "Jane Doe", "jane.roe@example.com"},
+		{"own email in another case", nil, str("JANE@example.com"), nil, "Jane Doe", "jane@example.com"},
+		{"email of another user", nil, str("john@example.com"), errors.ErrUserAlreadyExists, "Jane Doe", "jane@example.com"},
+		{"invalid email", nil, str("jane"), errors.ErrValidationInvalidEmail, "Jane Doe", "jane@example.com"},
+		{"blank name", str("  "), nil, errors.ErrValidationMissingName, "Jane Doe", "jane@example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := repository.NewMemoryUserRepository()
+			userService := service.NewUserService(repo, newTestHasher())
+			jane := createUser(t, userService, "Jane Doe", "jane@example.com")
+			createUser(t, userService, "John Doe", "john@example.com")
+
+			_, err := userService.UpdateUser(ctx, jane.ID, tt.newName, tt.newEmail)
+			if !stdErrors.Is(err, tt.expectedErr) {
+				t.Fatalf("Expected %v, got %v", tt.expectedErr, err)
+			}
+
+			stored, _ := repo.GetByID(ctx, jane.ID)
+			if stored.Name != tt.expectedName || stored.Email != tt.expectedEmail {
+				t.Errorf("Expected %s <%s>, got %s <%s>", tt.expectedName, tt.expectedEmail, stored.Name, stored.Email)
+			}
+		})
+	}
+
+	t.Run("unknown user", func(t *testing.T) {
+		userService := service.NewUserService(repository.NewMemoryUserRepository(), newTestHasher())
+
+		if _, err := userService.UpdateUser(ctx, "user-404", str("Jane Roe"), nil); err != errors.ErrUserNotFound {
+			t.Errorf("Expected %v, got %v", errors.ErrUserNotFound, err)
+		}
+	})
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryUserRepository()
+	userService := service.NewUserService(repo, newTestHasher())
+	jane := createUser(t, userService, "Jane Doe", "jane@example.com")
+
+	if err := userService.DeleteUser(ctx, jane.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := userService.GetUserByID(ctx, jane.ID); err != errors.ErrUserNotFound {
+		t.Errorf("Expected a deleted user to be %v, got %v", errors.ErrUserNotFound, err)
+	}
+	if users, _ := userService.GetAllUsers(ctx); len(users) != 0 {
+		t.Errorf("Expected a deleted user to be hidden from the list, got %d users", len(users))
+	}
+	if err := userService.DeleteUser(ctx, jane.ID); err != errors.ErrUserNotFound {
+		t.Errorf("Expected deleting again to be %v, got %v", errors.ErrUserNotFound, err)
+	}
+
+	// The email is free for a new registration
+	again := createUser(t, userService, "Jane Doe", "jane@example.com")
+	if again.ID == jane.ID {
+		t.Error("Expected a new user")
+	}
+}
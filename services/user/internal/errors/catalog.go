@@ -0,0 +1,116 @@
+package errors
+
+import (
+	"errors"
+
+	pkgErrors "github.com/robrt95x/godops/pkg/errors"
+)
+
+// Error codes for standardized API responses
+const (
+	// User related errors
+	UserNotFound      = "USER_NOT_FOUND"
+	UserAlreadyExists = "USER_ALREADY_EXISTS"
+
+	// Authentication errors
+	AuthInvalidCredentials  = "AUTH_INVALID_CREDENTIALS"
+	AuthInvalidRefreshToken = "AUTH_INVALID_REFRESH_TOKEN"
+	AuthUnauthenticated     = pkgErrors.AuthUnauthenticated
+	AuthForbidden           = pkgErrors.AuthForbidden
+
+	// Validation errors
+	ValidationMissingUserID   = "VALIDATION_MISSING_USER_ID"
+	ValidationMissingName     = "VALIDATION_MISSING_NAME"
+	ValidationMissingEmail    = "VALIDATION_MISSING_EMAIL"
+	ValidationInvalidEmail    = "VALIDATION_INVALID_EMAIL"
+	ValidationMissingPassword = "VALIDATION_MISSING_PASSWORD"
+	ValidationInvalidPassword = "VALIDATION_INVALID_PASSWORD"
+	ValidationInvalidRequest  = "VALIDATION_INVALID_REQUEST"
+
+	// Database errors
+	DatabaseConnectionError  = "DATABASE_CONNECTION_ERROR"
+	DatabaseQueryError       = "DATABASE_QUERY_ERROR"
+	DatabaseTransactionError = "DATABASE_TRANSACTION_ERROR"
+
+	// System errors
+	SystemInternalError      = "SYSTEM_INTERNAL_ERROR"
+	SystemServiceUnavailable = "SYSTEM_SERVICE_UNAVAILABLE"
+	SystemTimeout            = "SYSTEM_TIMEOUT"
+)
+
+// Domain errors that map to error codes
+var (
+	ErrUserNotFound      = errors.New("user not found")
+	ErrUserAlreadyExists = errors.New("user with this email already exists")
+
+	ErrAuthInvalidCredentials  = errors.New("invalid email or password")
+	ErrAuthInvalidRefreshToken = errors.New("refresh token is invalid, expired or revoked")
+	ErrAuthUnauthenticated     = errors.New("caller is not authenticated")
+	ErrAuthForbidden           = errors.New("caller is not allowed to perform this action")
+
+	ErrValidationMissingUserID   = errors.New("user ID is required")
+	ErrValidationMissingName     = errors.New("name is required")
+	ErrValidationMissingEmail    = errors.New("email is required")
+	ErrValidationInvalidEmail    = errors.New("invalid email format")
+	ErrValidationMissingPassword = errors.New("password is required")
+	ErrValidationInvalidPassword = errors.New("password must be 8 to 72 bytes long")
+	ErrValidationInvalidRequest  = errors.New("invalid request format")
+
+	ErrDatabaseConnection  = errors.New("database connection failed")
+	ErrDatabaseQuery       = errors.New("database query failed")
+	ErrDatabaseTransaction = errors.New("database transaction failed")
+
+	ErrSystemInternal           = errors.New("internal system error")
+	ErrSystemServiceUnavailable = errors.New("service temporarily unavailable")
+	ErrSystemTimeout            = errors.New("request timeout")
+)
+
+// Catalog resolves domain errors to API error responses; errors wrapped with
+// %w resolve like the error they wrap
+var Catalog = pkgErrors.NewRegistry(
+	pkgErrors.Entry{Code: SystemInternalError, Message: "An unexpected error occurred", Meta: pkgErrors.Internal},
+
+	pkgErrors.Entry{Err: ErrUserNotFound, Code: UserNotFound, Message: "The requested user could not be found", Meta: pkgErrors.NotFound},
+	pkgErrors.Entry{Err: ErrUserAlreadyExists, Code: UserAlreadyExists, Message: "A user with this email already exists", Meta: pkgErrors.Conflict},
+
+	pkgErrors.Entry{Err: ErrAuthInvalidCredentials, Code: AuthInvalidCredentials, Message: "Email or password is incorrect", Meta: pkgErrors.Unauthorized},
+	pkgErrors.Entry{Err: ErrAuthInvalidRefreshToken, Code: AuthInvalidRefreshToken, Message: "Refresh token is invalid, expired or revoked", Meta: pkgErrors.Unauthorized},
+	pkgErrors.Entry{Err: ErrAuthUnauthenticated, Code: AuthUnauthenticated, Message: "A valid bearer token is required", Meta: pkgErrors.Unauthorized},
+	pkgErrors.Entry{Err: ErrAuthForbidden, Code: AuthForbidden, Message: "You are not allowed to perform this action", Meta: pkgErrors.Forbidden},
+
+	pkgErrors.Entry{Err: ErrValidationMissingUserID, Code: ValidationMissingUserID, Message: "User ID is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingName, Code: ValidationMissingName, Message: "Name is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingEmail, Code: ValidationMissingEmail, Message: "Email is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidEmail, Code: ValidationInvalidEmail, Message: "Email format is invalid", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationMissingPassword, Code: ValidationMissingPassword, Message: "Password is required", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidPassword, Code: ValidationInvalidPassword, Message: "Password must be 8 to 72 bytes long", Meta: pkgErrors.BadRequest},
+	pkgErrors.Entry{Err: ErrValidationInvalidRequest, Code: ValidationInvalidRequest, Message: "Invalid request format", Meta: pkgErrors.BadRequest},
+
+	pkgErrors.Entry{Err: ErrDatabaseConnection, Code: DatabaseConnectionError, Message: "Database connection failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseQuery, Code: DatabaseQueryError, Message: "Database query failed", Meta: pkgErrors.DatabaseFailure},
+	pkgErrors.Entry{Err: ErrDatabaseTransaction, Code: DatabaseTransactionError, Message: "Database transaction failed", Meta: pkgErrors.DatabaseFailure},
+
+	pkgErrors.Entry{Err: ErrSystemInternal, Code: SystemInternalError, Message: "An internal error occurred", Meta: pkgErrors.Internal},
+	pkgErrors.Entry{Err: ErrSystemServiceUnavailable, Code: SystemServiceUnavailable, Message: "Service is temporarily unavailable", Meta: pkgErrors.Unavailable},
+	pkgErrors.Entry{Err: ErrSystemTimeout, Code: SystemTimeout, Message: "Request timeout", Meta: pkgErrors.Timeout},
+)
+
+// GetErrorInfo returns the ErrorInfo for a given error
+func GetErrorInfo(err error) pkgErrors.ErrorInfo {
+	return Catalog.GetErrorInfo(err)
+}
+
+// IsValidationError checks if the error is a validation error
+func IsValidationError(err error) bool {
+	return Catalog.IsValidationError(err)
+}
+
+// IsDatabaseError checks if the error is a database error
+func IsDatabaseError(err error) bool {
+	return Catalog.IsDatabaseError(err)
+}
+
+// NewUserErrorCatalog returns the catalog the HTTP error handler reports errors with
+func NewUserErrorCatalog() pkgErrors.ErrorCatalog {
+	return Catalog
+}